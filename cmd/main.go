@@ -15,14 +15,24 @@
 package main
 
 import (
+	"bufio"
+	"bytes"
+	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
 
 	"github.com/Adembc/lazyssh/internal/adapters/data/ssh_config_file"
+	"github.com/Adembc/lazyssh/internal/config"
 	"github.com/Adembc/lazyssh/internal/logger"
 
 	"github.com/Adembc/lazyssh/internal/adapters/ui"
+	"github.com/Adembc/lazyssh/internal/core/domain"
+	"github.com/Adembc/lazyssh/internal/core/ports"
 	"github.com/Adembc/lazyssh/internal/core/services"
 	"github.com/spf13/cobra"
 )
@@ -50,10 +60,23 @@ func main() {
 	}
 	sshConfigFile := filepath.Join(home, ".ssh", "config")
 	metaDataFile := filepath.Join(home, ".lazyssh", "metadata.json")
+	appConfigFile := filepath.Join(home, ".lazyssh", "config.json")
 
-	serverRepo := ssh_config_file.NewRepository(log, sshConfigFile, metaDataFile)
-	serverService := services.NewServerService(log, serverRepo)
-	tui := ui.NewTUI(log, serverService, version, gitCommit)
+	cfg, err := config.Load(appConfigFile)
+	if err != nil {
+		log.Warnw("failed to load config, using defaults", "path", appConfigFile, "error", err)
+	}
+	if cfg.SessionLogDir == "" {
+		cfg.SessionLogDir = filepath.Join(home, ".lazyssh", "logs")
+	}
+	if cfg.PingTimeoutMS > 0 && cfg.PingTimeoutMS < config.MinPingTimeoutMS {
+		log.Warnw("ping_timeout_ms is below the minimum, raising it", "value", cfg.PingTimeoutMS, "minimum", config.MinPingTimeoutMS)
+	}
+
+	serverRepo := ssh_config_file.NewRepository(log, sshConfigFile, metaDataFile, cfg)
+	remoteInventory := services.NewRemoteInventory(log, cfg, filepath.Join(home, ".lazyssh", "remote_inventory.yaml"))
+	serverService := services.NewServerService(log, serverRepo, remoteInventory, cfg)
+	tui := ui.NewTUI(log, serverService, cfg, version, gitCommit)
 
 	rootCmd := &cobra.Command{
 		Use:   ui.AppName,
@@ -63,9 +86,472 @@ func main() {
 		},
 	}
 	rootCmd.SilenceUsage = true
+	rootCmd.SilenceErrors = true
+	rootCmd.AddCommand(newAddCmd(serverService), newRemoveCmd(serverService), newPingCmd(serverService),
+		newExportCmd(serverService), newImportCmd(serverService), newDiffCmd(serverService),
+		newApplyCmd(serverService), newAuditCmd(serverService), newLogCmd(serverService))
 
 	if err := rootCmd.Execute(); err != nil {
 		_, _ = fmt.Fprintln(os.Stderr, err)
 		os.Exit(1)
 	}
 }
+
+// newAddCmd returns the non-interactive "add" subcommand used by provisioning scripts.
+func newAddCmd(serverService ports.ServerService) *cobra.Command {
+	var server domain.Server
+	cmd := &cobra.Command{
+		Use:     "add",
+		Short:   "Add a server to the SSH config without opening the TUI",
+		Example: "  lazyssh add --alias web1 --host 10.0.0.5 --user ubuntu --key ~/.ssh/id_ed25519 --tag prod",
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := serverService.AddServer(server); err != nil {
+				return fmt.Errorf("failed to add %s: %w", server.Alias, err)
+			}
+			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Added %s\n", server.Alias)
+			return nil
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.StringVar(&server.Alias, "alias", "", "Host alias (required)")
+	flags.StringVar(&server.Host, "host", "", "HostName or IP address (required)")
+	flags.StringVar(&server.User, "user", "", "Login user")
+	flags.IntVar(&server.Port, "port", 22, "SSH port")
+	flags.StringSliceVar(&server.IdentityFiles, "key", nil, "Identity file; repeat or comma-separate for several")
+	flags.StringSliceVar(&server.Tags, "tag", nil, "Tag; repeat or comma-separate for several")
+	_ = cmd.MarkFlagRequired("alias")
+	_ = cmd.MarkFlagRequired("host")
+	return cmd
+}
+
+// newRemoveCmd returns the non-interactive "remove" subcommand. It asks for confirmation
+// on stdin unless --yes is given.
+func newRemoveCmd(serverService ports.ServerService) *cobra.Command {
+	var yes bool
+	cmd := &cobra.Command{
+		Use:   "remove <alias>",
+		Short: "Remove a server from the SSH config without opening the TUI",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			server, err := findServer(serverService, args[0])
+			if err != nil {
+				return err
+			}
+			if server.ReadOnly {
+				return fmt.Errorf("%s is %w; cannot remove", server.Alias, domain.ErrReadOnly)
+			}
+			if !yes {
+				_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Remove %s (%s)? [y/N] ", server.Alias, server.Host)
+				answer, _ := bufio.NewReader(cmd.InOrStdin()).ReadString('\n')
+				if a := strings.ToLower(strings.TrimSpace(answer)); a != "y" && a != "yes" {
+					return fmt.Errorf("aborted; pass --yes to skip confirmation")
+				}
+			}
+			if err := serverService.DeleteServer(server); err != nil {
+				return fmt.Errorf("failed to remove %s: %w", server.Alias, err)
+			}
+			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Removed %s\n", server.Alias)
+			return nil
+		},
+	}
+	cmd.Flags().BoolVarP(&yes, "yes", "y", false, "Skip the confirmation prompt")
+	return cmd
+}
+
+// newExportCmd returns the "export" subcommand, which writes the server inventory to stdout
+// or to --output.
+func newExportCmd(serverService ports.ServerService) *cobra.Command {
+	var format, output string
+	cmd := &cobra.Command{
+		Use:     "export",
+		Short:   "Export all servers with their tags and pins as YAML or JSON",
+		Example: "  lazyssh export --output servers.yaml",
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if output == "" {
+				return serverService.ExportInventory(cmd.OutOrStdout(), format)
+			}
+			var buf bytes.Buffer
+			if err := serverService.ExportInventory(&buf, format); err != nil {
+				return err
+			}
+			if err := os.WriteFile(output, buf.Bytes(), 0o600); err != nil {
+				return fmt.Errorf("failed to write %s: %w", output, err)
+			}
+			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Exported to %s\n", output)
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&format, "format", services.InventoryFormatYAML, "Output format: yaml or json")
+	cmd.Flags().StringVarP(&output, "output", "o", "", "Write to this file instead of stdout")
+	return cmd
+}
+
+// newImportCmd returns the "import" subcommand, which adds or updates the servers listed in
+// an inventory file ("-" reads stdin).
+func newImportCmd(serverService ports.ServerService) *cobra.Command {
+	var format string
+	cmd := &cobra.Command{
+		Use:     "import <file>",
+		Short:   "Add or update servers from an inventory written by export",
+		Example: "  lazyssh import servers.yaml",
+		Args:    cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			in := cmd.InOrStdin()
+			if args[0] != "-" {
+				f, err := os.Open(args[0])
+				if err != nil {
+					return fmt.Errorf("failed to open %s: %w", args[0], err)
+				}
+				defer func() { _ = f.Close() }()
+				in = f
+				if !cmd.Flags().Changed("format") {
+					format = services.InventoryFormatForPath(args[0])
+				}
+			}
+			if err := serverService.ImportInventory(in, format); err != nil {
+				return fmt.Errorf("failed to import %s: %w", args[0], err)
+			}
+			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Imported %s\n", args[0])
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&format, "format", services.InventoryFormatYAML, "Input format: yaml or json (default from the file extension)")
+	return cmd
+}
+
+// newDiffCmd returns the "diff" subcommand, which shows what applying an inventory would
+// change without writing anything.
+func newDiffCmd(serverService ports.ServerService) *cobra.Command {
+	var exitCode bool
+	cmd := &cobra.Command{
+		Use:     "diff <inventory>",
+		Short:   "Show which servers an inventory would add, change or leave unlisted",
+		Example: "  lazyssh diff servers.yaml",
+		Args:    cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			report, err := serverService.DiffInventory(args[0])
+			if err != nil {
+				return fmt.Errorf("failed to diff %s: %w", args[0], err)
+			}
+			printDiffReport(cmd.OutOrStdout(), report)
+			if exitCode && !report.Empty() {
+				return fmt.Errorf("config differs from %s", args[0])
+			}
+			return nil
+		},
+	}
+	cmd.Flags().BoolVar(&exitCode, "exit-code", false, "Exit with status 1 when there are differences")
+	return cmd
+}
+
+// newApplyCmd returns the "apply" subcommand. It only previews the changes unless --confirm
+// is given, and deletes servers missing from the inventory only with --prune.
+func newApplyCmd(serverService ports.ServerService) *cobra.Command {
+	var prune, confirm bool
+	cmd := &cobra.Command{
+		Use:     "apply <inventory>",
+		Short:   "Reconcile the SSH config with an inventory (dry run unless --confirm)",
+		Example: "  lazyssh apply servers.yaml --prune --confirm",
+		Args:    cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			w := cmd.OutOrStdout()
+			if !confirm {
+				report, err := serverService.DiffInventory(args[0])
+				if err != nil {
+					return fmt.Errorf("failed to diff %s: %w", args[0], err)
+				}
+				kept := len(report.Removed)
+				if !prune {
+					report.Removed = nil
+				}
+				printDiffReport(w, report)
+				if !prune && kept > 0 {
+					_, _ = fmt.Fprintf(w, "%d server(s) not in the inventory will be kept; pass --prune to delete them\n", kept)
+				}
+				if !report.Empty() {
+					_, _ = fmt.Fprintln(w, "Dry run; pass --confirm to apply")
+				}
+				return nil
+			}
+
+			report, err := serverService.ApplyInventory(args[0], prune)
+			if err != nil {
+				return fmt.Errorf("failed to apply %s: %w", args[0], err)
+			}
+			printDiffReport(w, report)
+			_, _ = fmt.Fprintf(w, "Added %d, updated %d, removed %d\n", len(report.Added), len(report.Changed), len(report.Removed))
+			return nil
+		},
+	}
+	cmd.Flags().BoolVar(&prune, "prune", false, "Delete servers that are not in the inventory")
+	cmd.Flags().BoolVar(&confirm, "confirm", false, "Apply the changes instead of only showing them")
+	return cmd
+}
+
+// printDiffReport writes report as +/~/- lines, one server per line followed by its changes.
+func printDiffReport(w io.Writer, report domain.DiffReport) {
+	if report.Empty() {
+		_, _ = fmt.Fprintln(w, "No differences")
+		return
+	}
+	for _, alias := range report.Added {
+		_, _ = fmt.Fprintf(w, "+ %s\n", alias)
+	}
+	for _, change := range report.Changed {
+		_, _ = fmt.Fprintf(w, "~ %s\n", change.Alias)
+		for _, line := range change.Changes {
+			_, _ = fmt.Fprintf(w, "    %s\n", line)
+		}
+	}
+	for _, alias := range report.Removed {
+		_, _ = fmt.Fprintf(w, "- %s (not in inventory)\n", alias)
+	}
+}
+
+// findServer returns the server whose alias matches exactly.
+func findServer(serverService ports.ServerService, alias string) (domain.Server, error) {
+	servers, err := serverService.ListServers("")
+	if err != nil {
+		return domain.Server{}, fmt.Errorf("failed to list servers: %w", err)
+	}
+	for _, s := range servers {
+		if s.Alias == alias {
+			return s, nil
+		}
+	}
+	return domain.Server{}, fmt.Errorf("server %q not found", alias)
+}
+
+// newLogCmd returns the "log" subcommand, which prints recent adds, edits, renames and
+// deletes made through lazyssh, newest first.
+func newLogCmd(serverService ports.ServerService) *cobra.Command {
+	var limit int
+	cmd := &cobra.Command{
+		Use:   "log [alias]",
+		Short: "Show recent changes lazyssh made to the SSH config",
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			alias := ""
+			if len(args) == 1 {
+				alias = args[0]
+			}
+			changes, err := serverService.RecentChanges(alias, limit)
+			if err != nil {
+				return fmt.Errorf("failed to read change log: %w", err)
+			}
+			w := cmd.OutOrStdout()
+			if len(changes) == 0 {
+				_, _ = fmt.Fprintln(w, "No changes recorded")
+				return nil
+			}
+			for _, c := range changes {
+				_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", c.Time.Local().Format("2006-01-02 15:04:05"), c.Action, c.Alias, c.Detail)
+			}
+			return nil
+		},
+	}
+	cmd.Flags().IntVarP(&limit, "limit", "n", 20, "Number of entries to show (0 for all)")
+	return cmd
+}
+
+// auditOutput is the JSON shape printed by "audit --json" for each finding.
+type auditOutput struct {
+	Alias    string `json:"alias"`
+	Check    string `json:"check"`
+	Severity string `json:"severity"`
+	Message  string `json:"message"`
+}
+
+// newAuditCmd returns the "audit" subcommand, which reports risky SSH settings. It exits
+// non-zero when anything is found so it can gate CI jobs.
+func newAuditCmd(serverService ports.ServerService) *cobra.Command {
+	var asJSON bool
+	cmd := &cobra.Command{
+		Use:   "audit",
+		Short: "Report insecure settings in the SSH config",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			findings, err := serverService.Audit()
+			if err != nil {
+				return fmt.Errorf("failed to audit servers: %w", err)
+			}
+
+			w := cmd.OutOrStdout()
+			if asJSON {
+				out := make([]auditOutput, 0, len(findings))
+				for _, f := range findings {
+					out = append(out, auditOutput{Alias: f.Alias, Check: f.Check, Severity: string(f.Severity), Message: f.Message})
+				}
+				enc := json.NewEncoder(w)
+				enc.SetIndent("", "  ")
+				if err := enc.Encode(out); err != nil {
+					return fmt.Errorf("failed to encode findings: %w", err)
+				}
+			} else {
+				if len(findings) == 0 {
+					_, _ = fmt.Fprintln(w, "No insecure settings found")
+				}
+				for _, f := range findings {
+					_, _ = fmt.Fprintf(w, "%s\t%s\t%s\n", f.Alias, f.Severity, f.Message)
+				}
+			}
+
+			if len(findings) > 0 {
+				return fmt.Errorf("%d finding(s)", len(findings))
+			}
+			return nil
+		},
+	}
+	cmd.Flags().BoolVar(&asJSON, "json", false, "Print findings as JSON")
+	return cmd
+}
+
+// pingOutput is the JSON shape printed by "ping --json" for each server.
+type pingOutput struct {
+	Alias     string  `json:"alias"`
+	Up        bool    `json:"up"`
+	LatencyMS float64 `json:"latency_ms"`
+	Via       string  `json:"via,omitempty"`
+	Error     string  `json:"error,omitempty"`
+	// RequiresVPN is set for servers marked as VPN-only; when they are unreachable they
+	// are not counted as down.
+	RequiresVPN bool `json:"requires_vpn,omitempty"`
+}
+
+// Output formats of the "ping" subcommand.
+const (
+	pingFormatText       = "text"
+	pingFormatJSON       = "json"
+	pingFormatPrometheus = "prometheus"
+)
+
+// newPingCmd returns the "ping" subcommand. It exits non-zero when any pinged server is down;
+// unreachable servers marked as requiring a VPN are reported as "vpn?" and do not count.
+// The Prometheus format exits zero, since down hosts are part of the metrics it writes.
+func newPingCmd(serverService ports.ServerService) *cobra.Command {
+	var all, asJSON bool
+	var format string
+	cmd := &cobra.Command{
+		Use:   "ping [alias]",
+		Short: "Check whether servers accept SSH connections, through their ProxyJump if set",
+		Args: func(cmd *cobra.Command, args []string) error {
+			if all && len(args) > 0 {
+				return fmt.Errorf("pass either an alias or --all, not both")
+			}
+			if !all && len(args) != 1 {
+				return fmt.Errorf("pass an alias or --all")
+			}
+			if asJSON {
+				format = pingFormatJSON
+			}
+			switch format {
+			case pingFormatText, pingFormatJSON, pingFormatPrometheus:
+				return nil
+			}
+			return fmt.Errorf("unknown format %q: use text, json or prometheus", format)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var servers []domain.Server
+			if all {
+				list, err := serverService.ListServers("")
+				if err != nil {
+					return fmt.Errorf("failed to list servers: %w", err)
+				}
+				servers = list
+			} else {
+				server, err := findServer(serverService, args[0])
+				if err != nil {
+					return err
+				}
+				servers = []domain.Server{server}
+			}
+
+			results := serverService.PingAll(servers)
+			out := make([]pingOutput, 0, len(servers))
+			down := 0
+			for _, s := range servers {
+				res := results[s.Alias]
+				o := pingOutput{Alias: s.Alias, Up: res.Up, LatencyMS: float64(res.Latency.Microseconds()) / 1000, Via: res.Via, RequiresVPN: s.RequiresVPN}
+				if res.Err != nil {
+					o.Error = res.Err.Error()
+				}
+				if !res.Up && !s.RequiresVPN {
+					down++
+				}
+				out = append(out, o)
+			}
+			sort.Slice(out, func(i, j int) bool { return out[i].Alias < out[j].Alias })
+
+			w := cmd.OutOrStdout()
+			switch format {
+			case pingFormatPrometheus:
+				if err := writePingMetrics(w, out); err != nil {
+					return fmt.Errorf("failed to write metrics: %w", err)
+				}
+				return nil
+			case pingFormatJSON:
+				enc := json.NewEncoder(w)
+				enc.SetIndent("", "  ")
+				if err := enc.Encode(out); err != nil {
+					return fmt.Errorf("failed to encode results: %w", err)
+				}
+			default:
+				for _, o := range out {
+					via := ""
+					if o.Via != "" {
+						via = "\tvia " + o.Via
+					}
+					switch {
+					case o.Up:
+						_, _ = fmt.Fprintf(w, "%s\tup\t%.1fms%s\n", o.Alias, o.LatencyMS, via)
+					case o.RequiresVPN:
+						_, _ = fmt.Fprintf(w, "%s\tvpn?\t%s%s\n", o.Alias, o.Error, via)
+					default:
+						_, _ = fmt.Fprintf(w, "%s\tdown\t%s%s\n", o.Alias, o.Error, via)
+					}
+				}
+			}
+
+			if down > 0 {
+				return fmt.Errorf("%d of %d server(s) down", down, len(out))
+			}
+			return nil
+		},
+	}
+	cmd.Flags().BoolVar(&all, "all", false, "Ping every server in the SSH config")
+	cmd.Flags().BoolVar(&asJSON, "json", false, "Print results as JSON (same as --format json)")
+	cmd.Flags().StringVar(&format, "format", pingFormatText, "Output format: text, json or prometheus (node_exporter textfile)")
+	return cmd
+}
+
+// writePingMetrics writes ping results in the Prometheus text exposition format, for the
+// node_exporter textfile collector. Latency is only reported for hosts that answered.
+func writePingMetrics(w io.Writer, out []pingOutput) error {
+	var b strings.Builder
+	b.WriteString("# HELP lazyssh_host_up Whether the host accepted an SSH connection (1) or not (0).\n")
+	b.WriteString("# TYPE lazyssh_host_up gauge\n")
+	for _, o := range out {
+		up := 0
+		if o.Up {
+			up = 1
+		}
+		fmt.Fprintf(&b, "lazyssh_host_up{alias=\"%s\"} %d\n", promLabelValue(o.Alias), up)
+	}
+	b.WriteString("# HELP lazyssh_host_latency_ms Time to connect to the host's SSH port, in milliseconds.\n")
+	b.WriteString("# TYPE lazyssh_host_latency_ms gauge\n")
+	for _, o := range out {
+		if o.Up {
+			fmt.Fprintf(&b, "lazyssh_host_latency_ms{alias=\"%s\"} %s\n", promLabelValue(o.Alias), strconv.FormatFloat(o.LatencyMS, 'f', -1, 64))
+		}
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// promLabelValue escapes a Prometheus label value: backslash, double quote and newline.
+func promLabelValue(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(s)
+}
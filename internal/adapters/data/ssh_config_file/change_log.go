@@ -0,0 +1,154 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ssh_config_file
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/Adembc/lazyssh/internal/core/domain"
+)
+
+// changeLogFile is the append-only log of server edits, kept next to the metadata.
+const changeLogFile = "changes.log"
+
+// logChange appends an entry to the change log. Failures are logged and otherwise ignored:
+// the config change itself has already been saved.
+func (r *Repository) logChange(action, alias, detail string) {
+	if r.changeLogPath == "" {
+		return
+	}
+	clean := strings.NewReplacer("\t", " ", "\n", " ", "\r", " ")
+	line := strings.Join([]string{time.Now().UTC().Format(time.RFC3339), action, clean.Replace(alias), clean.Replace(detail)}, "\t") + "\n"
+
+	f, err := os.OpenFile(r.changeLogPath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		r.logger.Warnw("failed to open change log", "path", r.changeLogPath, "error", err)
+		return
+	}
+	defer func() { _ = f.Close() }()
+	if _, err := f.WriteString(line); err != nil {
+		r.logger.Warnw("failed to write change log", "path", r.changeLogPath, "error", err)
+	}
+}
+
+// RecentChanges returns up to limit change log entries, newest first, optionally only for
+// alias. A missing log yields no entries; limit <= 0 returns everything.
+func (r *Repository) RecentChanges(alias string, limit int) ([]domain.ConfigChange, error) {
+	f, err := os.Open(r.changeLogPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("open change log: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+
+	var changes []domain.ConfigChange
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		parts := strings.SplitN(scanner.Text(), "\t", 4)
+		if len(parts) < 3 {
+			continue
+		}
+		at, err := time.Parse(time.RFC3339, parts[0])
+		if err != nil {
+			continue
+		}
+		c := domain.ConfigChange{Time: at, Action: parts[1], Alias: parts[2]}
+		if len(parts) == 4 {
+			c.Detail = parts[3]
+		}
+		if alias != "" && c.Alias != alias && !strings.HasPrefix(c.Detail, alias+" → ") {
+			continue
+		}
+		changes = append(changes, c)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("read change log: %w", err)
+	}
+
+	for i, j := 0, len(changes)-1; i < j; i, j = i+1, j-1 {
+		changes[i], changes[j] = changes[j], changes[i]
+	}
+	if limit > 0 && len(changes) > limit {
+		changes = changes[:limit]
+	}
+	return changes, nil
+}
+
+// describeServer summarizes a server for add and delete entries.
+func describeServer(s domain.Server) string {
+	parts := []string{"HostName " + s.Host}
+	if s.User != "" {
+		parts = append(parts, "User "+s.User)
+	}
+	if s.Port != 0 {
+		parts = append(parts, fmt.Sprintf("Port %d", s.Port))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// describeServerChanges lists the settings that differ between old and updated as
+// "Field: old → new", separated by semicolons.
+func describeServerChanges(old, updated domain.Server) string {
+	ov, nv := reflect.ValueOf(old), reflect.ValueOf(updated)
+	t := ov.Type()
+	var changes []string
+	for i := 0; i < t.NumField(); i++ {
+		name := t.Field(i).Name
+		// The alias and bookkeeping fields change without the user editing a setting.
+		if kind := domain.ServerFieldKind(name); kind == domain.FieldName || kind == domain.FieldState {
+			continue
+		}
+		a, b := changeLogValue(ov.Field(i)), changeLogValue(nv.Field(i))
+		if a == b {
+			continue
+		}
+		if a == "" {
+			a = "(unset)"
+		}
+		if b == "" {
+			b = "(unset)"
+		}
+		changes = append(changes, fmt.Sprintf("%s: %s → %s", name, a, b))
+	}
+	return strings.Join(changes, "; ")
+}
+
+// changeLogValue renders a server field for the change log; zero values render empty.
+func changeLogValue(v reflect.Value) string {
+	switch v.Kind() {
+	case reflect.Slice:
+		items := make([]string, v.Len())
+		for i := range items {
+			items[i] = fmt.Sprint(v.Index(i).Interface())
+		}
+		return strings.Join(items, ",")
+	case reflect.Ptr:
+		if v.IsNil() {
+			return ""
+		}
+		return fmt.Sprint(v.Elem().Interface())
+	}
+	if v.IsZero() {
+		return ""
+	}
+	return fmt.Sprint(v.Interface())
+}
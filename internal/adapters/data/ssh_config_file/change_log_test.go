@@ -0,0 +1,76 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ssh_config_file
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"go.uber.org/zap"
+
+	"github.com/Adembc/lazyssh/internal/config"
+	"github.com/Adembc/lazyssh/internal/core/domain"
+)
+
+func TestChangeLog(t *testing.T) {
+	dir := t.TempDir()
+	configPath := filepath.Join(dir, "config")
+	writeTestFile(t, configPath, "")
+	r := NewRepository(zap.NewNop().Sugar(), configPath, filepath.Join(dir, "metadata.json"), config.Default())
+
+	web := domain.Server{Alias: "web1", Host: "10.0.0.5", User: "ubuntu", Port: 22}
+	if err := r.AddServer(web); err != nil {
+		t.Fatal(err)
+	}
+	moved := web
+	moved.Port = 2222
+	if err := r.UpdateServer(web, moved); err != nil {
+		t.Fatal(err)
+	}
+	renamed := moved
+	renamed.Alias = "web2"
+	if err := r.UpdateServer(moved, renamed); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.DeleteServer(renamed); err != nil {
+		t.Fatal(err)
+	}
+
+	changes, err := r.RecentChanges("", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got [][3]string
+	for _, c := range changes {
+		got = append(got, [3]string{c.Action, c.Alias, c.Detail})
+	}
+	want := [][3]string{
+		{domain.ChangeDelete, "web2", "HostName 10.0.0.5, User ubuntu, Port 2222"},
+		{domain.ChangeRename, "web2", "web1 → web2"},
+		{domain.ChangeUpdate, "web1", "Port: 22 → 2222"},
+		{domain.ChangeAdd, "web1", "HostName 10.0.0.5, User ubuntu, Port 22"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("RecentChanges() =\n%v\nwant\n%v", got, want)
+	}
+
+	if changes, _ := r.RecentChanges("web1", 0); len(changes) != 3 {
+		t.Errorf("RecentChanges(web1) returned %d entries, want add, update and the rename away", len(changes))
+	}
+	if changes, _ := r.RecentChanges("", 1); len(changes) != 1 || changes[0].Action != domain.ChangeDelete {
+		t.Errorf("RecentChanges(limit 1) = %+v, want only the delete", changes)
+	}
+}
@@ -15,9 +15,11 @@
 package ssh_config_file
 
 import (
+	"bufio"
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/kevinburke/ssh_config"
@@ -49,6 +51,10 @@ func (r *Repository) loadConfig() (*ssh_config.Config, error) {
 
 // saveConfig writes the SSH config back to the file with atomic operations and backup management.
 func (r *Repository) saveConfig(cfg *ssh_config.Config) error {
+	if r.sortOnWrite {
+		sortHosts(cfg.Hosts)
+	}
+
 	configDir := filepath.Dir(r.configPath)
 
 	tempFile, err := r.createTempFile(configDir)
@@ -83,6 +89,73 @@ func (r *Repository) saveConfig(cfg *ssh_config.Config) error {
 	return nil
 }
 
+// findHostEntry returns the file declaring alias, searching included files after the main
+// config, and the 1-based line of its Host entry. An alias declared nowhere is reported as the
+// main config with line 0.
+func (r *Repository) findHostEntry(alias string) (string, int, error) {
+	cfg, err := r.loadConfig()
+	if err != nil {
+		return r.configPath, 0, err
+	}
+	for _, def := range r.hostDefinitions(cfg) {
+		if def.alias == alias {
+			line, err := r.findHostLine(def.file, alias)
+			return def.file, line, err
+		}
+	}
+	return r.configPath, 0, nil
+}
+
+// findHostLine returns the 1-based line number of the Host entry in path declaring alias, or 0 if none does.
+func (r *Repository) findHostLine(path, alias string) (int, error) {
+	file, err := r.fileSystem.Open(path)
+	if err != nil {
+		if r.fileSystem.IsNotExist(err) {
+			return 0, nil
+		}
+		return 0, err
+	}
+	defer func() {
+		if cerr := file.Close(); cerr != nil {
+			r.logger.Warnf("failed to close config file: %v", cerr)
+		}
+	}()
+
+	scanner := bufio.NewScanner(file)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		idx := strings.IndexAny(text, " \t=")
+		if idx < 0 || !strings.EqualFold(text[:idx], "host") {
+			continue
+		}
+		for _, pattern := range splitArgs(strings.TrimLeft(text[idx:], " \t=")) {
+			if strings.HasPrefix(pattern, "#") {
+				break
+			}
+			if pattern == alias {
+				return line, nil
+			}
+		}
+	}
+	return 0, scanner.Err()
+}
+
+// lockConfig takes the inter-process lock guarding SSH config read-modify-write cycles.
+func (r *Repository) lockConfig() (*fileLock, error) {
+	lock, err := acquireFileLock(r.configLockPath)
+	if err != nil {
+		r.logger.Warnf("failed to lock SSH config: %v", err)
+		return nil, fmt.Errorf("failed to lock config: %w", err)
+	}
+	return lock, nil
+}
+
+func (r *Repository) unlockConfig(lock *fileLock) {
+	if err := lock.release(); err != nil {
+		r.logger.Warnf("failed to release SSH config lock: %v", err)
+	}
+}
+
 // writeConfigToFile writes the SSH config content to the specified file
 func (r *Repository) writeConfigToFile(filePath string, cfg *ssh_config.Config) error {
 	file, err := r.fileSystem.OpenFile(filePath, os.O_WRONLY|os.O_TRUNC, SSHConfigPerms)
@@ -124,3 +197,34 @@ func (r *Repository) createTempFile(dir string) (string, error) {
 
 	return tempFilePath, nil
 }
+
+// isWritable reports whether saveConfig can succeed: the config file (if it exists)
+// must be writable and its directory must accept the temporary file used for the
+// atomic replace.
+func (r *Repository) isWritable() bool {
+	file, err := r.fileSystem.OpenFile(r.configPath, os.O_WRONLY, 0)
+	switch {
+	case err == nil:
+		if cerr := file.Close(); cerr != nil {
+			r.logger.Warnf("failed to close config file: %v", cerr)
+		}
+	case !r.fileSystem.IsNotExist(err):
+		r.logger.Warnw("SSH config is not writable", "path", r.configPath, "error", err)
+		return false
+	}
+
+	dir := filepath.Dir(r.configPath)
+	probe := filepath.Join(dir, ".lazyssh-write-check"+TempSuffix)
+	f, err := r.fileSystem.OpenFile(probe, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, SSHConfigPerms)
+	if err != nil {
+		r.logger.Warnw("SSH config directory is not writable", "dir", dir, "error", err)
+		return false
+	}
+	if cerr := f.Close(); cerr != nil {
+		r.logger.Warnf("failed to close write check file %s: %v", probe, cerr)
+	}
+	if rerr := r.fileSystem.Remove(probe); rerr != nil {
+		r.logger.Warnf("failed to remove write check file %s: %v", probe, rerr)
+	}
+	return true
+}
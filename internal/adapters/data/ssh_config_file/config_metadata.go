@@ -0,0 +1,185 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ssh_config_file
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Adembc/lazyssh/internal/core/domain"
+	"github.com/kevinburke/ssh_config"
+)
+
+// Comment keys used when tags and pins are kept in the SSH config (metadata_in_config),
+// written inside the Host block as "# lazyssh-tags: prod,web".
+const (
+	tagsCommentKey   = "lazyssh-tags"
+	pinnedCommentKey = "lazyssh-pinned"
+)
+
+// parseMetadataComment splits a lazyssh metadata comment into its key and value. ok is false
+// for any other comment.
+func parseMetadataComment(comment string) (key, value string, ok bool) {
+	key, value, found := strings.Cut(strings.TrimSpace(comment), ":")
+	if !found {
+		return "", "", false
+	}
+	key = strings.TrimSpace(key)
+	if key != tagsCommentKey && key != pinnedCommentKey {
+		return "", "", false
+	}
+	return key, strings.TrimSpace(value), true
+}
+
+// mapCommentToServer reads tags and the pin time from a lazyssh metadata comment. A tags
+// comment always leaves Tags non-nil, so mergeMetadata can tell it apart from no comment.
+func mapCommentToServer(server *domain.Server, empty *ssh_config.Empty) {
+	key, value, ok := parseMetadataComment(empty.Comment)
+	if !ok {
+		return
+	}
+	switch key {
+	case tagsCommentKey:
+		server.Tags = []string{}
+		for _, tag := range strings.Split(value, ",") {
+			if tag = strings.TrimSpace(tag); tag != "" {
+				server.Tags = append(server.Tags, tag)
+			}
+		}
+	case pinnedCommentKey:
+		if pinnedAt, err := time.Parse(time.RFC3339, value); err == nil {
+			server.PinnedAt = pinnedAt
+		}
+	}
+}
+
+// writeConfigMetadata stores server's tags and pin time as comments in host.
+func writeConfigMetadata(host *ssh_config.Host, server domain.Server) {
+	setMetadataComment(host, tagsCommentKey, strings.Join(server.Tags, ","))
+	pinned := ""
+	if !server.PinnedAt.IsZero() {
+		pinned = server.PinnedAt.Format(time.RFC3339)
+	}
+	setMetadataComment(host, pinnedCommentKey, pinned)
+}
+
+// setMetadataComment replaces the comment for key in host, in place, or adds it at the top
+// of the block. An empty value removes it.
+func setMetadataComment(host *ssh_config.Host, key, value string) {
+	var comment ssh_config.Node
+	if value != "" {
+		comment = indentedComment(fmt.Sprintf(" %s: %s", key, value))
+	}
+	nodes := make([]ssh_config.Node, 0, len(host.Nodes)+1)
+	for _, node := range host.Nodes {
+		if empty, ok := node.(*ssh_config.Empty); ok {
+			if k, _, ok := parseMetadataComment(empty.Comment); ok && k == key {
+				if comment != nil {
+					nodes = append(nodes, comment)
+					comment = nil
+				}
+				continue
+			}
+		}
+		nodes = append(nodes, node)
+	}
+	if comment != nil {
+		nodes = append([]ssh_config.Node{comment}, nodes...)
+	}
+	host.Nodes = nodes
+}
+
+// indentedComment returns a comment node indented like the KV nodes lazyssh writes. The
+// parser is the only way to set a comment's indentation, so the line is parsed.
+func indentedComment(text string) ssh_config.Node {
+	cfg, err := ssh_config.Decode(strings.NewReader("    #" + text + "\n"))
+	if err == nil && len(cfg.Hosts) > 0 && len(cfg.Hosts[0].Nodes) > 0 {
+		return cfg.Hosts[0].Nodes[0]
+	}
+	return &ssh_config.Empty{Comment: text}
+}
+
+// stripConfigMetadata clears what is kept in the SSH config from server before it is passed to
+// the metadata store, so tags removed from the config do not come back from there.
+func stripConfigMetadata(server domain.Server) domain.Server {
+	server.Tags = nil
+	server.PinnedAt = time.Time{}
+	return server
+}
+
+// setPinnedInConfig writes or removes the pin comment of alias's Host entry.
+func (r *Repository) setPinnedInConfig(alias string, pinned bool) error {
+	lock, err := r.lockConfig()
+	if err != nil {
+		return err
+	}
+	defer r.unlockConfig(lock)
+
+	cfg, err := r.loadConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	host := r.findHostByAlias(cfg, alias)
+	if host == nil {
+		return fmt.Errorf("server with alias '%s' not found", alias)
+	}
+	value := ""
+	if pinned {
+		value = time.Now().Format(time.RFC3339)
+	}
+	setMetadataComment(host, pinnedCommentKey, value)
+	if err := r.saveConfig(cfg); err != nil {
+		return fmt.Errorf("failed to save config: %w", err)
+	}
+	return nil
+}
+
+// rewriteTagsInConfig replaces oldTag with newTag (or drops it when newTag is empty) in the
+// tags comment of every Host entry and returns the number of entries changed.
+func (r *Repository) rewriteTagsInConfig(oldTag, newTag string) (int, error) {
+	lock, err := r.lockConfig()
+	if err != nil {
+		return 0, err
+	}
+	defer r.unlockConfig(lock)
+
+	cfg, err := r.loadConfig()
+	if err != nil {
+		return 0, fmt.Errorf("failed to load config: %w", err)
+	}
+	changed := 0
+	for _, host := range cfg.Hosts {
+		var server domain.Server
+		for _, node := range host.Nodes {
+			if empty, ok := node.(*ssh_config.Empty); ok {
+				mapCommentToServer(&server, empty)
+			}
+		}
+		tags, ok := replaceTag(server.Tags, oldTag, newTag)
+		if !ok {
+			continue
+		}
+		setMetadataComment(host, tagsCommentKey, strings.Join(tags, ","))
+		changed++
+	}
+	if changed == 0 {
+		return 0, nil
+	}
+	if err := r.saveConfig(cfg); err != nil {
+		return 0, fmt.Errorf("failed to save config: %w", err)
+	}
+	return changed, nil
+}
@@ -0,0 +1,118 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ssh_config_file
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap"
+
+	"github.com/Adembc/lazyssh/internal/config"
+	"github.com/Adembc/lazyssh/internal/core/domain"
+)
+
+func TestMetadataInConfig(t *testing.T) {
+	dir := t.TempDir()
+	configPath := filepath.Join(dir, "config")
+	metaPath := filepath.Join(dir, "metadata.json")
+	writeTestFile(t, configPath, "Host db\n    HostName 10.0.0.6\n")
+	// db was tagged before the option was turned on.
+	writeTestFile(t, metaPath, `{"db": {"tags": ["legacy"], "ssh_count": 3}}`)
+
+	cfg := config.Default()
+	cfg.MetadataInConfig = true
+	r := NewRepository(zap.NewNop().Sugar(), configPath, metaPath, cfg)
+
+	web := domain.Server{Alias: "web", Host: "10.0.0.5", Port: 22, Tags: []string{"prod", "web"}}
+	if err := r.AddServer(web); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.SetPinned("web", true); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(configPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), "    # lazyssh-tags: prod,web\n") || !strings.Contains(string(data), "# lazyssh-pinned: ") {
+		t.Errorf("config lacks metadata comments:\n%s", data)
+	}
+	meta, err := os.ReadFile(metaPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(meta), "prod") {
+		t.Errorf("tags also written to the metadata file: %s", meta)
+	}
+
+	servers := listByAlias(t, r)
+	if got := servers["web"]; !reflect.DeepEqual(got.Tags, []string{"prod", "web"}) || got.PinnedAt.IsZero() {
+		t.Errorf("web = tags %v, pinned %v; want tags from the config and pinned", got.Tags, got.PinnedAt)
+	}
+	if got := servers["db"]; !reflect.DeepEqual(got.Tags, []string{"legacy"}) || got.SSHCount != 3 {
+		t.Errorf("db = tags %v, count %d; want the metadata store's values", got.Tags, got.SSHCount)
+	}
+
+	if n, err := r.RenameTag("legacy", "prod"); err != nil || n != 1 {
+		t.Errorf("RenameTag() = %d, %v; want 1 server", n, err)
+	}
+	if n, err := r.DeleteTag("prod"); err != nil || n != 2 {
+		t.Errorf("DeleteTag() = %d, %v; want 2 servers", n, err)
+	}
+	if err := r.SetPinned("web", false); err != nil {
+		t.Fatal(err)
+	}
+
+	servers = listByAlias(t, r)
+	if got := servers["web"]; !reflect.DeepEqual(got.Tags, []string{"web"}) || !got.PinnedAt.IsZero() {
+		t.Errorf("web = tags %v, pinned %v; want [web] and unpinned", got.Tags, got.PinnedAt)
+	}
+	if got := servers["db"]; len(got.Tags) != 0 {
+		t.Errorf("db tags = %v, want none", got.Tags)
+	}
+}
+
+func TestSetMetadataCommentReplacesInPlace(t *testing.T) {
+	host := (&Repository{}).createHostFromServer(domain.Server{Alias: "web", Host: "h"})
+	setMetadataComment(host, tagsCommentKey, "a")
+	setMetadataComment(host, pinnedCommentKey, "2026-01-02T03:04:05Z")
+	setMetadataComment(host, tagsCommentKey, "b")
+	setMetadataComment(host, pinnedCommentKey, "")
+
+	want := "    # lazyssh-tags: b\n    HostName h\n"
+	if _, got, _ := strings.Cut(host.String(), "\n"); got != want {
+		t.Errorf("host nodes =\n%q\nwant\n%q", got, want)
+	}
+}
+
+func listByAlias(t *testing.T, r interface {
+	ListServers(string) ([]domain.Server, error)
+}) map[string]domain.Server {
+	t.Helper()
+	servers, err := r.ListServers("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	byAlias := make(map[string]domain.Server, len(servers))
+	for _, s := range servers {
+		byAlias[s.Alias] = s
+	}
+	return byAlias
+}
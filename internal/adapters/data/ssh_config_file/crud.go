@@ -16,6 +16,7 @@ package ssh_config_file
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 
 	"github.com/Adembc/lazyssh/internal/core/domain"
@@ -30,46 +31,9 @@ const (
 	OriginalBackupName = "config.original.backup"
 )
 
-// filterServers filters servers based on the query string.
-func (r *Repository) filterServers(servers []domain.Server, query string) []domain.Server {
-	query = strings.ToLower(query)
-	filtered := make([]domain.Server, 0)
-
-	for _, server := range servers {
-		if r.matchesQuery(server, query) {
-			filtered = append(filtered, server)
-		}
-	}
-
-	return filtered
-}
-
-// matchesQuery checks if any field of the server matches the query string.
-func (r *Repository) matchesQuery(server domain.Server, query string) bool {
-	fields := []string{
-		strings.ToLower(server.Host),
-		strings.ToLower(server.User),
-	}
-	for _, tag := range server.Tags {
-		fields = append(fields, strings.ToLower(tag))
-	}
-	for _, alias := range server.Aliases {
-		fields = append(fields, strings.ToLower(alias))
-	}
-
-	for _, field := range fields {
-		if strings.Contains(field, query) {
-			return true
-		}
-	}
-
-	return false
-}
-
-// serverExists checks if a server with the given alias already exists in the config.
-func (r *Repository) serverExists(cfg *ssh_config.Config, alias string) bool {
-	return r.findHostByAlias(cfg, alias) != nil
-}
+// managedHostComment marks Host lines written by lazyssh when annotate_managed_hosts is on.
+// It is informational only: nothing reads it back.
+const managedHostComment = "Added by lazyssh"
 
 // findHostByAlias finds a host by its alias in the SSH config.
 func (r *Repository) findHostByAlias(cfg *ssh_config.Config, alias string) *ssh_config.Host {
@@ -83,24 +47,36 @@ func (r *Repository) findHostByAlias(cfg *ssh_config.Config, alias string) *ssh_
 
 // hostContainsPattern checks if a host contains a specific pattern.
 func (r *Repository) hostContainsPattern(host *ssh_config.Host, target string) bool {
-	for _, pattern := range host.Patterns {
-		if pattern.String() == target {
+	for _, pattern := range hostPatterns(host) {
+		if pattern == target {
 			return true
 		}
 	}
 	return false
 }
 
+// HostBlock renders the Host block lazyssh would write for server, without the
+// "Added by lazyssh" marker, for pasting into another config.
+func (r *Repository) HostBlock(server domain.Server) string {
+	host := r.createHostFromServer(server)
+	host.LeadingSpace = 0
+	host.EOLComment = ""
+	host.SpaceBeforeComment = ""
+	return host.String()
+}
+
 // createHostFromServer creates a new ssh_config.Host from a domain.Server.
 func (r *Repository) createHostFromServer(server domain.Server) *ssh_config.Host {
 	host := &ssh_config.Host{
 		Patterns: []*ssh_config.Pattern{
-			{Str: server.Alias},
+			{Str: quoteArg(server.Alias)},
 		},
-		Nodes:              make([]ssh_config.Node, 0),
-		LeadingSpace:       4,
-		EOLComment:         "Added by lazyssh",
-		SpaceBeforeComment: strings.Repeat(" ", 4),
+		Nodes:        make([]ssh_config.Node, 0),
+		LeadingSpace: 4,
+	}
+	if r.annotateHosts {
+		host.EOLComment = managedHostComment
+		host.SpaceBeforeComment = strings.Repeat(" ", 4)
 	}
 
 	// Basic config - always present
@@ -110,7 +86,7 @@ func (r *Repository) createHostFromServer(server domain.Server) *ssh_config.Host
 		r.addKVNodeIfNotEmpty(host, "Port", fmt.Sprintf("%d", server.Port))
 	}
 	for _, identityFile := range server.IdentityFiles {
-		r.addKVNodeIfNotEmpty(host, "IdentityFile", identityFile)
+		r.addKVNodeIfNotEmpty(host, "IdentityFile", r.identityFileValue(identityFile))
 	}
 
 	// Connection and proxy settings
@@ -197,12 +173,50 @@ func (r *Repository) addKVNodeIfNotEmpty(host *ssh_config.Host, key, value strin
 
 	kvNode := &ssh_config.KV{
 		Key:          key,
-		Value:        value,
+		Value:        configValue(key, value),
 		LeadingSpace: 4,
 	}
 	host.Nodes = append(host.Nodes, kvNode)
 }
 
+// identityFileValue returns the IdentityFile value to write, shortened to ~/... when enabled.
+func (r *Repository) identityFileValue(path string) string {
+	if !r.relativeIdentityPaths {
+		return path
+	}
+	return toTildePath(path, r.homeDir)
+}
+
+// toTildePath rewrites an absolute path under home as ~/<rest>. Both Unix ("/home/u") and
+// Windows ("C:\Users\u") homes are recognised; Windows prefixes are matched case-insensitively
+// with either separator. Paths outside home are returned unchanged.
+func toTildePath(path, home string) string {
+	home = strings.TrimRight(home, `/\`)
+	if home == "" || len(path) <= len(home) {
+		return path
+	}
+
+	prefix := path[:len(home)]
+	windowsHome := strings.Contains(home, `\`) || (len(home) >= 2 && home[1] == ':')
+	if windowsHome {
+		norm := func(s string) string { return strings.ToLower(strings.ReplaceAll(s, `\`, "/")) }
+		if norm(prefix) != norm(home) {
+			return path
+		}
+	} else if prefix != home {
+		return path
+	}
+
+	rest := path[len(home):]
+	if rest[0] != '/' && !(windowsHome && rest[0] == '\\') {
+		return path
+	}
+	if windowsHome {
+		rest = strings.ReplaceAll(rest, `\`, "/")
+	}
+	return "~" + rest
+}
+
 // removeNodesByKey removes all nodes with the specified key from the nodes slice
 func removeNodesByKey(nodes []ssh_config.Node, key string) []ssh_config.Node {
 	filtered := make([]ssh_config.Node, 0, len(nodes))
@@ -222,7 +236,7 @@ func (r *Repository) updateHostNodes(host *ssh_config.Host, newServer domain.Ser
 	// Handle Port - include if explicitly set (even if it's 22)
 	portValue := ""
 	if newServer.Port != 0 {
-		portValue = fmt.Sprintf("%d", newServer.Port)
+		portValue = existingPortValue(host, newServer.Port)
 	}
 
 	updates := map[string]string{
@@ -304,7 +318,7 @@ func (r *Repository) updateHostNodes(host *ssh_config.Host, newServer domain.Ser
 	// Replace multi-value entries entirely to reflect the new state
 	host.Nodes = removeNodesByKey(host.Nodes, "IdentityFile")
 	for _, identityFile := range newServer.IdentityFiles {
-		r.addKVNodeIfNotEmpty(host, "IdentityFile", identityFile)
+		r.addKVNodeIfNotEmpty(host, "IdentityFile", r.identityFileValue(identityFile))
 	}
 
 	host.Nodes = removeNodesByKey(host.Nodes, "LocalForward")
@@ -335,6 +349,24 @@ func (r *Repository) updateHostNodes(host *ssh_config.Host, newServer domain.Ser
 	}
 }
 
+// existingPortValue returns the Port value to write for port, keeping a service name already
+// in the host (Port https) when it still means that port. A name that could not be resolved
+// was read as the default port 22, so it is kept as long as the port was left at 22.
+func existingPortValue(host *ssh_config.Host, port int) string {
+	for _, node := range host.Nodes {
+		kv, ok := node.(*ssh_config.KV)
+		if !ok || !strings.EqualFold(kv.Key, "port") {
+			continue
+		}
+		literal := strings.TrimSpace(kv.Value)
+		if p, ok := parsePort(literal); (ok && p == port) || (!ok && port == 22) {
+			return literal
+		}
+		break
+	}
+	return fmt.Sprintf("%d", port)
+}
+
 // updateOrAddKVNode updates an existing key-value node or adds a new one if it doesn't exist.
 func (r *Repository) updateOrAddKVNode(host *ssh_config.Host, key, newValue string) {
 	keyLower := strings.ToLower(key)
@@ -343,7 +375,10 @@ func (r *Repository) updateOrAddKVNode(host *ssh_config.Host, key, newValue stri
 	for _, node := range host.Nodes {
 		kvNode, ok := node.(*ssh_config.KV)
 		if ok && strings.EqualFold(kvNode.Key, keyLower) {
-			kvNode.Value = newValue
+			// Keep the existing spelling (quotes, escapes) when the value is unchanged.
+			if unquoteValue(keyLower, kvNode.Value) != newValue {
+				kvNode.Value = configValue(keyLower, newValue)
+			}
 			return
 		}
 	}
@@ -351,7 +386,7 @@ func (r *Repository) updateOrAddKVNode(host *ssh_config.Host, key, newValue stri
 	// Add new node if not found
 	kvNode := &ssh_config.KV{
 		Key:          r.getProperKeyCase(key),
-		Value:        newValue,
+		Value:        configValue(key, newValue),
 		LeadingSpace: 4,
 	}
 	host.Nodes = append(host.Nodes, kvNode)
@@ -525,6 +560,98 @@ func (r *Repository) convertCLIForwardToConfigFormat(forward string) string {
 	return forward
 }
 
+// ensureBlankSeparators appends an empty line to every host but the last that does not
+// already end with one, so moved entries stay visually separated.
+func ensureBlankSeparators(hosts []*ssh_config.Host) {
+	for i := 0; i < len(hosts)-1; i++ {
+		nodes := hosts[i].Nodes
+		if hosts[i].Implicit && len(nodes) == 0 {
+			continue
+		}
+		if len(nodes) > 0 {
+			if empty, ok := nodes[len(nodes)-1].(*ssh_config.Empty); ok && empty.Comment == "" {
+				continue
+			}
+		}
+		hosts[i].Nodes = append(hosts[i].Nodes, &ssh_config.Empty{})
+	}
+}
+
+// sortHosts sorts Host entries by their first alias, case-insensitively, in place. Entries
+// with wildcard or negated patterns stay where they are and split the list into runs that
+// are sorted separately, so settings they apply to the entries after them keep applying to
+// the same entries. A run where an alias is declared more than once is left as is, since
+// OpenSSH takes the first value it finds. Comment lines directly above a Host line are
+// parsed into the entry before it; they are detached first so they move with the entry
+// they describe.
+func sortHosts(hosts []*ssh_config.Host) {
+	leading := make(map[*ssh_config.Host][]ssh_config.Node)
+	for i := 1; i < len(hosts); i++ {
+		if comments := detachTrailingComments(hosts[i-1]); len(comments) > 0 {
+			leading[hosts[i]] = comments
+		}
+	}
+
+	start := 0
+	for i := 0; i <= len(hosts); i++ {
+		if i < len(hosts) && !hosts[i].Implicit && !isWildcardHost(hosts[i]) {
+			continue
+		}
+		sortHostRun(hosts[start:i])
+		start = i + 1
+	}
+	ensureBlankSeparators(hosts)
+
+	for i := 1; i < len(hosts); i++ {
+		hosts[i-1].Nodes = append(hosts[i-1].Nodes, leading[hosts[i]]...)
+	}
+}
+
+// detachTrailingComments removes and returns the comment lines that end host's nodes with
+// no blank line after them, i.e. the comments written directly above the next Host line.
+func detachTrailingComments(host *ssh_config.Host) []ssh_config.Node {
+	i := len(host.Nodes)
+	for i > 0 {
+		empty, ok := host.Nodes[i-1].(*ssh_config.Empty)
+		if !ok || empty.Comment == "" {
+			break
+		}
+		i--
+	}
+	comments := append([]ssh_config.Node(nil), host.Nodes[i:]...)
+	host.Nodes = host.Nodes[:i]
+	return comments
+}
+
+func sortHostRun(run []*ssh_config.Host) {
+	seen := make(map[string]bool)
+	for _, host := range run {
+		for _, pattern := range hostPatterns(host) {
+			if seen[pattern] {
+				return
+			}
+			seen[pattern] = true
+		}
+	}
+	key := func(host *ssh_config.Host) string {
+		if patterns := hostPatterns(host); len(patterns) > 0 {
+			return strings.ToLower(patterns[0])
+		}
+		return ""
+	}
+	sort.SliceStable(run, func(i, j int) bool { return key(run[i]) < key(run[j]) })
+}
+
+// isWildcardHost reports whether any of host's patterns matches more than one name.
+func isWildcardHost(host *ssh_config.Host) bool {
+	for _, pattern := range hostPatterns(host) {
+		if strings.ContainsAny(pattern, "*?!") {
+			return true
+		}
+	}
+	return false
+}
+
 // removeHostByAlias removes a host by its alias from the list of hosts.
 func (r *Repository) removeHostByAlias(hosts []*ssh_config.Host, alias string) []*ssh_config.Host {
 	for i, host := range hosts {
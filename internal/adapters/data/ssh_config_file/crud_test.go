@@ -15,7 +15,14 @@
 package ssh_config_file
 
 import (
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+
+	"github.com/Adembc/lazyssh/internal/core/domain"
+	"github.com/kevinburke/ssh_config"
+	"go.uber.org/zap"
 )
 
 func TestConvertCLIForwardToConfigFormat(t *testing.T) {
@@ -145,3 +152,334 @@ func TestConvertConfigForwardToCLIFormat(t *testing.T) {
 		})
 	}
 }
+
+func TestToTildePath(t *testing.T) {
+	tests := []struct {
+		name     string
+		path     string
+		home     string
+		expected string
+	}{
+		{
+			name:     "unix path under home",
+			path:     "/home/alice/.ssh/id_ed25519",
+			home:     "/home/alice",
+			expected: "~/.ssh/id_ed25519",
+		},
+		{
+			name:     "unix home with trailing slash",
+			path:     "/home/alice/.ssh/id_rsa",
+			home:     "/home/alice/",
+			expected: "~/.ssh/id_rsa",
+		},
+		{
+			name:     "unix sibling directory with same prefix",
+			path:     "/home/alice2/.ssh/id_rsa",
+			home:     "/home/alice",
+			expected: "/home/alice2/.ssh/id_rsa",
+		},
+		{
+			name:     "unix path outside home",
+			path:     "/etc/ssh/keys/id_rsa",
+			home:     "/home/alice",
+			expected: "/etc/ssh/keys/id_rsa",
+		},
+		{
+			name:     "already relative",
+			path:     "~/.ssh/id_rsa",
+			home:     "/home/alice",
+			expected: "~/.ssh/id_rsa",
+		},
+		{
+			name:     "windows path under home",
+			path:     `C:\Users\Alice\.ssh\id_ed25519`,
+			home:     `C:\Users\Alice`,
+			expected: "~/.ssh/id_ed25519",
+		},
+		{
+			name:     "windows path with different case and forward slashes",
+			path:     "c:/users/alice/.ssh/id_rsa",
+			home:     `C:\Users\Alice`,
+			expected: "~/.ssh/id_rsa",
+		},
+		{
+			name:     "windows path outside home",
+			path:     `D:\keys\id_rsa`,
+			home:     `C:\Users\Alice`,
+			expected: `D:\keys\id_rsa`,
+		},
+		{
+			name:     "home itself",
+			path:     "/home/alice",
+			home:     "/home/alice",
+			expected: "/home/alice",
+		},
+		{
+			name:     "unknown home",
+			path:     "/home/alice/.ssh/id_rsa",
+			home:     "",
+			expected: "/home/alice/.ssh/id_rsa",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := toTildePath(tt.path, tt.home)
+			if result != tt.expected {
+				t.Errorf("toTildePath(%q, %q) = %q, want %q", tt.path, tt.home, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestMoveServer(t *testing.T) {
+	dir := t.TempDir()
+	configPath := filepath.Join(dir, "config")
+	writeTestFile(t, configPath, "User global\n\nHost a\n    HostName 10.0.0.1\n\nHost b\n    HostName 10.0.0.2\n\nHost c\n    HostName 10.0.0.3\n")
+
+	r := &Repository{
+		configPath:     configPath,
+		fileSystem:     DefaultFileSystem{},
+		logger:         zap.NewNop().Sugar(),
+		configLockPath: filepath.Join(dir, "ssh_config.lock"),
+	}
+
+	neighbour, err := r.MoveServer("c", -1)
+	if err != nil {
+		t.Fatalf("MoveServer(c, -1) error = %v", err)
+	}
+	if neighbour != "b" {
+		t.Errorf("MoveServer(c, -1) neighbour = %q, want %q", neighbour, "b")
+	}
+
+	data, err := os.ReadFile(configPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "User global\n\nHost a\n    HostName 10.0.0.1\n\nHost c\n    HostName 10.0.0.3\n\nHost b\n    HostName 10.0.0.2\n"
+	if got := string(data); got != want && got != want+"\n" {
+		t.Errorf("config after move =\n%s\nwant\n%s", got, want)
+	}
+
+	if _, err := r.MoveServer("a", -1); err == nil {
+		t.Errorf("MoveServer(a, -1) moved past the global section, want error")
+	}
+	if _, err := r.MoveServer("missing", 1); err == nil {
+		t.Errorf("MoveServer(missing, 1) error = nil, want not found")
+	}
+}
+
+func TestSortHosts(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{
+			name:  "by alias",
+			input: "User global\n\nHost web\n    HostName 10.0.0.1\n\nHost Db\n    HostName 10.0.0.2\n\nHost app\n    HostName 10.0.0.3\n",
+			want:  "User global\n\nHost app\n    HostName 10.0.0.3\n\nHost Db\n    HostName 10.0.0.2\n\nHost web\n    HostName 10.0.0.1\n",
+		},
+		{
+			name:  "wildcards stay in place",
+			input: "Host zeta\n    User z\n\nHost alpha\n    User a\n\nHost *.prod\n    User deploy\n\nHost web.prod\n    HostName 10.0.0.1\n\nHost db.prod\n    HostName 10.0.0.2\n",
+			want:  "Host alpha\n    User a\n\nHost zeta\n    User z\n\nHost *.prod\n    User deploy\n\nHost db.prod\n    HostName 10.0.0.2\n\nHost web.prod\n    HostName 10.0.0.1\n",
+		},
+		{
+			name:  "repeated alias keeps its run",
+			input: "Host web\n    User first\n\nHost app web\n    User second\n",
+			want:  "Host web\n    User first\n\nHost app web\n    User second\n",
+		},
+		{
+			name:  "comments move with the entry below them",
+			input: "# global\nUser global\n\n# web server\nHost web\n    HostName 10.0.0.1\n\n# the app\n# (staging)\nHost app\n    HostName 10.0.0.3\n# before wildcard\nHost *\n    User me\n",
+			want:  "# global\nUser global\n\n# the app\n# (staging)\nHost app\n    HostName 10.0.0.3\n\n# web server\nHost web\n    HostName 10.0.0.1\n\n# before wildcard\nHost *\n    User me\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg, err := ssh_config.Decode(strings.NewReader(tt.input))
+			if err != nil {
+				t.Fatal(err)
+			}
+			sortHosts(cfg.Hosts)
+			if got := strings.TrimRight(cfg.String(), "\n") + "\n"; got != tt.want {
+				t.Errorf("sortHosts() =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMoveServerRefusedWhenSorted(t *testing.T) {
+	dir := t.TempDir()
+	configPath := filepath.Join(dir, "config")
+	writeTestFile(t, configPath, "Host a\n    HostName 10.0.0.1\n\nHost b\n    HostName 10.0.0.2\n")
+
+	r := &Repository{
+		configPath:     configPath,
+		fileSystem:     DefaultFileSystem{},
+		logger:         zap.NewNop().Sugar(),
+		configLockPath: filepath.Join(dir, "ssh_config.lock"),
+		sortOnWrite:    true,
+	}
+	if _, err := r.MoveServer("b", -1); err == nil {
+		t.Errorf("MoveServer() with sort_config_on_write error = nil, want refusal")
+	}
+}
+
+func TestEnvDirectivesRoundTrip(t *testing.T) {
+	input := "Host app\n    HostName 10.0.0.7\n    SendEnv LANG LC_*\n    SendEnv TZ\n    SetEnv FOO=bar\n    SetEnv DEBUG=1\n"
+	cfg, err := ssh_config.Decode(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+
+	r := &Repository{}
+	servers := r.toDomainServer(cfg)
+	if len(servers) != 1 {
+		t.Fatalf("toDomainServer() returned %d servers, want 1", len(servers))
+	}
+	s := servers[0]
+	if strings.Join(s.SendEnv, "|") != "LANG LC_*|TZ" {
+		t.Errorf("SendEnv = %q, want [LANG LC_* TZ]", s.SendEnv)
+	}
+	if strings.Join(s.SetEnv, "|") != "FOO=bar|DEBUG=1" {
+		t.Errorf("SetEnv = %q, want [FOO=bar DEBUG=1]", s.SetEnv)
+	}
+
+	out := r.createHostFromServer(s).String()
+	for _, want := range []string{"SendEnv LANG LC_*", "SendEnv TZ", "SetEnv FOO=bar", "SetEnv DEBUG=1"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("written host missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestIsWritable(t *testing.T) {
+	dir := t.TempDir()
+	configPath := filepath.Join(dir, "config")
+	r := &Repository{
+		configPath: configPath,
+		fileSystem: DefaultFileSystem{},
+		logger:     zap.NewNop().Sugar(),
+	}
+
+	if !r.isWritable() {
+		t.Errorf("isWritable() = false for a missing config in a writable directory")
+	}
+	writeTestFile(t, configPath, "Host a\n    HostName 10.0.0.1\n")
+	if !r.isWritable() {
+		t.Errorf("isWritable() = false for a writable config")
+	}
+	if entries, _ := os.ReadDir(dir); len(entries) != 1 {
+		t.Errorf("isWritable() left %d files behind, want only the config", len(entries))
+	}
+
+	r.configPath = filepath.Join(dir, "missing", "config")
+	if r.isWritable() {
+		t.Errorf("isWritable() = true when the config directory does not exist")
+	}
+
+	if os.Geteuid() == 0 {
+		t.Skip("permission checks do not apply to root")
+	}
+	r.configPath = configPath
+	if err := os.Chmod(configPath, 0o400); err != nil {
+		t.Fatal(err)
+	}
+	if r.isWritable() {
+		t.Errorf("isWritable() = true for a read-only config")
+	}
+}
+
+func TestHostBlock(t *testing.T) {
+	r := &Repository{logger: zap.NewNop().Sugar()}
+	got := r.HostBlock(domain.Server{Alias: "web1", Host: "10.0.0.5", User: "ubuntu", Port: 2222, IdentityFiles: []string{"~/.ssh/id_ed25519"}})
+	want := "Host web1\n    HostName 10.0.0.5\n    User ubuntu\n    Port 2222\n    IdentityFile ~/.ssh/id_ed25519\n"
+	if got != want {
+		t.Errorf("HostBlock() =\n%q\nwant\n%q", got, want)
+	}
+}
+
+func TestPortServiceNameRoundTrip(t *testing.T) {
+	input := "Host web\n    HostName 10.0.0.8\n    Port https\n\nHost odd\n    HostName 10.0.0.9\n    Port no-such-service\n"
+	cfg, err := ssh_config.Decode(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+
+	r := &Repository{}
+	servers := r.toDomainServer(cfg)
+	if servers[0].Port != 443 {
+		t.Errorf("Port https read as %d, want 443", servers[0].Port)
+	}
+	if servers[1].Port != 22 {
+		t.Errorf("unknown service read as %d, want the default 22", servers[1].Port)
+	}
+
+	tests := []struct {
+		name string
+		host int
+		port int
+		want string
+	}{
+		{"service name kept", 0, 443, "Port https"},
+		{"changed port replaces name", 0, 8443, "Port 8443"},
+		{"unresolvable name kept at default", 1, 22, "Port no-such-service"},
+		{"unresolvable name replaced when changed", 1, 2222, "Port 2222"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg, _ := ssh_config.Decode(strings.NewReader(input))
+			host := cfg.Hosts[tt.host+1] // Hosts[0] is the implicit global section
+			server := servers[tt.host]
+			server.Port = tt.port
+			r.updateHostNodes(host, server)
+			if out := host.String(); !strings.Contains(out, tt.want) {
+				t.Errorf("host after update missing %q:\n%s", tt.want, out)
+			}
+		})
+	}
+}
+
+func TestDebugDirectivesRoundTrip(t *testing.T) {
+	input := "Host app\n    HostName 10.0.0.7\n    LogLevel DEBUG3\n    VerifyHostKeyDNS ask\n"
+	cfg, err := ssh_config.Decode(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+
+	r := &Repository{}
+	s := r.toDomainServer(cfg)[0]
+	if s.LogLevel != "DEBUG3" || s.VerifyHostKeyDNS != "ask" {
+		t.Errorf("LogLevel, VerifyHostKeyDNS = %q, %q; want DEBUG3, ask", s.LogLevel, s.VerifyHostKeyDNS)
+	}
+
+	out := r.createHostFromServer(s).String()
+	for _, want := range []string{"LogLevel DEBUG3", "VerifyHostKeyDNS ask"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("written host missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestAnnotateManagedHosts(t *testing.T) {
+	server := domain.Server{Alias: "web", Host: "10.0.0.5", User: "deploy", Port: 22}
+	for _, annotate := range []bool{true, false} {
+		r := &Repository{annotateHosts: annotate}
+		out := r.createHostFromServer(server).String()
+		if got := strings.Contains(out, managedHostComment); got != annotate {
+			t.Errorf("annotate=%v: written host has marker %v:\n%s", annotate, got, out)
+		}
+
+		// Reading the entry back does not depend on the marker.
+		cfg, err := ssh_config.Decode(strings.NewReader(out))
+		if err != nil {
+			t.Fatalf("Decode() error = %v", err)
+		}
+		servers := r.toDomainServer(cfg)
+		if len(servers) != 1 || servers[0].Alias != "web" || servers[0].Host != "10.0.0.5" || servers[0].User != "deploy" {
+			t.Errorf("annotate=%v: read back %+v", annotate, servers)
+		}
+	}
+}
@@ -0,0 +1,173 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ssh_config_file
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/kevinburke/ssh_config"
+)
+
+// maxIncludeDepth mirrors the nesting limit OpenSSH and the parser apply to Include.
+const maxIncludeDepth = 5
+
+// hostDefinition records a concrete alias and the file declaring it.
+type hostDefinition struct {
+	alias string
+	file  string
+}
+
+// hostDefinitions lists every concrete alias declared in cfg and, recursively, in the
+// files it includes. Included files that cannot be read are logged and skipped.
+func (r *Repository) hostDefinitions(cfg *ssh_config.Config) []hostDefinition {
+	visited := map[string]bool{r.configPath: true}
+	return r.collectHostDefinitions(cfg, r.configPath, 0, visited)
+}
+
+func (r *Repository) collectHostDefinitions(cfg *ssh_config.Config, file string, depth int, visited map[string]bool) []hostDefinition {
+	var defs []hostDefinition
+	for _, host := range cfg.Hosts {
+		for _, alias := range hostPatterns(host) {
+			if strings.ContainsAny(alias, "!*?[]") {
+				continue
+			}
+			defs = append(defs, hostDefinition{alias: alias, file: file})
+		}
+	}
+	if depth >= maxIncludeDepth {
+		return defs
+	}
+
+	for _, path := range r.includedFiles(cfg) {
+		if visited[path] {
+			continue
+		}
+		visited[path] = true
+		included, err := r.decodeFile(path)
+		if err != nil {
+			r.logger.Warnw("failed to read included ssh config", "path", path, "error", err)
+			continue
+		}
+		defs = append(defs, r.collectHostDefinitions(included, path, depth+1, visited)...)
+	}
+	return defs
+}
+
+// includedFiles resolves the Include directives of cfg to existing file paths. Relative
+// paths are taken from the directory of the main config, as OpenSSH does for ~/.ssh.
+func (r *Repository) includedFiles(cfg *ssh_config.Config) []string {
+	var files []string
+	for _, host := range cfg.Hosts {
+		for _, node := range host.Nodes {
+			inc, ok := node.(*ssh_config.Include)
+			if !ok {
+				continue
+			}
+			text := strings.TrimSpace(inc.String())
+			text = strings.TrimLeft(strings.TrimPrefix(text, "Include"), " \t=")
+			for _, pattern := range splitArgs(text) {
+				if strings.HasPrefix(pattern, "#") {
+					break
+				}
+				matches, err := r.fileSystem.Glob(r.resolveIncludePath(pattern))
+				if err != nil {
+					r.logger.Warnw("invalid Include pattern", "pattern", pattern, "error", err)
+					continue
+				}
+				files = append(files, matches...)
+			}
+		}
+	}
+	return files
+}
+
+func (r *Repository) resolveIncludePath(pattern string) string {
+	switch {
+	case pattern == "~" || strings.HasPrefix(pattern, "~/"):
+		if r.homeDir != "" {
+			return filepath.Join(r.homeDir, strings.TrimPrefix(pattern, "~"))
+		}
+		return pattern
+	case filepath.IsAbs(pattern):
+		return pattern
+	default:
+		return filepath.Join(filepath.Dir(r.configPath), pattern)
+	}
+}
+
+// decodeFile parses an SSH config file other than the main one.
+func (r *Repository) decodeFile(path string) (*ssh_config.Config, error) {
+	file, err := r.fileSystem.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if cerr := file.Close(); cerr != nil {
+			r.logger.Warnf("failed to close config file: %v", cerr)
+		}
+	}()
+	return ssh_config.Decode(file)
+}
+
+// aliasDefined reports whether alias is declared in cfg or any file it includes.
+func (r *Repository) aliasDefined(cfg *ssh_config.Config, alias string) bool {
+	for _, def := range r.hostDefinitions(cfg) {
+		if def.alias == alias {
+			return true
+		}
+	}
+	return false
+}
+
+// duplicateAliases returns, sorted, the aliases declared more than once in defs.
+func duplicateAliases(defs []hostDefinition) []string {
+	counts := make(map[string]int, len(defs))
+	for _, def := range defs {
+		counts[def.alias]++
+	}
+	var dups []string
+	for alias, n := range counts {
+		if n > 1 {
+			dups = append(dups, alias)
+		}
+	}
+	sort.Strings(dups)
+	return dups
+}
+
+// checkDuplicateHosts queues a warning naming aliases declared more than once across the
+// main config and its includes; OpenSSH only honours the first definition of each. It runs
+// once, on the first DrainWarnings, rather than from the constructor.
+func (r *Repository) checkDuplicateHosts() {
+	cfg, err := r.loadConfig()
+	if err != nil {
+		return
+	}
+	dups := duplicateAliases(r.hostDefinitions(cfg))
+	if len(dups) == 0 {
+		return
+	}
+	r.logger.Warnw("duplicate host definitions in ssh config", "aliases", dups)
+	r.addWarning(fmt.Sprintf("Duplicate Host entries (only the first is used): %s", strings.Join(dups, ", ")))
+}
+
+func (r *Repository) addWarning(msg string) {
+	r.warningsMu.Lock()
+	defer r.warningsMu.Unlock()
+	r.warnings = append(r.warnings, msg)
+}
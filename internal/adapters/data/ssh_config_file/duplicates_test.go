@@ -0,0 +1,161 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ssh_config_file
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Adembc/lazyssh/internal/config"
+	"github.com/Adembc/lazyssh/internal/core/domain"
+	"go.uber.org/zap"
+)
+
+func writeTestFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		t.Fatalf("MkdirAll() error = %v", err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+}
+
+func TestDuplicateHostsAcrossIncludes(t *testing.T) {
+	dir := t.TempDir()
+	configPath := filepath.Join(dir, "config")
+	writeTestFile(t, configPath, "Include conf.d/*\n\nHost web\n    HostName 10.0.0.1\n\nHost db web-2\n    HostName 10.0.0.2\n\nHost *\n    User root\n")
+	writeTestFile(t, filepath.Join(dir, "conf.d", "work"), "Host web\n    HostName 10.1.0.1\n\nHost bastion\n    HostName 10.1.0.2\n\nHost *\n    User ops\n")
+
+	r := &Repository{
+		configPath: configPath,
+		fileSystem: DefaultFileSystem{},
+		logger:     zap.NewNop().Sugar(),
+	}
+
+	cfg, err := r.loadConfig()
+	if err != nil {
+		t.Fatalf("loadConfig() error = %v", err)
+	}
+
+	if dups := duplicateAliases(r.hostDefinitions(cfg)); strings.Join(dups, ",") != "web" {
+		t.Errorf("duplicateAliases() = %v, want [web]", dups)
+	}
+	if !r.aliasDefined(cfg, "bastion") {
+		t.Errorf("aliasDefined(%q) = false, want true for alias from include", "bastion")
+	}
+	if r.aliasDefined(cfg, "missing") {
+		t.Errorf("aliasDefined(%q) = true, want false", "missing")
+	}
+
+	r.checkDuplicateHosts()
+	warnings := r.warnings
+	if len(warnings) != 1 || !strings.Contains(warnings[0], "web") {
+		t.Errorf("warnings = %q, want one naming web", warnings)
+	}
+}
+
+func TestAddServerRejectsAliasFromInclude(t *testing.T) {
+	dir := t.TempDir()
+	configPath := filepath.Join(dir, "config")
+	writeTestFile(t, configPath, "Include extra\n")
+	writeTestFile(t, filepath.Join(dir, "extra"), "Host bastion\n    HostName 10.1.0.2\n")
+
+	r := &Repository{
+		configPath:     configPath,
+		fileSystem:     DefaultFileSystem{},
+		logger:         zap.NewNop().Sugar(),
+		metadataStore:  newMetadataManager(filepath.Join(dir, "metadata.json"), zap.NewNop().Sugar()),
+		configLockPath: filepath.Join(dir, "ssh_config.lock"),
+	}
+
+	err := r.AddServer(domain.Server{Alias: "bastion", Host: "10.9.9.9"})
+	if err == nil || !strings.Contains(err.Error(), "already exists") {
+		t.Errorf("AddServer() error = %v, want already exists", err)
+	}
+}
+
+func TestServerFilesLocatesIncludedHost(t *testing.T) {
+	dir := t.TempDir()
+	configPath := filepath.Join(dir, "config")
+	extraPath := filepath.Join(dir, "conf.d", "work")
+	writeTestFile(t, configPath, "Include conf.d/*\n\nHost web\n    HostName 10.0.0.1\n")
+	writeTestFile(t, extraPath, "# work hosts\n\nHost db bastion\n    HostName 10.1.0.2\n")
+
+	r := &Repository{
+		configPath:    configPath,
+		fileSystem:    DefaultFileSystem{},
+		logger:        zap.NewNop().Sugar(),
+		metadataStore: newMetadataManager(filepath.Join(dir, "metadata.json"), zap.NewNop().Sugar()),
+	}
+
+	tests := []struct {
+		alias    string
+		wantPath string
+		wantLine int
+	}{
+		{alias: "web", wantPath: configPath, wantLine: 3},
+		{alias: "bastion", wantPath: extraPath, wantLine: 3},
+		{alias: "missing", wantPath: configPath, wantLine: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.alias, func(t *testing.T) {
+			files, err := r.ServerFiles(tt.alias)
+			if err != nil {
+				t.Fatalf("ServerFiles() error = %v", err)
+			}
+			if files.ConfigPath != tt.wantPath || files.ConfigLine != tt.wantLine {
+				t.Errorf("ServerFiles() = %s:%d, want %s:%d", files.ConfigPath, files.ConfigLine, tt.wantPath, tt.wantLine)
+			}
+		})
+	}
+}
+
+// countingFS records how often includes are globbed.
+type countingFS struct {
+	DefaultFileSystem
+	globs int
+}
+
+func (fs *countingFS) Glob(pattern string) ([]string, error) {
+	fs.globs++
+	return fs.DefaultFileSystem.Glob(pattern)
+}
+
+func TestDuplicateCheckIsLazy(t *testing.T) {
+	dir := t.TempDir()
+	configPath := filepath.Join(dir, "config")
+	writeTestFile(t, configPath, "Include extra\n\nHost web\n    HostName 10.0.0.1\n")
+	writeTestFile(t, filepath.Join(dir, "extra"), "Host web\n    HostName 10.1.0.1\n")
+
+	fs := &countingFS{}
+	r := NewRepositoryWithFS(zap.NewNop().Sugar(), configPath, filepath.Join(dir, "metadata.json"), fs, config.Default())
+	if fs.globs != 0 {
+		t.Fatalf("constructor globbed includes %d time(s), want none", fs.globs)
+	}
+
+	warnings := r.DrainWarnings()
+	if len(warnings) != 1 || !strings.Contains(warnings[0], "web") {
+		t.Errorf("DrainWarnings() = %q, want one naming web", warnings)
+	}
+	if fs.globs == 0 {
+		t.Error("includes were not read through the injected filesystem")
+	}
+	if again := r.DrainWarnings(); len(again) != 0 {
+		t.Errorf("second DrainWarnings() = %q, want none", again)
+	}
+}
@@ -0,0 +1,79 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ssh_config_file
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const (
+	lockTimeout        = 3 * time.Second
+	lockInitialBackoff = 10 * time.Millisecond
+	lockMaxBackoff     = 250 * time.Millisecond
+)
+
+// errLockBusy is returned by tryLockFile when another process holds the lock.
+var errLockBusy = errors.New("lock is held by another process")
+
+// fileLock is an advisory inter-process lock held on a sidecar lock file. It guards
+// read-modify-write cycles so that concurrent lazyssh instances do not clobber each other.
+type fileLock struct {
+	file *os.File
+}
+
+// acquireFileLock takes an exclusive lock on path, creating it if needed. While another
+// process holds the lock it retries with exponential backoff until lockTimeout elapses.
+func acquireFileLock(path string) (*fileLock, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		return nil, fmt.Errorf("mkdir '%s': %w", filepath.Dir(path), err)
+	}
+	// #nosec G304 -- the lock path is derived internally, not user-supplied
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("open lock file '%s': %w", path, err)
+	}
+
+	deadline := time.Now().Add(lockTimeout)
+	backoff := lockInitialBackoff
+	for {
+		err := tryLockFile(f)
+		if err == nil {
+			return &fileLock{file: f}, nil
+		}
+		if !errors.Is(err, errLockBusy) || time.Now().After(deadline) {
+			_ = f.Close()
+			if errors.Is(err, errLockBusy) {
+				return nil, fmt.Errorf("timed out waiting for lock '%s'", path)
+			}
+			return nil, fmt.Errorf("lock '%s': %w", path, err)
+		}
+		time.Sleep(backoff)
+		backoff = min(backoff*2, lockMaxBackoff)
+	}
+}
+
+// release unlocks and closes the lock file. The file itself is left in place.
+func (l *fileLock) release() error {
+	if l == nil || l.file == nil {
+		return nil
+	}
+	uerr := unlockFile(l.file)
+	cerr := l.file.Close()
+	return errors.Join(uerr, cerr)
+}
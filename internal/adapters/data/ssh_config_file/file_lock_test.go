@@ -0,0 +1,77 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ssh_config_file
+
+import (
+	"fmt"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"go.uber.org/zap"
+
+	"github.com/Adembc/lazyssh/internal/config"
+	"github.com/Adembc/lazyssh/internal/core/domain"
+)
+
+func TestConcurrentWritersKeepEveryUpdate(t *testing.T) {
+	dir := t.TempDir()
+	configPath := filepath.Join(dir, "config")
+	metaPath := filepath.Join(dir, "metadata.json")
+	writeTestFile(t, configPath, "")
+
+	// Two repositories stand in for two lazyssh instances sharing one config.
+	repos := []interface{ AddServer(domain.Server) error }{
+		NewRepository(zap.NewNop().Sugar(), configPath, metaPath, config.Default()),
+		NewRepository(zap.NewNop().Sugar(), configPath, metaPath, config.Default()),
+	}
+	const perRepo = 10
+
+	var wg sync.WaitGroup
+	errs := make(chan error, len(repos)*perRepo)
+	for i, repo := range repos {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < perRepo; j++ {
+				alias := fmt.Sprintf("host-%d-%d", i, j)
+				if err := repo.AddServer(domain.Server{Alias: alias, Host: alias + ".lan", Port: 22}); err != nil {
+					errs <- fmt.Errorf("add %s: %w", alias, err)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Error(err)
+	}
+
+	servers, err := NewRepository(zap.NewNop().Sugar(), configPath, metaPath, config.Default()).ListServers("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := make(map[string]bool, len(servers))
+	for _, s := range servers {
+		got[s.Alias] = true
+	}
+	for i := range repos {
+		for j := 0; j < perRepo; j++ {
+			if alias := fmt.Sprintf("host-%d-%d", i, j); !got[alias] {
+				t.Errorf("%s lost by a concurrent write", alias)
+			}
+		}
+	}
+}
@@ -15,6 +15,7 @@
 package ssh_config_file
 
 import (
+	"net"
 	"strconv"
 	"strings"
 	"time"
@@ -26,12 +27,12 @@ import (
 // toDomainServer converts ssh_config.Config to a slice of domain.Server.
 func (r *Repository) toDomainServer(cfg *ssh_config.Config) []domain.Server {
 	servers := make([]domain.Server, 0, len(cfg.Hosts))
-	for _, host := range cfg.Hosts {
+	for i, host := range cfg.Hosts {
 
-		aliases := make([]string, 0, len(host.Patterns))
+		patterns := hostPatterns(host)
+		aliases := make([]string, 0, len(patterns))
 
-		for _, pattern := range host.Patterns {
-			alias := pattern.String()
+		for _, alias := range patterns {
 			// Skip if alias contains wildcards (not a concrete Host)
 			if strings.ContainsAny(alias, "!*?[]") {
 				continue
@@ -46,15 +47,18 @@ func (r *Repository) toDomainServer(cfg *ssh_config.Config) []domain.Server {
 			Aliases:       aliases,
 			Port:          22,
 			IdentityFiles: []string{},
+			ConfigOrder:   i,
 		}
 
 		for _, node := range host.Nodes {
-			kvNode, ok := node.(*ssh_config.KV)
-			if !ok {
-				continue
+			switch node := node.(type) {
+			case *ssh_config.KV:
+				r.mapKVToServer(&server, node)
+			case *ssh_config.Empty:
+				if r.metadataInConfig {
+					mapCommentToServer(&server, node)
+				}
 			}
-
-			r.mapKVToServer(&server, kvNode)
 		}
 
 		servers = append(servers, server)
@@ -66,7 +70,13 @@ func (r *Repository) toDomainServer(cfg *ssh_config.Config) []domain.Server {
 // mapKVToServer maps an ssh_config.KV node to the corresponding fields in domain.Server.
 func (r *Repository) mapKVToServer(server *domain.Server, kvNode *ssh_config.KV) {
 	key := strings.ToLower(kvNode.Key)
-	value := kvNode.Value
+	if key == "identityfile" {
+		// Some configs list several keys on one line; each becomes its own entry and is
+		// written back one per line.
+		server.IdentityFiles = append(server.IdentityFiles, splitArgs(kvNode.Value)...)
+		return
+	}
+	value := unquoteValue(key, kvNode.Value)
 
 	// Try mapping in order of categories
 	if r.mapBasicConfig(server, key, value) {
@@ -90,6 +100,18 @@ func (r *Repository) mapKVToServer(server *domain.Server, kvNode *ssh_config.KV)
 	r.mapDebugConfig(server, key, value)
 }
 
+// parsePort reads a Port value, which ssh accepts as a number or as a service name from
+// the services database ("ssh", "https").
+func parsePort(value string) (int, bool) {
+	if port, err := strconv.Atoi(value); err == nil {
+		return port, true
+	}
+	if port, err := net.LookupPort("tcp", value); err == nil {
+		return port, true
+	}
+	return 0, false
+}
+
 // mapBasicConfig maps basic SSH configuration fields
 func (r *Repository) mapBasicConfig(server *domain.Server, key, value string) bool {
 	switch key {
@@ -98,12 +120,9 @@ func (r *Repository) mapBasicConfig(server *domain.Server, key, value string) bo
 	case "user":
 		server.User = value
 	case "port":
-		port, err := strconv.Atoi(value)
-		if err == nil {
+		if port, ok := parsePort(value); ok {
 			server.Port = port
 		}
-	case "identityfile":
-		server.IdentityFiles = append(server.IdentityFiles, value)
 	default:
 		return false
 	}
@@ -297,7 +316,11 @@ func (r *Repository) mergeMetadata(servers []domain.Server, metadata map[string]
 		servers[i].LastSeen = time.Time{}
 
 		if meta, exists := metadata[server.Alias]; exists {
-			servers[i].Tags = meta.Tags
+			// With metadata_in_config, tags and pins from the SSH config win; the metadata
+			// store only fills them in for entries not saved since the option was turned on.
+			if !r.metadataInConfig || server.Tags == nil {
+				servers[i].Tags = meta.Tags
+			}
 			servers[i].SSHCount = meta.SSHCount
 
 			if meta.LastSeen != "" {
@@ -306,11 +329,24 @@ func (r *Repository) mergeMetadata(servers []domain.Server, metadata map[string]
 				}
 			}
 
-			if meta.PinnedAt != "" {
+			if meta.PinnedAt != "" && (!r.metadataInConfig || server.PinnedAt.IsZero()) {
 				if pinnedAt, err := time.Parse(time.RFC3339, meta.PinnedAt); err == nil {
 					servers[i].PinnedAt = pinnedAt
 				}
 			}
+
+			servers[i].LastError = meta.LastError
+			servers[i].SessionLogging = meta.SessionLogging
+			servers[i].PinOrder = meta.PinOrder
+			servers[i].AutoTunnels = meta.AutoTunnels
+			servers[i].AutoTunnelsDisabled = meta.AutoTunnelsDisabled
+			servers[i].RequiresVPN = meta.RequiresVPN
+			servers[i].PingTarget = meta.PingTarget
+			if meta.LastErrorAt != "" {
+				if lastErrorAt, err := time.Parse(time.RFC3339, meta.LastErrorAt); err == nil {
+					servers[i].LastErrorAt = lastErrorAt
+				}
+			}
 		}
 	}
 	return servers
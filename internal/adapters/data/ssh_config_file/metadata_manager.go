@@ -19,6 +19,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sync"
 	"time"
 
 	"github.com/Adembc/lazyssh/internal/core/domain"
@@ -26,15 +27,29 @@ import (
 )
 
 type ServerMetadata struct {
-	Tags     []string `json:"tags,omitempty"`
-	LastSeen string   `json:"last_seen,omitempty"`
-	PinnedAt string   `json:"pinned_at,omitempty"`
-	SSHCount int      `json:"ssh_count,omitempty"`
+	Tags        []string `json:"tags,omitempty"`
+	LastSeen    string   `json:"last_seen,omitempty"`
+	PinnedAt    string   `json:"pinned_at,omitempty"`
+	SSHCount    int      `json:"ssh_count,omitempty"`
+	LastError   string   `json:"last_error,omitempty"`
+	LastErrorAt string   `json:"last_error_at,omitempty"`
+	// SessionLogging overrides the session_logging config for this server when set.
+	SessionLogging *bool `json:"session_logging,omitempty"`
+	// PinOrder is the manual position among pinned servers; 0 orders by PinnedAt.
+	PinOrder int `json:"pin_order,omitempty"`
+	// AutoTunnels are ssh -L specs added to interactive sessions unless AutoTunnelsDisabled.
+	AutoTunnels         []string `json:"auto_tunnels,omitempty"`
+	AutoTunnelsDisabled bool     `json:"auto_tunnels_disabled,omitempty"`
+	RequiresVPN         bool     `json:"requires_vpn,omitempty"`
+	PingTarget          string   `json:"ping_target,omitempty"`
 }
 
 type metadataManager struct {
 	filePath string
 	logger   *zap.SugaredLogger
+
+	warningsMu sync.Mutex
+	warnings   []string
 }
 
 func newMetadataManager(filePath string, logger *zap.SugaredLogger) *metadataManager {
@@ -42,26 +57,84 @@ func newMetadataManager(filePath string, logger *zap.SugaredLogger) *metadataMan
 }
 
 func (m *metadataManager) loadAll() (map[string]ServerMetadata, error) {
-	metadata := make(map[string]ServerMetadata)
+	metadata, parseErr, err := m.read()
+	if err != nil || parseErr == nil {
+		return metadata, err
+	}
+
+	// Recover under the lock so a save that just replaced the file is not moved aside.
+	lock, err := m.lock()
+	if err != nil {
+		return nil, err
+	}
+	defer m.unlock(lock)
+	return m.loadLocked()
+}
+
+// loadLocked reads the metadata for a caller holding the metadata lock, recovering from a
+// corrupt file.
+func (m *metadataManager) loadLocked() (map[string]ServerMetadata, error) {
+	metadata, parseErr, err := m.read()
+	if err != nil || parseErr == nil {
+		return metadata, err
+	}
+	return m.recoverCorrupt(parseErr)
+}
+
+// read parses the metadata file. A missing or empty file is empty metadata; unparsable
+// content is reported as parseErr.
+func (m *metadataManager) read() (metadata map[string]ServerMetadata, parseErr, err error) {
+	metadata = make(map[string]ServerMetadata)
 
 	if _, err := os.Stat(m.filePath); os.IsNotExist(err) {
-		return metadata, nil
+		return metadata, nil, nil
 	}
 
 	data, err := os.ReadFile(m.filePath)
 	if err != nil {
-		return nil, fmt.Errorf("read metadata '%s': %w", m.filePath, err)
+		return nil, nil, fmt.Errorf("read metadata '%s': %w", m.filePath, err)
 	}
 
 	if len(data) == 0 {
-		return metadata, nil
+		return metadata, nil, nil
 	}
 
 	if err := json.Unmarshal(data, &metadata); err != nil {
-		return nil, fmt.Errorf("parse metadata JSON '%s': %w", m.filePath, err)
+		return nil, err, nil
 	}
 
-	return metadata, nil
+	return metadata, nil, nil
+}
+
+// recoverCorrupt moves an unparsable metadata file aside as metadata.json.corrupt-<ts> and
+// continues with empty metadata, so one bad write does not block every later update. The
+// caller must hold the metadata lock.
+func (m *metadataManager) recoverCorrupt(parseErr error) (map[string]ServerMetadata, error) {
+	backupPath := fmt.Sprintf("%s.corrupt-%s", m.filePath, time.Now().Format("20060102150405"))
+	if err := os.Rename(m.filePath, backupPath); err != nil {
+		m.logger.Errorw("failed to move corrupt metadata aside", "path", m.filePath, "error", err)
+		return nil, fmt.Errorf("parse metadata JSON '%s': %w", m.filePath, parseErr)
+	}
+
+	m.logger.Warnw("metadata file was corrupt; starting with empty metadata",
+		"path", m.filePath, "backup", backupPath, "error", parseErr)
+	m.addWarning(fmt.Sprintf("Metadata was corrupt and has been reset (backup: %s)", backupPath))
+	return make(map[string]ServerMetadata), nil
+}
+
+func (m *metadataManager) addWarning(msg string) {
+	m.warningsMu.Lock()
+	defer m.warningsMu.Unlock()
+	m.warnings = append(m.warnings, msg)
+}
+
+// drainWarnings returns and clears warnings that should be shown to the user.
+func (m *metadataManager) drainWarnings() []string {
+	m.warningsMu.Lock()
+	defer m.warningsMu.Unlock()
+	w := m.warnings
+	m.warnings = nil
+	return w
 }
 
 func (m *metadataManager) saveAll(metadata map[string]ServerMetadata) error {
@@ -77,15 +150,58 @@ func (m *metadataManager) saveAll(metadata map[string]ServerMetadata) error {
 		return fmt.Errorf("marshal metadata for '%s': %w", m.filePath, err)
 	}
 
-	if err := os.WriteFile(m.filePath, data, 0o600); err != nil {
+	if err := m.writeAtomic(data); err != nil {
 		m.logger.Errorw("failed to write metadata file", "path", m.filePath, "error", err)
 		return fmt.Errorf("write metadata '%s': %w", m.filePath, err)
 	}
 	return nil
 }
 
+// writeAtomic writes data to a temporary file next to the metadata file and renames it
+// into place, so a crash mid-write never leaves a truncated metadata.json behind.
+func (m *metadataManager) writeAtomic(data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(m.filePath), filepath.Base(m.filePath)+".*"+TempSuffix)
+	if err != nil {
+		return fmt.Errorf("create temporary file: %w", err)
+	}
+	tmpPath := tmp.Name()
+	defer func() {
+		// Only present if something failed before the rename.
+		if rerr := os.Remove(tmpPath); rerr != nil && !os.IsNotExist(rerr) {
+			m.logger.Warnw("failed to remove temporary metadata file", "path", tmpPath, "error", rerr)
+		}
+	}()
+
+	if err := tmp.Chmod(0o600); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("chmod temporary file: %w", err)
+	}
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("write temporary file: %w", err)
+	}
+	if err := tmp.Sync(); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("sync temporary file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("close temporary file: %w", err)
+	}
+
+	if err := os.Rename(tmpPath, m.filePath); err != nil {
+		return fmt.Errorf("replace metadata file: %w", err)
+	}
+	return nil
+}
+
 func (m *metadataManager) updateServer(server domain.Server, oldAlias string) error {
-	metadata, err := m.loadAll()
+	lock, err := m.lock()
+	if err != nil {
+		return err
+	}
+	defer m.unlock(lock)
+
+	metadata, err := m.loadLocked()
 	if err != nil {
 		m.logger.Errorw("failed to load metadata in updateServer", "path", m.filePath, "alias", server.Alias, "old_alias", oldAlias, "error", err)
 		return fmt.Errorf("load metadata: %w", err)
@@ -103,6 +219,8 @@ func (m *metadataManager) updateServer(server domain.Server, oldAlias string) er
 	merged := existing
 
 	merged.Tags = server.Tags
+	merged.RequiresVPN = server.RequiresVPN
+	merged.PingTarget = server.PingTarget
 
 	if !server.LastSeen.IsZero() {
 		merged.LastSeen = server.LastSeen.Format(time.RFC3339)
@@ -121,7 +239,13 @@ func (m *metadataManager) updateServer(server domain.Server, oldAlias string) er
 }
 
 func (m *metadataManager) deleteServer(alias string) error {
-	metadata, err := m.loadAll()
+	lock, err := m.lock()
+	if err != nil {
+		return err
+	}
+	defer m.unlock(lock)
+
+	metadata, err := m.loadLocked()
 	if err != nil {
 		m.logger.Errorw("failed to load metadata in deleteServer", "path", m.filePath, "alias", alias, "error", err)
 		return fmt.Errorf("load metadata: %w", err)
@@ -132,7 +256,13 @@ func (m *metadataManager) deleteServer(alias string) error {
 }
 
 func (m *metadataManager) setPinned(alias string, pinned bool) error {
-	metadata, err := m.loadAll()
+	lock, err := m.lock()
+	if err != nil {
+		return err
+	}
+	defer m.unlock(lock)
+
+	metadata, err := m.loadLocked()
 	if err != nil {
 		m.logger.Errorw("failed to load metadata in setPinned", "path", m.filePath, "alias", alias, "pinned", pinned, "error", err)
 		return fmt.Errorf("load metadata: %w", err)
@@ -143,14 +273,82 @@ func (m *metadataManager) setPinned(alias string, pinned bool) error {
 		meta.PinnedAt = time.Now().Format(time.RFC3339)
 	} else {
 		meta.PinnedAt = ""
+		meta.PinOrder = 0
 	}
 
 	metadata[alias] = meta
 	return m.saveAll(metadata)
 }
 
+func (m *metadataManager) setSessionLogging(alias string, enabled *bool) error {
+	lock, err := m.lock()
+	if err != nil {
+		return err
+	}
+	defer m.unlock(lock)
+
+	metadata, err := m.loadLocked()
+	if err != nil {
+		m.logger.Errorw("failed to load metadata in setSessionLogging", "path", m.filePath, "alias", alias, "error", err)
+		return fmt.Errorf("load metadata: %w", err)
+	}
+
+	meta := metadata[alias]
+	meta.SessionLogging = enabled
+	metadata[alias] = meta
+	return m.saveAll(metadata)
+}
+
+func (m *metadataManager) setAutoTunnels(alias string, specs []string, disabled bool) error {
+	lock, err := m.lock()
+	if err != nil {
+		return err
+	}
+	defer m.unlock(lock)
+
+	metadata, err := m.loadLocked()
+	if err != nil {
+		m.logger.Errorw("failed to load metadata in setAutoTunnels", "path", m.filePath, "alias", alias, "error", err)
+		return fmt.Errorf("load metadata: %w", err)
+	}
+
+	meta := metadata[alias]
+	meta.AutoTunnels = specs
+	meta.AutoTunnelsDisabled = disabled
+	metadata[alias] = meta
+	return m.saveAll(metadata)
+}
+
+// setPinOrder stores manual pin positions for several servers at once.
+func (m *metadataManager) setPinOrder(orders map[string]int) error {
+	lock, err := m.lock()
+	if err != nil {
+		return err
+	}
+	defer m.unlock(lock)
+
+	metadata, err := m.loadLocked()
+	if err != nil {
+		m.logger.Errorw("failed to load metadata in setPinOrder", "path", m.filePath, "error", err)
+		return fmt.Errorf("load metadata: %w", err)
+	}
+
+	for alias, order := range orders {
+		meta := metadata[alias]
+		meta.PinOrder = order
+		metadata[alias] = meta
+	}
+	return m.saveAll(metadata)
+}
+
 func (m *metadataManager) recordSSH(alias string) error {
-	metadata, err := m.loadAll()
+	lock, err := m.lock()
+	if err != nil {
+		return err
+	}
+	defer m.unlock(lock)
+
+	metadata, err := m.loadLocked()
 	if err != nil {
 		m.logger.Errorw("failed to load metadata in recordSSH", "path", m.filePath, "alias", alias, "error", err)
 		return fmt.Errorf("load metadata: %w", err)
@@ -159,11 +357,94 @@ func (m *metadataManager) recordSSH(alias string) error {
 	meta := metadata[alias]
 	meta.LastSeen = time.Now().Format(time.RFC3339)
 	meta.SSHCount++
+	meta.LastError = ""
+	meta.LastErrorAt = ""
 
 	metadata[alias] = meta
 	return m.saveAll(metadata)
 }
 
+func (m *metadataManager) recordSSHError(alias, message string) error {
+	lock, err := m.lock()
+	if err != nil {
+		return err
+	}
+	defer m.unlock(lock)
+
+	metadata, err := m.loadLocked()
+	if err != nil {
+		m.logger.Errorw("failed to load metadata in recordSSHError", "path", m.filePath, "alias", alias, "error", err)
+		return fmt.Errorf("load metadata: %w", err)
+	}
+
+	meta := metadata[alias]
+	meta.LastError = message
+	meta.LastErrorAt = time.Now().Format(time.RFC3339)
+
+	metadata[alias] = meta
+	return m.saveAll(metadata)
+}
+
+func (m *metadataManager) renameTag(oldTag, newTag string) (int, error) {
+	return m.rewriteTags(oldTag, newTag)
+}
+
+func (m *metadataManager) deleteTag(tag string) (int, error) {
+	return m.rewriteTags(tag, "")
+}
+
+// rewriteTags replaces oldTag with newTag (or drops it when newTag is empty) on every server
+// and returns how many servers were changed.
+func (m *metadataManager) rewriteTags(oldTag, newTag string) (int, error) {
+	lock, err := m.lock()
+	if err != nil {
+		return 0, err
+	}
+	defer m.unlock(lock)
+
+	metadata, err := m.loadLocked()
+	if err != nil {
+		m.logger.Errorw("failed to load metadata in rewriteTags", "path", m.filePath, "tag", oldTag, "error", err)
+		return 0, fmt.Errorf("load metadata: %w", err)
+	}
+
+	changed := 0
+	for alias, meta := range metadata {
+		tags, ok := replaceTag(meta.Tags, oldTag, newTag)
+		if !ok {
+			continue
+		}
+		meta.Tags = tags
+		metadata[alias] = meta
+		changed++
+	}
+	if changed == 0 {
+		return 0, nil
+	}
+	return changed, m.saveAll(metadata)
+}
+
+// location returns the path of the metadata file.
+func (m *metadataManager) location() string {
+	return m.filePath
+}
+
+// lock takes the inter-process lock guarding metadata read-modify-write cycles.
+func (m *metadataManager) lock() (*fileLock, error) {
+	lock, err := acquireFileLock(m.filePath + ".lock")
+	if err != nil {
+		m.logger.Errorw("failed to lock metadata", "path", m.filePath, "error", err)
+		return nil, fmt.Errorf("lock metadata: %w", err)
+	}
+	return lock, nil
+}
+
+func (m *metadataManager) unlock(lock *fileLock) {
+	if err := lock.release(); err != nil {
+		m.logger.Warnw("failed to release metadata lock", "path", m.filePath, "error", err)
+	}
+}
+
 func (m *metadataManager) ensureDirectory() error {
 	dir := filepath.Dir(m.filePath)
 	if err := os.MkdirAll(dir, 0o750); err != nil {
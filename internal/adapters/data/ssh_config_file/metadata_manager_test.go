@@ -0,0 +1,146 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ssh_config_file
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Adembc/lazyssh/internal/core/domain"
+	"go.uber.org/zap"
+)
+
+func TestMetadataManagerRecoversFromCorruptFile(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+	}{
+		{name: "truncated JSON", content: `{"web": {"tags": ["prod"], "ssh_cou`},
+		{name: "not JSON", content: "garbage"},
+		{name: "wrong shape", content: `["web", "db"]`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			path := filepath.Join(dir, "metadata.json")
+			if err := os.WriteFile(path, []byte(tt.content), 0o600); err != nil {
+				t.Fatal(err)
+			}
+
+			m := newMetadataManager(path, zap.NewNop().Sugar())
+			metadata, err := m.loadAll()
+			if err != nil {
+				t.Fatalf("loadAll() error = %v, want nil", err)
+			}
+			if len(metadata) != 0 {
+				t.Errorf("loadAll() = %v, want empty", metadata)
+			}
+
+			matches, _ := filepath.Glob(path + ".corrupt-*")
+			if len(matches) != 1 {
+				t.Fatalf("expected one corrupt backup, got %v", matches)
+			}
+			backup, err := os.ReadFile(matches[0])
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(backup) != tt.content {
+				t.Errorf("backup content = %q, want %q", backup, tt.content)
+			}
+
+			warnings := m.drainWarnings()
+			if len(warnings) != 1 || !strings.Contains(warnings[0], matches[0]) {
+				t.Errorf("drainWarnings() = %v, want one warning naming the backup", warnings)
+			}
+			if again := m.drainWarnings(); len(again) != 0 {
+				t.Errorf("warnings not cleared: %v", again)
+			}
+
+			// Writes work again after recovery.
+			if err := m.updateServer(domain.Server{Alias: "web", Tags: []string{"prod"}}, "web"); err != nil {
+				t.Fatalf("updateServer() after recovery: %v", err)
+			}
+		})
+	}
+}
+
+func TestMetadataRecoveryWaitsForWriter(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "metadata.json")
+	if err := os.WriteFile(path, []byte("garbage"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	// Another instance holds the lock while it replaces the corrupt file.
+	writer := newMetadataManager(path, zap.NewNop().Sugar())
+	lock, err := writer.lock()
+	if err != nil {
+		t.Fatal(err)
+	}
+	reader := newMetadataManager(path, zap.NewNop().Sugar())
+	done := make(chan map[string]ServerMetadata)
+	go func() {
+		metadata, err := reader.loadAll()
+		if err != nil {
+			t.Errorf("loadAll() error = %v", err)
+		}
+		done <- metadata
+	}()
+	time.Sleep(50 * time.Millisecond)
+	if err := writer.saveAll(map[string]ServerMetadata{"web": {Tags: []string{"prod"}}}); err != nil {
+		t.Fatal(err)
+	}
+	writer.unlock(lock)
+
+	metadata := <-done
+	if got := metadata["web"].Tags; len(got) != 1 || got[0] != "prod" {
+		t.Errorf("loadAll() = %v, want the writer's metadata", metadata)
+	}
+	if matches, _ := filepath.Glob(path + ".corrupt-*"); len(matches) != 0 {
+		t.Errorf("freshly written metadata was moved aside: %v", matches)
+	}
+}
+
+func TestReplaceTag(t *testing.T) {
+	tests := []struct {
+		name        string
+		tags        []string
+		oldTag      string
+		newTag      string
+		expected    []string
+		wantChanged bool
+	}{
+		{name: "rename", tags: []string{"prod", "web"}, oldTag: "prod", newTag: "production", expected: []string{"production", "web"}, wantChanged: true},
+		{name: "rename merges duplicate", tags: []string{"prod", "production"}, oldTag: "prod", newTag: "production", expected: []string{"production"}, wantChanged: true},
+		{name: "delete", tags: []string{"prod", "web"}, oldTag: "prod", newTag: "", expected: []string{"web"}, wantChanged: true},
+		{name: "absent tag", tags: []string{"web"}, oldTag: "prod", newTag: "production", expected: []string{"web"}, wantChanged: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, changed := replaceTag(tt.tags, tt.oldTag, tt.newTag)
+			if changed != tt.wantChanged {
+				t.Errorf("replaceTag changed = %v, want %v", changed, tt.wantChanged)
+			}
+			if strings.Join(got, ",") != strings.Join(tt.expected, ",") {
+				t.Errorf("replaceTag(%v, %q, %q) = %v, want %v", tt.tags, tt.oldTag, tt.newTag, got, tt.expected)
+			}
+		})
+	}
+}
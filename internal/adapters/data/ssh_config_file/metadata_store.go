@@ -0,0 +1,79 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ssh_config_file
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/Adembc/lazyssh/internal/config"
+	"github.com/Adembc/lazyssh/internal/core/domain"
+	"go.uber.org/zap"
+)
+
+// metadataStore persists lazyssh-specific server metadata (tags, pins, usage stats)
+// that has no place in the SSH config itself.
+type metadataStore interface {
+	loadAll() (map[string]ServerMetadata, error)
+	updateServer(server domain.Server, oldAlias string) error
+	deleteServer(alias string) error
+	setPinned(alias string, pinned bool) error
+	setPinOrder(orders map[string]int) error
+	setSessionLogging(alias string, enabled *bool) error
+	setAutoTunnels(alias string, specs []string, disabled bool) error
+	recordSSH(alias string) error
+	recordSSHError(alias, message string) error
+	renameTag(oldTag, newTag string) (int, error)
+	deleteTag(tag string) (int, error)
+	drainWarnings() []string
+	location() string
+}
+
+// newMetadataStore returns the metadata backend selected in the config. The SQLite
+// database lives next to the JSON file with a .db extension; if it cannot be opened
+// the JSON backend is used instead so the app stays usable.
+func newMetadataStore(backend, jsonPath string, logger *zap.SugaredLogger) metadataStore {
+	jsonStore := newMetadataManager(jsonPath, logger)
+	if backend != config.MetadataBackendSQLite {
+		return jsonStore
+	}
+
+	dbPath := strings.TrimSuffix(jsonPath, filepath.Ext(jsonPath)) + ".db"
+	store, err := newSQLiteMetadataStore(dbPath, jsonStore, logger)
+	if err != nil {
+		logger.Errorw("failed to open sqlite metadata store, falling back to JSON", "path", dbPath, "error", err)
+		return jsonStore
+	}
+	return store
+}
+
+// replaceTag returns tags with every occurrence of oldTag replaced by newTag (or removed when
+// newTag is empty), without introducing duplicates. changed reports whether oldTag was present.
+func replaceTag(tags []string, oldTag, newTag string) (out []string, changed bool) {
+	out = make([]string, 0, len(tags))
+	seen := make(map[string]bool, len(tags))
+	for _, t := range tags {
+		if t == oldTag {
+			changed = true
+			t = newTag
+		}
+		if t == "" || seen[t] {
+			continue
+		}
+		seen[t] = true
+		out = append(out, t)
+	}
+	return out, changed
+}
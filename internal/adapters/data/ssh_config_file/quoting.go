@@ -0,0 +1,112 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ssh_config_file
+
+import (
+	"strings"
+
+	"github.com/kevinburke/ssh_config"
+)
+
+// singleArgKeys lists directives whose value is one argument, so a quoted value is
+// unquoted on read and a value with spaces is quoted on write. Directives such as
+// ProxyCommand, RemoteCommand or LocalForward keep their raw text.
+var singleArgKeys = map[string]bool{
+	"hostname":        true,
+	"user":            true,
+	"identityfile":    true,
+	"certificatefile": true,
+	"identityagent":   true,
+	"controlpath":     true,
+}
+
+// splitArgs splits s into arguments the way OpenSSH does: on unquoted whitespace, with
+// double or single quotes grouping words. \\, \" and \' are escapes everywhere and an
+// escaped space joins words outside quotes; any other backslash is kept, so unquoted
+// values such as DOMAIN\user or C:\keys\id read back unchanged.
+func splitArgs(s string) []string {
+	var args []string
+	var cur strings.Builder
+	inArg := false
+	var quote byte
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c == '\\' && i+1 < len(s) && (s[i+1] == '\\' || s[i+1] == '"' || s[i+1] == '\'' || (quote == 0 && s[i+1] == ' ')):
+			i++
+			cur.WriteByte(s[i])
+			inArg = true
+		case quote != 0 && c == quote:
+			quote = 0
+		case quote != 0:
+			cur.WriteByte(c)
+		case c == '"' || c == '\'':
+			quote = c
+			inArg = true
+		case c == ' ' || c == '\t':
+			if inArg {
+				args = append(args, cur.String())
+				cur.Reset()
+				inArg = false
+			}
+		default:
+			cur.WriteByte(c)
+			inArg = true
+		}
+	}
+	if inArg {
+		args = append(args, cur.String())
+	}
+	return args
+}
+
+// quoteArg wraps s in double quotes when it would otherwise be split or misread. A lone
+// backslash (DOMAIN\user) is left bare since OpenSSH keeps it; a doubled one is not.
+func quoteArg(s string) string {
+	if s == "" || (!strings.ContainsAny(s, " \t\"'#") && !strings.Contains(s, `\\`)) {
+		return s
+	}
+	r := strings.NewReplacer("\\", "\\\\", "\"", "\\\"")
+	return "\"" + r.Replace(s) + "\""
+}
+
+// unquoteValue returns the value of a single-argument directive with any quoting removed.
+func unquoteValue(key, value string) string {
+	if !singleArgKeys[strings.ToLower(key)] || !strings.ContainsAny(value, "\"'\\") {
+		return value
+	}
+	if args := splitArgs(value); len(args) == 1 {
+		return args[0]
+	}
+	return value
+}
+
+// configValue returns value as it should be written for key.
+func configValue(key, value string) string {
+	if !singleArgKeys[strings.ToLower(key)] {
+		return value
+	}
+	return quoteArg(value)
+}
+
+// hostPatterns returns the patterns of host with quoting resolved. The parser splits
+// Host lines on spaces, so "staging box" arrives as two tokens and is rejoined here.
+func hostPatterns(host *ssh_config.Host) []string {
+	tokens := make([]string, 0, len(host.Patterns))
+	for _, pattern := range host.Patterns {
+		tokens = append(tokens, pattern.String())
+	}
+	return splitArgs(strings.Join(tokens, " "))
+}
@@ -0,0 +1,226 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ssh_config_file
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Adembc/lazyssh/internal/core/domain"
+	"github.com/kevinburke/ssh_config"
+)
+
+func TestSplitArgs(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected []string
+	}{
+		{name: "plain", input: "web db", expected: []string{"web", "db"}},
+		{name: "double quoted", input: `"staging box" web`, expected: []string{"staging box", "web"}},
+		{name: "single quoted", input: `'staging box'`, expected: []string{"staging box"}},
+		{name: "escaped backslash", input: `"domain\\user"`, expected: []string{`domain\user`}},
+		{name: "escaped quote", input: `"say \"hi\""`, expected: []string{`say "hi"`}},
+		{name: "unquoted backslash kept", input: `C:\keys\id`, expected: []string{`C:\keys\id`}},
+		{name: "unquoted escaped backslash", input: `EXAMPLE\\jdoe`, expected: []string{`EXAMPLE\jdoe`}},
+		{name: "unquoted escaped space", input: `my\ key other`, expected: []string{"my key", "other"}},
+		{name: "extra whitespace", input: "  a \t b  ", expected: []string{"a", "b"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := splitArgs(tt.input)
+			if strings.Join(result, "|") != strings.Join(tt.expected, "|") {
+				t.Errorf("splitArgs(%q) = %q, want %q", tt.input, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestQuotedHostAndValues(t *testing.T) {
+	input := "Host \"staging box\" staging\n" +
+		"    HostName 10.0.0.5\n" +
+		"    User \"domain\\\\user\"\n" +
+		"    IdentityFile \"~/.ssh/my key\"\n"
+
+	cfg, err := ssh_config.Decode(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+
+	r := &Repository{}
+	servers := r.toDomainServer(cfg)
+	if len(servers) != 1 {
+		t.Fatalf("toDomainServer() returned %d servers, want 1", len(servers))
+	}
+	s := servers[0]
+	if s.Alias != "staging box" {
+		t.Errorf("Alias = %q, want %q", s.Alias, "staging box")
+	}
+	if strings.Join(s.Aliases, "|") != "staging box|staging" {
+		t.Errorf("Aliases = %q, want [staging box staging]", s.Aliases)
+	}
+	if s.User != `domain\user` {
+		t.Errorf("User = %q, want %q", s.User, `domain\user`)
+	}
+	if len(s.IdentityFiles) != 1 || s.IdentityFiles[0] != "~/.ssh/my key" {
+		t.Errorf("IdentityFiles = %q, want [~/.ssh/my key]", s.IdentityFiles)
+	}
+	if r.findHostByAlias(cfg, "staging box") == nil {
+		t.Errorf("findHostByAlias(%q) = nil, want host", "staging box")
+	}
+}
+
+func TestQuotedValuesOnWrite(t *testing.T) {
+	r := &Repository{}
+	host := r.createHostFromServer(domain.Server{
+		Alias:         "staging box",
+		Host:          "10.0.0.5",
+		User:          `domain user`,
+		IdentityFiles: []string{"~/.ssh/my key"},
+	})
+
+	out := host.String()
+	for _, want := range []string{
+		`Host "staging box"`,
+		`User "domain user"`,
+		`IdentityFile "~/.ssh/my key"`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("createHostFromServer() output missing %q:\n%s", want, out)
+		}
+	}
+
+	cfg, err := ssh_config.Decode(strings.NewReader(out))
+	if err != nil {
+		t.Fatalf("Decode() of written host error = %v", err)
+	}
+	servers := r.toDomainServer(cfg)
+	if len(servers) != 1 || servers[0].Alias != "staging box" || servers[0].User != "domain user" {
+		t.Errorf("round trip = %+v, want alias %q and user %q", servers, "staging box", "domain user")
+	}
+}
+
+func TestMultipleIdentityFilesOnOneLine(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		want  []string
+	}{
+		{name: "single", value: "~/.ssh/id_ed25519", want: []string{"~/.ssh/id_ed25519"}},
+		{name: "space separated", value: "~/.ssh/id_a ~/.ssh/id_b", want: []string{"~/.ssh/id_a", "~/.ssh/id_b"}},
+		{name: "quoted path with space", value: `"~/.ssh/my key" ~/.ssh/id_b`, want: []string{"~/.ssh/my key", "~/.ssh/id_b"}},
+		{name: "tabs and escapes", value: "~/.ssh/id_a\t'~/.ssh/other key'\t~/my\\ key", want: []string{"~/.ssh/id_a", "~/.ssh/other key", "~/my key"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			input := "Host web\n    HostName web.lan\n    IdentityFile " + tt.value + "\n"
+			cfg, err := ssh_config.Decode(strings.NewReader(input))
+			if err != nil {
+				t.Fatalf("Decode() error = %v", err)
+			}
+			r := &Repository{}
+			servers := r.toDomainServer(cfg)
+			if len(servers) != 1 {
+				t.Fatalf("toDomainServer() returned %d servers, want 1", len(servers))
+			}
+			if got := servers[0].IdentityFiles; strings.Join(got, "|") != strings.Join(tt.want, "|") {
+				t.Errorf("IdentityFiles = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMultipleIdentityFilesWrittenOnePerLine(t *testing.T) {
+	input := "Host web\n    HostName web.lan\n    IdentityFile ~/.ssh/id_a \"~/.ssh/my key\"\n"
+	cfg, err := ssh_config.Decode(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+	r := &Repository{}
+	server := r.toDomainServer(cfg)[0]
+	host := r.findHostByAlias(cfg, "web")
+	r.updateHostNodes(host, server)
+
+	var lines []string
+	for _, node := range host.Nodes {
+		if kv, ok := node.(*ssh_config.KV); ok && kv.Key == "IdentityFile" {
+			lines = append(lines, kv.Value)
+		}
+	}
+	want := []string{"~/.ssh/id_a", `"~/.ssh/my key"`}
+	if strings.Join(lines, "|") != strings.Join(want, "|") {
+		t.Errorf("IdentityFile lines = %q, want %q", lines, want)
+	}
+}
+
+func TestDomainUserRoundTrip(t *testing.T) {
+	tests := []struct {
+		name    string
+		line    string
+		written string
+	}{
+		{name: "bare", line: `User EXAMPLE\jdoe`, written: `User EXAMPLE\jdoe`},
+		{name: "escaped", line: `User EXAMPLE\\jdoe`, written: `User EXAMPLE\jdoe`},
+		{name: "quoted", line: `User "EXAMPLE\\jdoe"`, written: `User EXAMPLE\jdoe`},
+	}
+
+	r := &Repository{}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg, err := ssh_config.Decode(strings.NewReader("Host ad\n    HostName dc.example.com\n    " + tt.line + "\n"))
+			if err != nil {
+				t.Fatalf("Decode() error = %v", err)
+			}
+			servers := r.toDomainServer(cfg)
+			if len(servers) != 1 || servers[0].User != `EXAMPLE\jdoe` {
+				t.Fatalf("read servers = %+v, want user %q", servers, `EXAMPLE\jdoe`)
+			}
+
+			out := r.createHostFromServer(servers[0]).String()
+			if !strings.Contains(out, tt.written) {
+				t.Errorf("written host missing %q:\n%s", tt.written, out)
+			}
+			cfg, err = ssh_config.Decode(strings.NewReader(out))
+			if err != nil {
+				t.Fatalf("Decode() of written host error = %v", err)
+			}
+			if got := r.toDomainServer(cfg); len(got) != 1 || got[0].User != `EXAMPLE\jdoe` {
+				t.Errorf("round trip = %+v, want user %q", got, `EXAMPLE\jdoe`)
+			}
+		})
+	}
+
+	if got := quoteArg(`a\\b`); got != `"a\\\\b"` {
+		t.Errorf("quoteArg(%q) = %q, want %q", `a\\b`, got, `"a\\\\b"`)
+	}
+}
+
+func TestUpdateOrAddKVNodeKeepsQuoting(t *testing.T) {
+	r := &Repository{}
+	host := &ssh_config.Host{}
+	host.Nodes = append(host.Nodes, &ssh_config.KV{Key: "User", Value: `"domain\\user"`})
+
+	r.updateOrAddKVNode(host, "User", `domain\user`)
+	if got := host.Nodes[0].(*ssh_config.KV).Value; got != `"domain\\user"` {
+		t.Errorf("unchanged value rewritten to %q", got)
+	}
+
+	r.updateOrAddKVNode(host, "User", "other user")
+	if got := host.Nodes[0].(*ssh_config.KV).Value; got != `"other user"` {
+		t.Errorf("updated value = %q, want %q", got, `"other user"`)
+	}
+}
@@ -0,0 +1,464 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ssh_config_file
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/Adembc/lazyssh/internal/core/domain"
+	"go.uber.org/zap"
+
+	// Registers the pure-Go "sqlite" database/sql driver.
+	_ "modernc.org/sqlite"
+)
+
+const sqliteMetadataSchema = `
+CREATE TABLE IF NOT EXISTS metadata (
+	alias         TEXT PRIMARY KEY,
+	tags          TEXT NOT NULL DEFAULT '',
+	last_seen     TEXT NOT NULL DEFAULT '',
+	pinned_at     TEXT NOT NULL DEFAULT '',
+	ssh_count     INTEGER NOT NULL DEFAULT 0,
+	last_error    TEXT NOT NULL DEFAULT '',
+	last_error_at TEXT NOT NULL DEFAULT ''
+)`
+
+// sqliteMetadataMigrations lists columns added after the initial schema. They are added
+// to existing databases on open; new columns must be nullable or have a default.
+var sqliteMetadataMigrations = []struct {
+	column     string
+	definition string
+}{
+	{column: "session_logging", definition: "INTEGER"},
+	{column: "pin_order", definition: "INTEGER NOT NULL DEFAULT 0"},
+	{column: "auto_tunnels", definition: "TEXT NOT NULL DEFAULT ''"},
+	{column: "auto_tunnels_disabled", definition: "INTEGER NOT NULL DEFAULT 0"},
+	{column: "requires_vpn", definition: "INTEGER NOT NULL DEFAULT 0"},
+	{column: "ping_target", definition: "TEXT NOT NULL DEFAULT ''"},
+}
+
+// sqliteMetadataColumns is the column list shared by every SELECT and the upsert.
+const sqliteMetadataColumns = "tags, last_seen, pinned_at, ssh_count, last_error, last_error_at, session_logging, pin_order, auto_tunnels, auto_tunnels_disabled, requires_vpn, ping_target"
+
+// sqliteMetadataStore keeps server metadata in a SQLite database. Every mutation touches
+// only the affected rows inside a single transaction, so concurrent lazyssh instances
+// do not overwrite each other's changes the way whole-file JSON rewrites can.
+type sqliteMetadataStore struct {
+	db     *sql.DB
+	path   string
+	logger *zap.SugaredLogger
+}
+
+// sqliteDSN returns the file: URI for the database at path with the given query parameters.
+// The path is escaped, so a ?, # or % in it stays part of the file name.
+func sqliteDSN(path, query string) string {
+	if abs, err := filepath.Abs(path); err == nil {
+		path = abs
+	}
+	path = filepath.ToSlash(path)
+	if !strings.HasPrefix(path, "/") {
+		path = "/" + path // a Windows drive letter, as in file:///C:/...
+	}
+	return (&url.URL{Scheme: "file", Path: path, RawQuery: query}).String()
+}
+
+// newSQLiteMetadataStore opens (or creates) the database at path. When the database is new,
+// existing entries from the JSON store are imported so switching backends keeps tags and history.
+func newSQLiteMetadataStore(path string, legacy *metadataManager, logger *zap.SugaredLogger) (*sqliteMetadataStore, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		return nil, fmt.Errorf("mkdir '%s': %w", filepath.Dir(path), err)
+	}
+
+	db, err := sql.Open("sqlite", sqliteDSN(path, "_pragma=busy_timeout(5000)&_pragma=journal_mode(WAL)&_txlock=immediate"))
+	if err != nil {
+		return nil, fmt.Errorf("open sqlite metadata '%s': %w", path, err)
+	}
+	if _, err := db.Exec(sqliteMetadataSchema); err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("create sqlite metadata schema '%s': %w", path, err)
+	}
+	if err := migrateSQLiteMetadata(db); err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("migrate sqlite metadata schema '%s': %w", path, err)
+	}
+	if err := os.Chmod(path, 0o600); err != nil {
+		logger.Warnw("failed to restrict sqlite metadata permissions", "path", path, "error", err)
+	}
+
+	s := &sqliteMetadataStore{db: db, path: path, logger: logger}
+	if legacy != nil {
+		if err := s.importFrom(legacy); err != nil {
+			logger.Warnw("failed to import JSON metadata into sqlite", "path", path, "error", err)
+		}
+	}
+	return s, nil
+}
+
+// migrateSQLiteMetadata adds any columns from sqliteMetadataMigrations the table lacks.
+func migrateSQLiteMetadata(db *sql.DB) error {
+	rows, err := db.Query("PRAGMA table_info(metadata)")
+	if err != nil {
+		return err
+	}
+	existing := make(map[string]bool)
+	for rows.Next() {
+		var (
+			cid, notNull, pk int
+			name, colType    string
+			defaultValue     sql.NullString
+		)
+		if err := rows.Scan(&cid, &name, &colType, &notNull, &defaultValue, &pk); err != nil {
+			_ = rows.Close()
+			return err
+		}
+		existing[name] = true
+	}
+	if err := rows.Close(); err != nil {
+		return err
+	}
+
+	for _, m := range sqliteMetadataMigrations {
+		if existing[m.column] {
+			continue
+		}
+		if _, err := db.Exec(fmt.Sprintf("ALTER TABLE metadata ADD COLUMN %s %s", m.column, m.definition)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// importFrom copies all entries from the JSON store when the database is still empty.
+func (s *sqliteMetadataStore) importFrom(legacy *metadataManager) error {
+	var count int
+	if err := s.db.QueryRow("SELECT COUNT(*) FROM metadata").Scan(&count); err != nil {
+		return err
+	}
+	if count > 0 {
+		return nil
+	}
+
+	metadata, err := legacy.loadAll()
+	if err != nil || len(metadata) == 0 {
+		return err
+	}
+
+	return s.withTx(func(tx *sql.Tx) error {
+		for alias, meta := range metadata {
+			if err := putMetadata(tx, alias, meta); err != nil {
+				return err
+			}
+		}
+		s.logger.Infow("imported JSON metadata into sqlite", "path", s.path, "count", len(metadata))
+		return nil
+	})
+}
+
+func (s *sqliteMetadataStore) loadAll() (map[string]ServerMetadata, error) {
+	rows, err := s.db.Query("SELECT alias, " + sqliteMetadataColumns + " FROM metadata")
+	if err != nil {
+		return nil, fmt.Errorf("query sqlite metadata '%s': %w", s.path, err)
+	}
+	defer func() {
+		if cerr := rows.Close(); cerr != nil {
+			s.logger.Warnw("failed to close sqlite rows", "path", s.path, "error", cerr)
+		}
+	}()
+
+	metadata := make(map[string]ServerMetadata)
+	for rows.Next() {
+		var alias string
+		meta, err := scanMetadata(rows, &alias)
+		if err != nil {
+			return nil, fmt.Errorf("scan sqlite metadata '%s': %w", s.path, err)
+		}
+		metadata[alias] = meta
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("read sqlite metadata '%s': %w", s.path, err)
+	}
+	return metadata, nil
+}
+
+func (s *sqliteMetadataStore) updateServer(server domain.Server, oldAlias string) error {
+	return s.withTx(func(tx *sql.Tx) error {
+		if oldAlias != server.Alias {
+			oldMeta, ok, err := getMetadata(tx, oldAlias)
+			if err != nil {
+				return err
+			}
+			if ok {
+				if err := putMetadata(tx, server.Alias, oldMeta); err != nil {
+					return err
+				}
+			}
+			if _, err := tx.Exec("DELETE FROM metadata WHERE alias = ?", oldAlias); err != nil {
+				return err
+			}
+		}
+
+		merged, _, err := getMetadata(tx, server.Alias)
+		if err != nil {
+			return err
+		}
+		merged.Tags = server.Tags
+		merged.RequiresVPN = server.RequiresVPN
+		merged.PingTarget = server.PingTarget
+		if !server.LastSeen.IsZero() {
+			merged.LastSeen = server.LastSeen.Format(time.RFC3339)
+		}
+		if !server.PinnedAt.IsZero() {
+			merged.PinnedAt = server.PinnedAt.Format(time.RFC3339)
+		}
+		if server.SSHCount > 0 {
+			merged.SSHCount = server.SSHCount
+		}
+		return putMetadata(tx, server.Alias, merged)
+	})
+}
+
+func (s *sqliteMetadataStore) deleteServer(alias string) error {
+	return s.withTx(func(tx *sql.Tx) error {
+		_, err := tx.Exec("DELETE FROM metadata WHERE alias = ?", alias)
+		return err
+	})
+}
+
+func (s *sqliteMetadataStore) setPinned(alias string, pinned bool) error {
+	return s.modify(alias, func(meta *ServerMetadata) {
+		if pinned {
+			meta.PinnedAt = time.Now().Format(time.RFC3339)
+		} else {
+			meta.PinnedAt = ""
+			meta.PinOrder = 0
+		}
+	})
+}
+
+func (s *sqliteMetadataStore) setPinOrder(orders map[string]int) error {
+	return s.withTx(func(tx *sql.Tx) error {
+		for alias, order := range orders {
+			meta, _, err := getMetadata(tx, alias)
+			if err != nil {
+				return err
+			}
+			meta.PinOrder = order
+			if err := putMetadata(tx, alias, meta); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (s *sqliteMetadataStore) setSessionLogging(alias string, enabled *bool) error {
+	return s.modify(alias, func(meta *ServerMetadata) {
+		meta.SessionLogging = enabled
+	})
+}
+
+func (s *sqliteMetadataStore) setAutoTunnels(alias string, specs []string, disabled bool) error {
+	return s.modify(alias, func(meta *ServerMetadata) {
+		meta.AutoTunnels = specs
+		meta.AutoTunnelsDisabled = disabled
+	})
+}
+
+func (s *sqliteMetadataStore) recordSSH(alias string) error {
+	return s.modify(alias, func(meta *ServerMetadata) {
+		meta.LastSeen = time.Now().Format(time.RFC3339)
+		meta.SSHCount++
+		meta.LastError = ""
+		meta.LastErrorAt = ""
+	})
+}
+
+func (s *sqliteMetadataStore) recordSSHError(alias, message string) error {
+	return s.modify(alias, func(meta *ServerMetadata) {
+		meta.LastError = message
+		meta.LastErrorAt = time.Now().Format(time.RFC3339)
+	})
+}
+
+func (s *sqliteMetadataStore) renameTag(oldTag, newTag string) (int, error) {
+	return s.rewriteTags(oldTag, newTag)
+}
+
+func (s *sqliteMetadataStore) deleteTag(tag string) (int, error) {
+	return s.rewriteTags(tag, "")
+}
+
+// rewriteTags replaces oldTag with newTag (or drops it when newTag is empty) on every row.
+func (s *sqliteMetadataStore) rewriteTags(oldTag, newTag string) (int, error) {
+	changed := 0
+	err := s.withTx(func(tx *sql.Tx) error {
+		rows, err := tx.Query("SELECT alias, tags FROM metadata WHERE tags != ''")
+		if err != nil {
+			return err
+		}
+		updates := make(map[string][]string)
+		for rows.Next() {
+			var alias, tags string
+			if err := rows.Scan(&alias, &tags); err != nil {
+				_ = rows.Close()
+				return err
+			}
+			if newTags, ok := replaceTag(decodeTags(tags), oldTag, newTag); ok {
+				updates[alias] = newTags
+			}
+		}
+		if err := rows.Close(); err != nil {
+			return err
+		}
+		for alias, tags := range updates {
+			if _, err := tx.Exec("UPDATE metadata SET tags = ? WHERE alias = ?", encodeTags(tags), alias); err != nil {
+				return err
+			}
+		}
+		changed = len(updates)
+		return nil
+	})
+	return changed, err
+}
+
+// drainWarnings is a no-op: SQLite reports corruption as ordinary query errors.
+func (s *sqliteMetadataStore) drainWarnings() []string {
+	return nil
+}
+
+// location returns the path of the SQLite database.
+func (s *sqliteMetadataStore) location() string {
+	return s.path
+}
+
+// modify applies fn to the metadata of a single alias inside a transaction.
+func (s *sqliteMetadataStore) modify(alias string, fn func(meta *ServerMetadata)) error {
+	return s.withTx(func(tx *sql.Tx) error {
+		meta, _, err := getMetadata(tx, alias)
+		if err != nil {
+			return err
+		}
+		fn(&meta)
+		return putMetadata(tx, alias, meta)
+	})
+}
+
+func (s *sqliteMetadataStore) withTx(fn func(tx *sql.Tx) error) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		s.logger.Errorw("failed to begin sqlite transaction", "path", s.path, "error", err)
+		return fmt.Errorf("begin sqlite transaction '%s': %w", s.path, err)
+	}
+	if err := fn(tx); err != nil {
+		_ = tx.Rollback()
+		s.logger.Errorw("sqlite metadata update failed", "path", s.path, "error", err)
+		return fmt.Errorf("update sqlite metadata '%s': %w", s.path, err)
+	}
+	if err := tx.Commit(); err != nil {
+		s.logger.Errorw("failed to commit sqlite transaction", "path", s.path, "error", err)
+		return fmt.Errorf("commit sqlite metadata '%s': %w", s.path, err)
+	}
+	return nil
+}
+
+// scanMetadata reads one row selected with sqliteMetadataColumns, preceded by the given
+// leading destinations (such as the alias).
+func scanMetadata(row interface{ Scan(...any) error }, leading ...any) (ServerMetadata, error) {
+	var tags, autoTunnels string
+	var meta ServerMetadata
+	var sessionLogging sql.NullBool
+	dest := make([]any, 0, len(leading)+12)
+	dest = append(dest, leading...)
+	dest = append(dest, &tags, &meta.LastSeen, &meta.PinnedAt, &meta.SSHCount, &meta.LastError, &meta.LastErrorAt,
+		&sessionLogging, &meta.PinOrder, &autoTunnels, &meta.AutoTunnelsDisabled, &meta.RequiresVPN, &meta.PingTarget)
+	if err := row.Scan(dest...); err != nil {
+		return ServerMetadata{}, err
+	}
+	meta.Tags = decodeTags(tags)
+	meta.AutoTunnels = decodeTags(autoTunnels)
+	if sessionLogging.Valid {
+		v := sessionLogging.Bool
+		meta.SessionLogging = &v
+	}
+	return meta, nil
+}
+
+func getMetadata(tx *sql.Tx, alias string) (ServerMetadata, bool, error) {
+	meta, err := scanMetadata(tx.QueryRow("SELECT "+sqliteMetadataColumns+" FROM metadata WHERE alias = ?", alias))
+	if errors.Is(err, sql.ErrNoRows) {
+		return ServerMetadata{}, false, nil
+	}
+	if err != nil {
+		return ServerMetadata{}, false, err
+	}
+	return meta, true, nil
+}
+
+func putMetadata(tx *sql.Tx, alias string, meta ServerMetadata) error {
+	var sessionLogging sql.NullBool
+	if meta.SessionLogging != nil {
+		sessionLogging = sql.NullBool{Bool: *meta.SessionLogging, Valid: true}
+	}
+	_, err := tx.Exec(`
+		INSERT INTO metadata (alias, `+sqliteMetadataColumns+`)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(alias) DO UPDATE SET
+			tags = excluded.tags,
+			last_seen = excluded.last_seen,
+			pinned_at = excluded.pinned_at,
+			ssh_count = excluded.ssh_count,
+			last_error = excluded.last_error,
+			last_error_at = excluded.last_error_at,
+			session_logging = excluded.session_logging,
+			pin_order = excluded.pin_order,
+			auto_tunnels = excluded.auto_tunnels,
+			auto_tunnels_disabled = excluded.auto_tunnels_disabled,
+			requires_vpn = excluded.requires_vpn,
+			ping_target = excluded.ping_target`,
+		alias, encodeTags(meta.Tags), meta.LastSeen, meta.PinnedAt, meta.SSHCount, meta.LastError, meta.LastErrorAt,
+		sessionLogging, meta.PinOrder, encodeTags(meta.AutoTunnels), meta.AutoTunnelsDisabled, meta.RequiresVPN, meta.PingTarget)
+	return err
+}
+
+// encodeTags stores tags as a JSON array; an empty string means no tags.
+func encodeTags(tags []string) string {
+	if len(tags) == 0 {
+		return ""
+	}
+	data, err := json.Marshal(tags)
+	if err != nil {
+		return ""
+	}
+	return string(data)
+}
+
+func decodeTags(s string) []string {
+	if s == "" {
+		return nil
+	}
+	var tags []string
+	if err := json.Unmarshal([]byte(s), &tags); err != nil {
+		return nil
+	}
+	return tags
+}
@@ -0,0 +1,301 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ssh_config_file
+
+import (
+	"database/sql"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/Adembc/lazyssh/internal/core/domain"
+	"go.uber.org/zap"
+)
+
+func TestSQLiteMetadataStore(t *testing.T) {
+	dir := t.TempDir()
+	logger := zap.NewNop().Sugar()
+
+	legacy := newMetadataManager(filepath.Join(dir, "metadata.json"), logger)
+	if err := legacy.updateServer(domain.Server{Alias: "old", Tags: []string{"legacy"}}, "old"); err != nil {
+		t.Fatal(err)
+	}
+
+	store, err := newSQLiteMetadataStore(filepath.Join(dir, "metadata.db"), legacy, logger)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := store.updateServer(domain.Server{Alias: "web", Tags: []string{"prod", "eu"}}, "web"); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.recordSSHError("web", "Permission denied (publickey)"); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.recordSSH("web"); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.setPinned("web", true); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.updateServer(domain.Server{Alias: "web2", Tags: []string{"prod"}}, "web"); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.deleteServer("old"); err != nil {
+		t.Fatal(err)
+	}
+
+	metadata, err := store.loadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(metadata) != 1 {
+		t.Fatalf("expected 1 entry, got %d: %v", len(metadata), metadata)
+	}
+	meta, ok := metadata["web2"]
+	if !ok {
+		t.Fatalf("expected renamed entry web2, got %v", metadata)
+	}
+	if !reflect.DeepEqual(meta.Tags, []string{"prod"}) {
+		t.Errorf("tags = %v, want [prod]", meta.Tags)
+	}
+	if meta.SSHCount != 1 || meta.LastSeen == "" {
+		t.Errorf("expected ssh stats to survive rename, got count=%d lastSeen=%q", meta.SSHCount, meta.LastSeen)
+	}
+	if meta.PinnedAt == "" {
+		t.Error("expected pin to survive rename")
+	}
+	if meta.LastError != "" || meta.LastErrorAt != "" {
+		t.Errorf("expected last error cleared by recordSSH, got %q at %q", meta.LastError, meta.LastErrorAt)
+	}
+}
+
+func TestSQLiteMetadataStoreImportsJSON(t *testing.T) {
+	dir := t.TempDir()
+	logger := zap.NewNop().Sugar()
+
+	legacy := newMetadataManager(filepath.Join(dir, "metadata.json"), logger)
+	if err := legacy.updateServer(domain.Server{Alias: "db", Tags: []string{"staging"}, SSHCount: 3}, "db"); err != nil {
+		t.Fatal(err)
+	}
+
+	store, err := newSQLiteMetadataStore(filepath.Join(dir, "metadata.db"), legacy, logger)
+	if err != nil {
+		t.Fatal(err)
+	}
+	metadata, err := store.loadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := metadata["db"]; got.SSHCount != 3 || !reflect.DeepEqual(got.Tags, []string{"staging"}) {
+		t.Errorf("imported metadata = %+v", got)
+	}
+}
+
+func TestSQLiteMetadataStoreMigratesOldSchema(t *testing.T) {
+	dir := t.TempDir()
+	logger := zap.NewNop().Sugar()
+	path := filepath.Join(dir, "metadata.db")
+
+	// A database created before session_logging existed.
+	db, err := sql.Open("sqlite", sqliteDSN(path, ""))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.Exec(sqliteMetadataSchema); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.Exec("INSERT INTO metadata (alias, tags) VALUES ('web', '[\"prod\"]')"); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	store, err := newSQLiteMetadataStore(path, nil, logger)
+	if err != nil {
+		t.Fatalf("newSQLiteMetadataStore() error = %v", err)
+	}
+
+	metadata, err := store.loadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if meta := metadata["web"]; meta.SessionLogging != nil || !reflect.DeepEqual(meta.Tags, []string{"prod"}) {
+		t.Errorf("migrated row = %+v, want tags [prod] and no session logging override", meta)
+	}
+
+	enabled := true
+	if err := store.setSessionLogging("web", &enabled); err != nil {
+		t.Fatal(err)
+	}
+	metadata, err = store.loadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := metadata["web"].SessionLogging; got == nil || !*got {
+		t.Errorf("SessionLogging = %v, want true", got)
+	}
+}
+
+func TestSQLiteMetadataStorePinOrder(t *testing.T) {
+	dir := t.TempDir()
+	logger := zap.NewNop().Sugar()
+
+	store, err := newSQLiteMetadataStore(filepath.Join(dir, "metadata.db"), nil, logger)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, alias := range []string{"a", "b"} {
+		if err := store.setPinned(alias, true); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := store.setPinOrder(map[string]int{"a": 2, "b": 1}); err != nil {
+		t.Fatal(err)
+	}
+
+	metadata, err := store.loadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if metadata["a"].PinOrder != 2 || metadata["b"].PinOrder != 1 {
+		t.Errorf("pin orders = a:%d b:%d, want a:2 b:1", metadata["a"].PinOrder, metadata["b"].PinOrder)
+	}
+
+	if err := store.setPinned("a", false); err != nil {
+		t.Fatal(err)
+	}
+	metadata, err = store.loadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if metadata["a"].PinOrder != 0 {
+		t.Errorf("unpinning should clear the pin order, got %d", metadata["a"].PinOrder)
+	}
+}
+
+func TestSQLiteMetadataStoreAutoTunnels(t *testing.T) {
+	dir := t.TempDir()
+	logger := zap.NewNop().Sugar()
+
+	store, err := newSQLiteMetadataStore(filepath.Join(dir, "metadata.db"), nil, logger)
+	if err != nil {
+		t.Fatal(err)
+	}
+	specs := []string{"5432:localhost:5432", "127.0.0.1:8080:web:80"}
+	if err := store.setAutoTunnels("db", specs, true); err != nil {
+		t.Fatal(err)
+	}
+
+	metadata, err := store.loadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := metadata["db"]; !reflect.DeepEqual(got.AutoTunnels, specs) || !got.AutoTunnelsDisabled {
+		t.Errorf("auto tunnels = %v disabled %v, want %v disabled true", got.AutoTunnels, got.AutoTunnelsDisabled, specs)
+	}
+
+	if err := store.setAutoTunnels("db", nil, false); err != nil {
+		t.Fatal(err)
+	}
+	metadata, err = store.loadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := metadata["db"]; got.AutoTunnels != nil || got.AutoTunnelsDisabled {
+		t.Errorf("cleared auto tunnels = %v disabled %v", got.AutoTunnels, got.AutoTunnelsDisabled)
+	}
+}
+
+func TestSQLiteMetadataStoreRequiresVPN(t *testing.T) {
+	store, err := newSQLiteMetadataStore(filepath.Join(t.TempDir(), "metadata.db"), nil, zap.NewNop().Sugar())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := store.updateServer(domain.Server{Alias: "corp", RequiresVPN: true}, "corp"); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.recordSSH("corp"); err != nil {
+		t.Fatal(err)
+	}
+	metadata, err := store.loadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !metadata["corp"].RequiresVPN {
+		t.Errorf("RequiresVPN lost after recordSSH: %+v", metadata["corp"])
+	}
+
+	if err := store.updateServer(domain.Server{Alias: "corp"}, "corp"); err != nil {
+		t.Fatal(err)
+	}
+	if metadata, err = store.loadAll(); err != nil {
+		t.Fatal(err)
+	}
+	if metadata["corp"].RequiresVPN {
+		t.Errorf("RequiresVPN still set after clearing it in an update")
+	}
+}
+
+func TestSQLiteMetadataStorePingTarget(t *testing.T) {
+	store, err := newSQLiteMetadataStore(filepath.Join(t.TempDir(), "metadata.db"), nil, zap.NewNop().Sugar())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := store.updateServer(domain.Server{Alias: "web", PingTarget: "10.0.0.5:443"}, "web"); err != nil {
+		t.Fatal(err)
+	}
+	metadata, err := store.loadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := metadata["web"].PingTarget; got != "10.0.0.5:443" {
+		t.Errorf("PingTarget = %q, want %q", got, "10.0.0.5:443")
+	}
+}
+
+func TestSQLiteMetadataStoreEscapesPath(t *testing.T) {
+	// Unescaped, ? would start the query and # the fragment, opening a different file.
+	dir := filepath.Join(t.TempDir(), "odd?mode=memory#50%")
+	path := filepath.Join(dir, "metadata.db")
+	store, err := newSQLiteMetadataStore(path, nil, zap.NewNop().Sugar())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := store.updateServer(domain.Server{Alias: "web", Tags: []string{"prod"}}, "web"); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.db.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(path); err != nil {
+		t.Fatalf("database not created at %s: %v", path, err)
+	}
+
+	reopened, err := newSQLiteMetadataStore(path, nil, zap.NewNop().Sugar())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = reopened.db.Close() }()
+	metadata, err := reopened.loadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := metadata["web"].Tags; !reflect.DeepEqual(got, []string{"prod"}) {
+		t.Errorf("tags after reopening = %v, want [prod]", got)
+	}
+}
@@ -16,7 +16,11 @@ package ssh_config_file
 
 import (
 	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
 
+	"github.com/Adembc/lazyssh/internal/config"
 	"github.com/Adembc/lazyssh/internal/core/domain"
 	"github.com/Adembc/lazyssh/internal/core/ports"
 	"github.com/kevinburke/ssh_config"
@@ -25,30 +29,59 @@ import (
 
 // Repository implements ServerRepository interface for SSH config file operations.
 type Repository struct {
-	configPath      string
-	fileSystem      FileSystem
-	metadataManager *metadataManager
-	logger          *zap.SugaredLogger
+	configPath    string
+	fileSystem    FileSystem
+	metadataStore metadataStore
+	logger        *zap.SugaredLogger
+
+	// configLockPath guards SSH config read-modify-write cycles across lazyssh instances.
+	configLockPath string
+	// changeLogPath is the append-only log of adds, edits, renames and deletes.
+	changeLogPath string
+
+	// homeDir is used to rewrite IdentityFile paths as ~/... when relativeIdentityPaths is set.
+	homeDir               string
+	relativeIdentityPaths bool
+
+	// sortOnWrite sorts Host entries by alias before every save.
+	sortOnWrite bool
+	// metadataInConfig keeps tags and pins as comments in the Host entries.
+	metadataInConfig bool
+	// annotateHosts adds the "Added by lazyssh" comment to Host lines lazyssh creates.
+	annotateHosts bool
+
+	// warnings holds config problems found at startup until the UI drains them.
+	warningsMu sync.Mutex
+	warnings   []string
+	// duplicatesChecked runs checkDuplicateHosts once, when warnings are first drained.
+	duplicatesChecked sync.Once
 }
 
 // NewRepository creates a new SSH config repository.
-func NewRepository(logger *zap.SugaredLogger, configPath, metaDataPath string) ports.ServerRepository {
-	return &Repository{
-		logger:          logger,
-		configPath:      configPath,
-		fileSystem:      DefaultFileSystem{},
-		metadataManager: newMetadataManager(metaDataPath, logger),
-	}
+func NewRepository(logger *zap.SugaredLogger, configPath, metaDataPath string, cfg config.Config) ports.ServerRepository {
+	return NewRepositoryWithFS(logger, configPath, metaDataPath, DefaultFileSystem{}, cfg)
 }
 
 // NewRepositoryWithFS creates a new SSH config repository with a custom filesystem.
-func NewRepositoryWithFS(logger *zap.SugaredLogger, configPath string, metaDataPath string, fs FileSystem) ports.ServerRepository {
-	return &Repository{
-		logger:          logger,
-		configPath:      configPath,
-		fileSystem:      fs,
-		metadataManager: newMetadataManager(metaDataPath, logger),
+func NewRepositoryWithFS(logger *zap.SugaredLogger, configPath string, metaDataPath string, fs FileSystem, cfg config.Config) ports.ServerRepository {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		logger.Warnw("failed to resolve home directory; IdentityFile paths will be written as-is", "error", err)
+	}
+	repo := &Repository{
+		logger:                logger,
+		configPath:            configPath,
+		fileSystem:            fs,
+		metadataStore:         newMetadataStore(cfg.MetadataBackend, metaDataPath, logger),
+		configLockPath:        filepath.Join(filepath.Dir(metaDataPath), "ssh_config.lock"),
+		changeLogPath:         filepath.Join(filepath.Dir(metaDataPath), changeLogFile),
+		homeDir:               home,
+		relativeIdentityPaths: cfg.RelativeIdentityPaths,
+		sortOnWrite:           cfg.SortConfigOnWrite,
+		metadataInConfig:      cfg.MetadataInConfig,
+		annotateHosts:         cfg.AnnotateManagedHosts,
 	}
+	return repo
 }
 
 // ListServers returns all servers matching the query pattern.
@@ -60,7 +93,7 @@ func (r *Repository) ListServers(query string) ([]domain.Server, error) {
 	}
 
 	servers := r.toDomainServer(cfg)
-	metadata, err := r.metadataManager.loadAll()
+	metadata, err := r.metadataStore.loadAll()
 	if err != nil {
 		r.logger.Warnf("Failed to load metadata: %v", err)
 		metadata = make(map[string]ServerMetadata)
@@ -70,32 +103,51 @@ func (r *Repository) ListServers(query string) ([]domain.Server, error) {
 		return servers, nil
 	}
 
-	return r.filterServers(servers, query), nil
+	return domain.FilterServers(servers, query), nil
 }
 
 // AddServer adds a new server to the SSH config.
 func (r *Repository) AddServer(server domain.Server) error {
+	lock, err := r.lockConfig()
+	if err != nil {
+		return err
+	}
+	defer r.unlockConfig(lock)
+
 	cfg, err := r.loadConfig()
 	if err != nil {
 		return fmt.Errorf("failed to load config: %w", err)
 	}
 
-	if r.serverExists(cfg, server.Alias) {
+	if r.aliasDefined(cfg, server.Alias) {
 		return fmt.Errorf("server with alias '%s' already exists", server.Alias)
 	}
 
 	host := r.createHostFromServer(server)
+	if r.metadataInConfig {
+		writeConfigMetadata(host, server)
+	}
 	cfg.Hosts = append(cfg.Hosts, host)
 
 	if err := r.saveConfig(cfg); err != nil {
 		r.logger.Warnf("Failed to save config while adding new server: %v", err)
 		return fmt.Errorf("failed to save config: %w", err)
 	}
-	return r.metadataManager.updateServer(server, server.Alias)
+	r.logChange(domain.ChangeAdd, server.Alias, describeServer(server))
+	if r.metadataInConfig {
+		server = stripConfigMetadata(server)
+	}
+	return r.metadataStore.updateServer(server, server.Alias)
 }
 
 // UpdateServer updates an existing server in the SSH config.
 func (r *Repository) UpdateServer(server domain.Server, newServer domain.Server) error {
+	lock, err := r.lockConfig()
+	if err != nil {
+		return err
+	}
+	defer r.unlockConfig(lock)
+
 	cfg, err := r.loadConfig()
 	if err != nil {
 		return fmt.Errorf("failed to load config: %w", err)
@@ -107,17 +159,17 @@ func (r *Repository) UpdateServer(server domain.Server, newServer domain.Server)
 	}
 
 	if server.Alias != newServer.Alias {
-		if r.serverExists(cfg, newServer.Alias) {
+		if r.aliasDefined(cfg, newServer.Alias) {
 			return fmt.Errorf("server with alias '%s' already exists", newServer.Alias)
 		}
 
-		newPatterns := make([]*ssh_config.Pattern, 0, len(host.Patterns))
-		for _, pattern := range host.Patterns {
-			if pattern.Str == server.Alias {
-				newPatterns = append(newPatterns, &ssh_config.Pattern{Str: newServer.Alias})
-			} else {
-				newPatterns = append(newPatterns, pattern)
+		patterns := hostPatterns(host)
+		newPatterns := make([]*ssh_config.Pattern, 0, len(patterns))
+		for _, pattern := range patterns {
+			if pattern == server.Alias {
+				pattern = newServer.Alias
 			}
+			newPatterns = append(newPatterns, &ssh_config.Pattern{Str: quoteArg(pattern)})
 		}
 
 		host.Patterns = newPatterns
@@ -125,17 +177,35 @@ func (r *Repository) UpdateServer(server domain.Server, newServer domain.Server)
 	}
 
 	r.updateHostNodes(host, newServer)
+	if r.metadataInConfig {
+		writeConfigMetadata(host, newServer)
+	}
 
 	if err := r.saveConfig(cfg); err != nil {
 		r.logger.Warnf("Failed to save config while updating server: %v", err)
 		return fmt.Errorf("failed to save config: %w", err)
 	}
+	if server.Alias != newServer.Alias {
+		r.logChange(domain.ChangeRename, newServer.Alias, server.Alias+" → "+newServer.Alias)
+	}
+	if detail := describeServerChanges(server, newServer); detail != "" {
+		r.logChange(domain.ChangeUpdate, newServer.Alias, detail)
+	}
 	// Update metadata; pass old alias to allow inline migration
-	return r.metadataManager.updateServer(newServer, server.Alias)
+	if r.metadataInConfig {
+		newServer = stripConfigMetadata(newServer)
+	}
+	return r.metadataStore.updateServer(newServer, server.Alias)
 }
 
 // DeleteServer removes a server from the SSH config.
 func (r *Repository) DeleteServer(server domain.Server) error {
+	lock, err := r.lockConfig()
+	if err != nil {
+		return err
+	}
+	defer r.unlockConfig(lock)
+
 	cfg, err := r.loadConfig()
 	if err != nil {
 		return fmt.Errorf("failed to load config: %w", err)
@@ -152,15 +222,159 @@ func (r *Repository) DeleteServer(server domain.Server) error {
 		r.logger.Warnf("Failed to save config while deleting server: %v", err)
 		return fmt.Errorf("failed to save config: %w", err)
 	}
-	return r.metadataManager.deleteServer(server.Alias)
+	r.logChange(domain.ChangeDelete, server.Alias, describeServer(server))
+	return r.metadataStore.deleteServer(server.Alias)
+}
+
+// MoveServer swaps the Host entry for alias with the entry offset positions away (-1 up,
+// +1 down) in the main config, changing which entry OpenSSH consults first. It returns
+// the alias of the entry it moved past.
+func (r *Repository) MoveServer(alias string, offset int) (string, error) {
+	lock, err := r.lockConfig()
+	if err != nil {
+		return "", err
+	}
+	defer r.unlockConfig(lock)
+
+	if r.sortOnWrite {
+		return "", fmt.Errorf("the SSH config is kept sorted by alias (sort_config_on_write)")
+	}
+
+	cfg, err := r.loadConfig()
+	if err != nil {
+		return "", fmt.Errorf("failed to load config: %w", err)
+	}
+
+	from := -1
+	for i, host := range cfg.Hosts {
+		if r.hostContainsPattern(host, alias) {
+			from = i
+			break
+		}
+	}
+	if from < 0 {
+		return "", fmt.Errorf("server with alias '%s' not found", alias)
+	}
+	to := from + offset
+	if to < 0 || to >= len(cfg.Hosts) || cfg.Hosts[to].Implicit {
+		return "", fmt.Errorf("'%s' cannot move further", alias)
+	}
+
+	cfg.Hosts[from], cfg.Hosts[to] = cfg.Hosts[to], cfg.Hosts[from]
+	ensureBlankSeparators(cfg.Hosts)
+
+	if err := r.saveConfig(cfg); err != nil {
+		r.logger.Warnf("Failed to save config while moving server: %v", err)
+		return "", fmt.Errorf("failed to save config: %w", err)
+	}
+
+	neighbour := ""
+	if patterns := hostPatterns(cfg.Hosts[from]); len(patterns) > 0 {
+		neighbour = patterns[0]
+	}
+	return neighbour, nil
 }
 
 // SetPinned sets or unsets the pinned status of a server.
+// With metadata_in_config the pin is written to the Host entry; unpinning also clears a pin
+// left in the metadata store from before the option was turned on.
 func (r *Repository) SetPinned(alias string, pinned bool) error {
-	return r.metadataManager.setPinned(alias, pinned)
+	if !r.metadataInConfig {
+		return r.metadataStore.setPinned(alias, pinned)
+	}
+	if err := r.setPinnedInConfig(alias, pinned); err != nil {
+		return err
+	}
+	if pinned {
+		return nil
+	}
+	return r.metadataStore.setPinned(alias, false)
+}
+
+// SetPinOrder stores manual positions for pinned servers; an order of 0 clears it.
+func (r *Repository) SetPinOrder(orders map[string]int) error {
+	return r.metadataStore.setPinOrder(orders)
+}
+
+// SetSessionLogging sets the per-server session logging override; nil follows the config.
+func (r *Repository) SetSessionLogging(alias string, enabled *bool) error {
+	return r.metadataStore.setSessionLogging(alias, enabled)
+}
+
+// SetAutoTunnels stores the lazyssh-managed local forwards opened with sessions to alias.
+func (r *Repository) SetAutoTunnels(alias string, specs []string, disabled bool) error {
+	return r.metadataStore.setAutoTunnels(alias, specs, disabled)
 }
 
 // RecordSSH increments the SSH access count and updates the last seen timestamp for a server.
 func (r *Repository) RecordSSH(alias string) error {
-	return r.metadataManager.recordSSH(alias)
+	return r.metadataStore.recordSSH(alias)
+}
+
+// RecordSSHError stores the last connection error for a server; it is cleared by the next RecordSSH.
+func (r *Repository) RecordSSHError(alias string, message string) error {
+	return r.metadataStore.recordSSHError(alias, message)
+}
+
+// RenameTag replaces a tag on every server and returns the number of servers changed.
+func (r *Repository) RenameTag(oldTag, newTag string) (int, error) {
+	if !r.metadataInConfig {
+		return r.metadataStore.renameTag(oldTag, newTag)
+	}
+	return r.rewriteTagsEverywhere(oldTag, newTag)
+}
+
+// DeleteTag removes a tag from every server and returns the number of servers changed.
+func (r *Repository) DeleteTag(tag string) (int, error) {
+	if !r.metadataInConfig {
+		return r.metadataStore.deleteTag(tag)
+	}
+	return r.rewriteTagsEverywhere(tag, "")
+}
+
+// rewriteTagsEverywhere rewrites a tag in the SSH config comments and in the metadata store,
+// where servers not yet saved since metadata_in_config was turned on still keep their tags.
+func (r *Repository) rewriteTagsEverywhere(oldTag, newTag string) (int, error) {
+	n, err := r.rewriteTagsInConfig(oldTag, newTag)
+	if err != nil {
+		return n, err
+	}
+	var m int
+	if newTag == "" {
+		m, err = r.metadataStore.deleteTag(oldTag)
+	} else {
+		m, err = r.metadataStore.renameTag(oldTag, newTag)
+	}
+	return n + m, err
+}
+
+// ServerFiles returns the SSH config file declaring the given alias, which may be an
+// included file, and the metadata path, with the line of its Host entry when it can be found.
+func (r *Repository) ServerFiles(alias string) (domain.ServerFiles, error) {
+	files := domain.ServerFiles{
+		ConfigPath:   r.configPath,
+		MetadataPath: r.metadataStore.location(),
+	}
+	path, line, err := r.findHostEntry(alias)
+	if err != nil {
+		return files, fmt.Errorf("failed to locate host entry: %w", err)
+	}
+	files.ConfigPath = path
+	files.ConfigLine = line
+	return files, nil
+}
+
+// ConfigWritable returns the SSH config path and whether lazyssh can save changes to it.
+func (r *Repository) ConfigWritable() (string, bool) {
+	return r.configPath, r.isWritable()
+}
+
+// DrainWarnings returns and clears non-fatal problems (such as recovered metadata) to surface in the UI.
+func (r *Repository) DrainWarnings() []string {
+	r.duplicatesChecked.Do(r.checkDuplicateHosts)
+	r.warningsMu.Lock()
+	warnings := r.warnings
+	r.warnings = nil
+	r.warningsMu.Unlock()
+	return append(warnings, r.metadataStore.drainWarnings()...)
 }
@@ -0,0 +1,70 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ui
+
+import (
+	"strings"
+
+	"github.com/rivo/tview"
+)
+
+// asciiTerms are TERM values of consoles without emoji or reliable box-drawing glyphs.
+var asciiTerms = map[string]bool{
+	"dumb":  true,
+	"vt100": true,
+	"vt102": true,
+	"vt220": true,
+	"linux": true,
+}
+
+// useASCIIBorders switches tview's box-drawing borders, which tview keeps globally, to
+// ASCII. It must be called before the components are built.
+func useASCIIBorders() {
+	tview.Borders.Horizontal, tview.Borders.Vertical = '-', '|'
+	tview.Borders.TopLeft, tview.Borders.TopRight = '+', '+'
+	tview.Borders.BottomLeft, tview.Borders.BottomRight = '+', '+'
+	tview.Borders.LeftT, tview.Borders.RightT = '+', '+'
+	tview.Borders.TopT, tview.Borders.BottomT, tview.Borders.Cross = '+', '+', '+'
+	tview.Borders.HorizontalFocus, tview.Borders.VerticalFocus = '=', '|'
+	tview.Borders.TopLeftFocus, tview.Borders.TopRightFocus = '+', '+'
+	tview.Borders.BottomLeftFocus, tview.Borders.BottomRightFocus = '+', '+'
+}
+
+// UseASCII decides whether to render in ASCII: the ascii_mode setting wins when set,
+// otherwise a non-UTF-8 locale or a console TERM turns it on.
+func UseASCII(setting *bool, getenv func(string) string) bool {
+	if setting != nil {
+		return *setting
+	}
+	if asciiTerms[getenv("TERM")] {
+		return true
+	}
+	// LC_ALL overrides LC_CTYPE, which overrides LANG; an unset locale says nothing about the terminal.
+	for _, name := range []string{"LC_ALL", "LC_CTYPE", "LANG"} {
+		if locale := getenv(name); locale != "" {
+			locale = strings.ToLower(locale)
+			return !strings.Contains(locale, "utf-8") && !strings.Contains(locale, "utf8")
+		}
+	}
+	return false
+}
+
+// glyph returns fancy, or plain in ASCII mode.
+func (d displaySettings) glyph(fancy, plain string) string {
+	if d.ascii {
+		return plain
+	}
+	return fancy
+}
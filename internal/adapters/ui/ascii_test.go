@@ -0,0 +1,108 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ui
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Adembc/lazyssh/internal/core/domain"
+	"github.com/mattn/go-runewidth"
+)
+
+func TestUseASCII(t *testing.T) {
+	on, off := true, false
+	tests := []struct {
+		name    string
+		setting *bool
+		env     map[string]string
+		want    bool
+	}{
+		{"utf-8 locale", nil, map[string]string{"LANG": "en_US.UTF-8", "TERM": "xterm-256color"}, false},
+		{"utf8 spelling", nil, map[string]string{"LC_ALL": "C.utf8"}, false},
+		{"C locale", nil, map[string]string{"LANG": "C"}, true},
+		{"LC_ALL overrides LANG", nil, map[string]string{"LC_ALL": "POSIX", "LANG": "en_US.UTF-8"}, true},
+		{"linux console", nil, map[string]string{"LANG": "en_US.UTF-8", "TERM": "linux"}, true},
+		{"nothing set", nil, map[string]string{}, false},
+		{"forced on", &on, map[string]string{"LANG": "en_US.UTF-8"}, true},
+		{"forced off", &off, map[string]string{"TERM": "vt100"}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			getenv := func(k string) string { return tt.env[k] }
+			if got := UseASCII(tt.setting, getenv); got != tt.want {
+				t.Errorf("UseASCII() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatServerLineASCII(t *testing.T) {
+	d := displaySettings{ascii: true}
+
+	pinned := domain.Server{Alias: "web", PinnedAt: time.Now(), AutoTunnels: []string{"5432:localhost:5432"}}
+	plain := domain.Server{Alias: "db"}
+	a, _ := d.formatServerLine(pinned, domain.Endpoint{}, false, true)
+	b, _ := d.formatServerLine(plain, domain.Endpoint{}, false, false)
+	for _, line := range []string{a, b} {
+		text := stripTags(line)
+		for _, r := range text {
+			if r > 127 {
+				t.Fatalf("non-ASCII %q in %q", r, text)
+			}
+		}
+	}
+	if !strings.HasPrefix(stripTags(a), "+* ") || !strings.HasPrefix(stripTags(b), " . ") {
+		t.Errorf("unexpected icons: %q / %q", stripTags(a), stripTags(b))
+	}
+	// Both icons occupy the same width, so the alias column stays aligned.
+	if wa, wb := runewidth.StringWidth(cellPad(d.pinnedIcon(time.Now()), 2)), runewidth.StringWidth(cellPad(d.pinnedIcon(time.Time{}), 2)); wa != wb {
+		t.Errorf("icon widths differ: %d vs %d", wa, wb)
+	}
+}
+
+var colorTag = regexp.MustCompile(`\[[^\[\]]*\]`)
+
+// stripTags removes tview color/style tags such as [#FFD75F::b] and [-].
+func stripTags(s string) string {
+	return colorTag.ReplaceAllString(s, "")
+}
+
+// TestWarningGlyphsHaveASCIIFallback guards against ⚠ markers printed without glyph(), which
+// ASCII mode would still show.
+func TestWarningGlyphsHaveASCIIFallback(t *testing.T) {
+	files, err := filepath.Glob("*.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, file := range files {
+		if strings.HasSuffix(file, "_test.go") {
+			continue
+		}
+		data, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i, line := range strings.Split(string(data), "\n") {
+			if strings.Contains(line, "⚠") && !strings.Contains(line, "glyph(") {
+				t.Errorf("%s:%d prints ⚠ without glyph()", file, i+1)
+			}
+		}
+	}
+}
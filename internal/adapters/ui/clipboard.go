@@ -0,0 +1,97 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ui
+
+import (
+	"github.com/atotto/clipboard"
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// clipboardInstallHint names the tools the clipboard package needs on Linux and BSD.
+const clipboardInstallHint = "Install xclip, xsel or wl-clipboard, or set clipboard_osc52 over SSH."
+
+// copyToClipboard copies text and reports done in the status bar. With clipboard_osc52 set
+// the terminal is asked to do it. Otherwise, when no clipboard tool is available (common over
+// SSH or on headless machines), the text is shown in a modal to select by hand; other
+// failures are reported as errors.
+func (t *tui) copyToClipboard(text, done string) {
+	if t.cfg.ClipboardOSC52 {
+		if err := writeOSC52(text); err != nil {
+			t.logger.Warnw("failed to copy via OSC 52", "error", err)
+			t.showStatusTempColor("Failed to copy to clipboard: "+err.Error(), "#FF6B6B")
+			return
+		}
+		t.showStatusTemp(done)
+		return
+	}
+
+	err := clipboard.WriteAll(text)
+	switch {
+	case err == nil:
+		t.showStatusTemp(done)
+	case clipboard.Unsupported:
+		t.showCopyFallback(text)
+	default:
+		t.logger.Warnw("failed to copy to clipboard", "error", err)
+		t.showStatusTempColor("Failed to copy to clipboard: "+err.Error(), "#FF6B6B")
+	}
+}
+
+// showCopyFallback shows text for selecting with the terminal's own mouse selection. Mouse
+// capture is switched off while the modal is open so dragging selects instead of clicking.
+func (t *tui) showCopyFallback(text string) {
+	view := tview.NewTextView().
+		SetDynamicColors(true).
+		SetScrollable(true).
+		SetWrap(true).
+		SetText(tview.Escape(text))
+	view.SetBorder(true).
+		SetTitle(" No clipboard available — select the text to copy ").
+		SetTitleAlign(tview.AlignCenter).
+		SetBorderColor(tcell.Color238).
+		SetTitleColor(tcell.Color250)
+
+	hint := tview.NewTextView().
+		SetDynamicColors(true).
+		SetTextAlign(tview.AlignCenter).
+		SetText("[#888888]" + clipboardInstallHint + "  Esc to close[-]")
+
+	t.app.EnableMouse(false)
+	view.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Key() == tcell.KeyEscape || event.Key() == tcell.KeyEnter || event.Rune() == 'q' {
+			t.app.EnableMouse(true)
+			t.handleModalClose()
+			return nil
+		}
+		return event
+	})
+
+	panel := tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(view, 0, 1, true).
+		AddItem(hint, 1, 0, false)
+	box := tview.NewFlex().
+		AddItem(nil, 0, 1, false).
+		AddItem(tview.NewFlex().SetDirection(tview.FlexRow).
+			AddItem(nil, 0, 1, false).
+			AddItem(panel, 0, 2, true).
+			AddItem(nil, 0, 1, false), 0, 4, true).
+		AddItem(nil, 0, 1, false)
+
+	t.app.SetRoot(tview.NewPages().
+		AddPage("main", t.root, true, true).
+		AddPage("copy", box, true, true), true)
+	t.app.SetFocus(view)
+}
@@ -0,0 +1,118 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ui
+
+import (
+	"time"
+	"unicode"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// confirmIdleTimeout is how long a confirmation waits for input before it dismisses itself
+// as cancelled.
+const confirmIdleTimeout = 30 * time.Second
+
+// confirmation describes the buttons of a fail-safe confirmation.
+type confirmation struct {
+	cancelLabel, confirmLabel string
+	// cancelKey cancels; c always cancels too, as in every dialog.
+	cancelKey rune
+	// confirmKey only moves focus to the confirm button; 0 leaves it without a shortcut.
+	confirmKey rune
+	// timedOut is the status shown when the confirmation cancels itself.
+	timedOut string
+}
+
+// deleteConfirmation is the confirmation for deletes.
+var deleteConfirmation = confirmation{
+	cancelLabel:  "[yellow]C[-]ancel",
+	confirmLabel: "[yellow]D[-]elete",
+	confirmKey:   'd',
+	timedOut:     "Delete cancelled after no input",
+}
+
+// showDeleteConfirm asks before an irreversible delete. It is built to fail safe: focus
+// starts on Cancel, so a stray Enter cancels; the d shortcut only moves focus to Delete, so
+// a double-pressed d cannot confirm; and the modal cancels itself after confirmIdleTimeout
+// without a key press. onDelete runs after the modal is closed.
+func (t *tui) showDeleteConfirm(msg string, onDelete func()) {
+	t.showDeleteConfirmOver(msg, t.handleModalClose, onDelete)
+}
+
+// showDeleteConfirmOver is showDeleteConfirm for screens other than the server list: dismiss
+// leaves the modal, typically by redrawing the screen it was opened from.
+func (t *tui) showDeleteConfirmOver(msg string, dismiss, onDelete func()) {
+	t.showConfirm(msg, deleteConfirmation, dismiss, onDelete)
+}
+
+// showConfirm shows the fail-safe confirmation described by c for any irreversible action:
+// focus starts on the cancel button, the confirm shortcut only moves focus, and the modal
+// cancels itself after confirmIdleTimeout without a key press. onConfirm runs after dismiss.
+func (t *tui) showConfirm(msg string, c confirmation, dismiss, onConfirm func()) {
+	t.app.SetRoot(t.newConfirm(msg, c, dismiss, onConfirm), true)
+}
+
+// newConfirm builds the modal shown by showConfirm.
+func (t *tui) newConfirm(msg string, c confirmation, dismiss, onConfirm func()) *tview.Modal {
+	const cancelButton, confirmButton = 0, 1
+
+	closed := false // only touched on the UI goroutine
+	timer := time.AfterFunc(confirmIdleTimeout, func() {
+		t.app.QueueUpdateDraw(func() {
+			if closed {
+				return
+			}
+			closed = true
+			dismiss()
+			t.showStatusTemp(c.timedOut)
+		})
+	})
+	finish := func(confirmed bool) {
+		closed = true
+		timer.Stop()
+		dismiss()
+		if confirmed {
+			onConfirm()
+		}
+	}
+
+	modal := tview.NewModal().
+		SetText(msg).
+		AddButtons([]string{c.cancelLabel, c.confirmLabel}).
+		SetDoneFunc(func(buttonIndex int, buttonLabel string) {
+			finish(buttonIndex == confirmButton)
+		})
+
+	modal.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		timer.Reset(confirmIdleTimeout)
+		r := unicode.ToLower(event.Rune())
+		switch {
+		case r == 'c' || (c.cancelKey != 0 && r == c.cancelKey):
+			finish(false)
+			return nil
+		case c.confirmKey != 0 && r == c.confirmKey:
+			modal.SetFocus(confirmButton)
+			t.app.SetFocus(modal)
+			return nil
+		}
+		// ESC key already handled by default modal behavior
+		return event
+	})
+
+	modal.SetFocus(cancelButton)
+	return modal
+}
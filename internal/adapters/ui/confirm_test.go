@@ -0,0 +1,59 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ui
+
+import (
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+func TestConfirmFailsSafe(t *testing.T) {
+	tests := []struct {
+		name          string
+		c             confirmation
+		keys          []*tcell.EventKey
+		wantConfirmed bool
+		wantDismissed bool
+	}{
+		{"enter cancels delete", deleteConfirmation, []*tcell.EventKey{tcell.NewEventKey(tcell.KeyEnter, 0, tcell.ModNone)}, false, true},
+		{"double d does not delete", deleteConfirmation, []*tcell.EventKey{
+			tcell.NewEventKey(tcell.KeyRune, 'd', tcell.ModNone),
+			tcell.NewEventKey(tcell.KeyRune, 'd', tcell.ModNone),
+		}, false, false},
+		{"d then enter deletes", deleteConfirmation, []*tcell.EventKey{
+			tcell.NewEventKey(tcell.KeyRune, 'd', tcell.ModNone),
+			tcell.NewEventKey(tcell.KeyEnter, 0, tcell.ModNone),
+		}, true, true},
+		{"enter keeps master", closeMasterConfirmation, []*tcell.EventKey{tcell.NewEventKey(tcell.KeyEnter, 0, tcell.ModNone)}, false, true},
+		{"c keeps master", closeMasterConfirmation, []*tcell.EventKey{tcell.NewEventKey(tcell.KeyRune, 'c', tcell.ModNone)}, false, true},
+		{"k keeps master", closeMasterConfirmation, []*tcell.EventKey{tcell.NewEventKey(tcell.KeyRune, 'K', tcell.ModNone)}, false, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ui := &tui{app: tview.NewApplication()}
+			confirmed, dismissed := false, false
+			modal := ui.newConfirm("sure?", tt.c, func() { dismissed = true }, func() { confirmed = true })
+			ui.app.SetRoot(modal, true)
+			for _, key := range tt.keys {
+				modal.InputHandler()(key, func(p tview.Primitive) { ui.app.SetFocus(p) })
+			}
+			if confirmed != tt.wantConfirmed || dismissed != tt.wantDismissed {
+				t.Errorf("confirmed=%v dismissed=%v; want %v, %v", confirmed, dismissed, tt.wantConfirmed, tt.wantDismissed)
+			}
+		})
+	}
+}
@@ -0,0 +1,50 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ui
+
+import (
+	"strings"
+
+	"github.com/Adembc/lazyssh/internal/config"
+)
+
+// displaySettings are the config values the components render with. NewTUI builds them once
+// and hands them to each component.
+type displaySettings struct {
+	// ascii replaces emoji and box-drawing characters with plain ASCII for terminals and
+	// serial consoles that cannot render them.
+	ascii bool
+	// tagColors maps lower-cased tag names to user-assigned colors from the tag_colors config key.
+	tagColors map[string]string
+	// listMaxTags is how many tag chips a list row shows; 0 hides them and a negative value
+	// shows all.
+	listMaxTags int
+	// defaultIdentityFile is the key used for servers without an IdentityFile, empty to leave
+	// it to ssh.
+	defaultIdentityFile string
+	// enterAction is what Enter does on the selected server, one of the config.EnterAction values.
+	enterAction string
+}
+
+// newDisplaySettings reads the display settings from cfg; getenv is consulted for ASCII mode.
+func newDisplaySettings(cfg config.Config, getenv func(string) string) displaySettings {
+	return displaySettings{
+		ascii:               UseASCII(cfg.ASCIIMode, getenv),
+		tagColors:           parseTagColors(cfg.TagColors),
+		listMaxTags:         cfg.ListMaxTags,
+		defaultIdentityFile: strings.TrimSpace(cfg.DefaultIdentityFile),
+		enterAction:         cfg.Enter(),
+	}
+}
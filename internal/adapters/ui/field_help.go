@@ -412,6 +412,15 @@ var fieldHelpData = map[string]FieldHelp{
 		Category:    "Basic",
 	},
 
+	"PingTarget": {
+		Field:       "PingTarget",
+		Description: "Address pinged instead of the SSH HostName and Port, for hosts whose SSH port is firewalled but another port shows they are up. Stored in lazyssh metadata, not the SSH config. Pings to it ignore ProxyJump.",
+		Syntax:      "host:port  ",
+		Examples:    []string{"10.0.0.5:443", "health.example.com:8080", "[2001:db8::1]:80"},
+		Default:     "the SSH address",
+		Category:    "Basic",
+	},
+
 	// Connection - IP and Address fields
 	"IPQoS": {
 		Field:       "IPQoS",
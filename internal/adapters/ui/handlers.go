@@ -15,12 +15,15 @@
 package ui
 
 import (
+	"bufio"
+	"errors"
 	"fmt"
+	"os"
 	"strings"
 	"time"
 
+	"github.com/Adembc/lazyssh/internal/config"
 	"github.com/Adembc/lazyssh/internal/core/domain"
-	"github.com/atotto/clipboard"
 	"github.com/gdamore/tcell/v2"
 	"github.com/rivo/tview"
 )
@@ -34,6 +37,14 @@ func (t *tui) handleGlobalKeys(event *tcell.EventKey) *tcell.EventKey {
 	if t.app.GetFocus() == t.searchBar {
 		return event
 	}
+	if t.app.GetFocus() == t.details {
+		return t.handleDetailsKeys(event)
+	}
+
+	if event.Key() == tcell.KeyTab {
+		t.focusDetails(true)
+		return nil
+	}
 
 	switch event.Rune() {
 	case 'q':
@@ -45,6 +56,9 @@ func (t *tui) handleGlobalKeys(event *tcell.EventKey) *tcell.EventKey {
 	case 'a':
 		t.handleServerAdd()
 		return nil
+	case 'A':
+		t.handleServerAddFromCommand()
+		return nil
 	case 'e':
 		t.handleServerEdit()
 		return nil
@@ -60,18 +74,87 @@ func (t *tui) handleGlobalKeys(event *tcell.EventKey) *tcell.EventKey {
 	case 'S':
 		t.handleSortReverse()
 		return nil
+	case 'R':
+		t.handleToggleResolved()
+		return nil
+	case 'x':
+		t.handleServerConnect()
+		return nil
 	case 'c':
 		t.handleCopyCommand()
 		return nil
+	case 'C':
+		t.handleCopyAllCommands()
+		return nil
+	case 'y':
+		t.handleCopyConfigBlock()
+		return nil
+	case 'J':
+		t.handleCopyServerJSON()
+		return nil
+	case '<':
+		t.handleResizeSplit(-listWidthStep)
+		return nil
+	case '>':
+		t.handleResizeSplit(listWidthStep)
+		return nil
+	case 'v':
+		t.handleToggleLayout()
+		return nil
 	case 'g':
 		t.handlePingSelected()
 		return nil
+	case 'G':
+		t.handlePingAll()
+		return nil
 	case 'r':
 		t.handleRefreshBackground()
 		return nil
 	case 't':
 		t.handleTagsEdit()
 		return nil
+	case 'E':
+		t.handleQuickEdit()
+		return nil
+	case 'u':
+		t.handleAutoTunnelsEdit()
+		return nil
+	case 'T':
+		t.showTagManager()
+		return nil
+	case 'f':
+		t.handleShowFiles()
+		return nil
+	case 'o':
+		t.handleOpenURL()
+		return nil
+	case 'w':
+		t.handleToggleWrap()
+		return nil
+	case '?':
+		t.showHelpModal()
+		return nil
+	case 'm':
+		t.handleCheckMultiplexing()
+		return nil
+	case 'K':
+		t.handleShowCrypto()
+		return nil
+	case 'M':
+		t.handleCloseMaster()
+		return nil
+	case 'P':
+		t.handleCheckPath()
+		return nil
+	case 'V':
+		t.handleServerConnectVerbose()
+		return nil
+	case 'I':
+		t.handleAddKeysToAgent()
+		return nil
+	case 'L':
+		t.handleToggleSessionLogging()
+		return nil
 	case 'j':
 		t.handleNavigateDown()
 		return nil
@@ -81,23 +164,354 @@ func (t *tui) handleGlobalKeys(event *tcell.EventKey) *tcell.EventKey {
 	}
 
 	if event.Key() == tcell.KeyEnter {
-		t.handleServerConnect()
+		t.handleEnter()
+		return nil
+	}
+
+	if event.Rune() == ' ' {
+		t.handleToggleMark()
 		return nil
 	}
 
+	if event.Modifiers()&tcell.ModCtrl != 0 {
+		switch event.Key() {
+		case tcell.KeyUp:
+			t.handleServerMove(-1)
+			return nil
+		case tcell.KeyDown:
+			t.handleServerMove(1)
+			return nil
+		}
+	}
+	if event.Modifiers()&tcell.ModAlt != 0 {
+		switch event.Key() {
+		case tcell.KeyUp:
+			t.handleConfigMove(-1)
+			return nil
+		case tcell.KeyDown:
+			t.handleConfigMove(1)
+			return nil
+		}
+	}
+
+	if event.Key() == tcell.KeyEscape && len(t.serverList.MarkedServers()) > 0 {
+		t.serverList.ClearMarked()
+		t.showStatusTemp("Selection cleared")
+		return nil
+	}
+
+	return event
+}
+
+// handleDetailsKeys handles keys while the details pane is focused: the TextView scrolls
+// with arrows and PgUp/PgDn itself, j/k map to down/up, and Tab or Esc return to the list.
+func (t *tui) handleDetailsKeys(event *tcell.EventKey) *tcell.EventKey {
+	switch {
+	case event.Key() == tcell.KeyTab, event.Key() == tcell.KeyEscape:
+		t.focusDetails(false)
+		return nil
+	case event.Key() == tcell.KeyEnter && t.display.enterAction == config.EnterActionDetails:
+		t.focusDetails(false)
+		t.handleServerConnect()
+		return nil
+	case event.Rune() == 'w':
+		t.handleToggleWrap()
+		return nil
+	case event.Rune() == 'q':
+		t.handleQuit()
+		return nil
+	case event.Rune() == 'j':
+		return tcell.NewEventKey(tcell.KeyDown, 0, tcell.ModNone)
+	case event.Rune() == 'k':
+		return tcell.NewEventKey(tcell.KeyUp, 0, tcell.ModNone)
+	}
 	return event
 }
 
+// handleEnter runs the Enter action set by enter_action: connect, edit, or focus the details
+// pane, where a second Enter connects.
+func (t *tui) handleEnter() {
+	switch t.display.enterAction {
+	case config.EnterActionEdit:
+		t.handleServerEdit()
+	case config.EnterActionDetails:
+		if _, ok := t.serverList.GetSelectedServer(); ok {
+			t.focusDetails(true)
+		}
+	default:
+		t.handleServerConnect()
+	}
+}
+
+// focusDetails moves keyboard focus between the server list and the details pane.
+func (t *tui) focusDetails(focused bool) {
+	t.details.SetFocused(focused)
+	if focused {
+		t.app.SetFocus(t.details)
+		return
+	}
+	t.app.SetFocus(t.serverList)
+}
+
+// handleToggleSessionLogging flips session logging for the selected server. Choosing the
+// same value as the session_logging config clears the override instead of storing it.
+func (t *tui) handleToggleSessionLogging() {
+	server, ok := t.serverList.GetSelectedServer()
+	if !ok || !t.ensureEditable(server) {
+		return
+	}
+	def := t.serverService.SessionLoggingDefault()
+	enabled := def
+	if server.SessionLogging != nil {
+		enabled = *server.SessionLogging
+	}
+	enabled = !enabled
+
+	var override *bool
+	if enabled != def {
+		override = &enabled
+	}
+	if err := t.serverService.SetSessionLogging(server.Alias, override); err != nil {
+		t.showStatusTempColor(fmt.Sprintf("Failed to update session logging: %v", err), "#FF6B6B")
+		return
+	}
+	t.refreshServerList()
+	if enabled {
+		t.showStatusTemp(fmt.Sprintf("Session logging on for %s", server.Alias))
+	} else {
+		t.showStatusTemp(fmt.Sprintf("Session logging off for %s", server.Alias))
+	}
+}
+
+func (t *tui) handleToggleWrap() {
+	if t.details.ToggleWrap() {
+		t.showStatusTemp("Details wrap: on")
+	} else {
+		t.showStatusTemp("Details wrap: off (←/→ scroll when focused)")
+	}
+}
+
 func (t *tui) handleQuit() {
 	t.app.Stop()
 }
 
 func (t *tui) handleServerPin() {
-	if server, ok := t.serverList.GetSelectedServer(); ok {
-		pinned := server.PinnedAt.IsZero()
-		_ = t.serverService.SetPinned(server.Alias, pinned)
-		t.refreshServerList()
+	server, ok := t.serverList.GetSelectedServer()
+	if !ok || !t.ensureEditable(server) || !t.ensureMetadataWritable() {
+		return
+	}
+	if err := t.serverService.SetPinned(server.Alias, server.PinnedAt.IsZero()); err != nil {
+		t.showStatusTempColor(fmt.Sprintf("Pin failed: %v", err), "#FF6B6B")
+		return
 	}
+	t.refreshServerList()
+}
+
+// handleCheckPath connects to the selected server through its whole proxy chain without
+// prompting and reports which hop failed, or that the path works end to end.
+func (t *tui) handleCheckPath() {
+	server, ok := t.serverList.GetSelectedServer()
+	if !ok {
+		return
+	}
+	t.showStatusTemp(fmt.Sprintf("Checking connection path to %s…", server.Alias))
+	go func() {
+		check := t.serverService.CheckPath(server.Alias)
+		t.app.QueueUpdateDraw(func() {
+			t.showPathCheckModal(server.Alias, check)
+		})
+	}()
+}
+
+// showPathCheckModal shows the outcome of a connection path check.
+func (t *tui) showPathCheckModal(alias string, check domain.PathCheck) {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s: %s\n", tview.Escape(alias), tview.Escape(check.Summary()))
+	if check.Proxy != "" {
+		fmt.Fprintf(&b, "\nVia: %s", tview.Escape(check.Proxy))
+	}
+	if !check.OK() && check.Hop != "" {
+		fmt.Fprintf(&b, "\nFailing hop: %s", tview.Escape(check.Hop))
+	}
+	if check.Message != "" {
+		fmt.Fprintf(&b, "\n\nssh: %s", tview.Escape(check.Message))
+	}
+	fmt.Fprintf(&b, "\n\nTook %s", check.Duration.Round(10*time.Millisecond))
+
+	modal := tview.NewModal().
+		SetText(b.String()).
+		AddButtons([]string{"OK"}).
+		SetDoneFunc(func(int, string) { t.handleModalClose() })
+	t.app.SetRoot(modal, true)
+}
+
+// handleShowCrypto looks up the algorithms ssh would offer the selected server and shows
+// them in the details pane, flagging deprecated ones.
+func (t *tui) handleShowCrypto() {
+	server, ok := t.serverList.GetSelectedServer()
+	if !ok {
+		return
+	}
+	t.showStatusTemp(fmt.Sprintf("Reading effective crypto for %s…", server.Alias))
+	go func() {
+		c, err := t.serverService.EffectiveCrypto(server.Alias)
+		t.app.QueueUpdateDraw(func() {
+			if err != nil {
+				t.showStatusTempColor(fmt.Sprintf("Could not read crypto settings for %s: %v", server.Alias, err), "#FF6B6B")
+				return
+			}
+			t.details.SetCrypto(server.Alias, c)
+			if current, ok := t.serverList.GetSelectedServer(); ok && current.Alias == server.Alias {
+				t.details.UpdateServer(current)
+			}
+			if n := c.DeprecatedCount(); n > 0 {
+				t.showStatusTempColor(fmt.Sprintf("%s offers %d deprecated algorithm(s)", server.Alias, n), "#FF6B6B")
+				return
+			}
+			t.showStatusTempColor(fmt.Sprintf("%s: no deprecated algorithms offered", server.Alias), "#A0FFA0")
+		})
+	}()
+}
+
+// handleCheckMultiplexing checks whether a control master is running for the selected server
+// (`ssh -O check`) and shows the result in the status bar and the details pane.
+func (t *tui) handleCheckMultiplexing() {
+	server, ok := t.serverList.GetSelectedServer()
+	if !ok {
+		return
+	}
+	t.showStatusTemp(fmt.Sprintf("Checking multiplexing for %s…", server.Alias))
+	go func() {
+		path, active := t.serverService.ControlSocket(server.Alias)
+		msg, err := "", error(nil)
+		if active {
+			msg, err = t.serverService.ControlMaster(server.Alias, domain.ControlCheck)
+		}
+		t.app.QueueUpdateDraw(func() {
+			var status string
+			switch {
+			case path == "":
+				status = "[#888888]no ControlPath configured[-]"
+				t.showStatusTemp(fmt.Sprintf("%s has no ControlPath configured", server.Alias))
+			case active && err == nil:
+				status = fmt.Sprintf("[#A0FFA0]multiplexed session active[-] [#888888](%s)[-]", tview.Escape(msg))
+				t.showStatusTempColor(fmt.Sprintf("%s: multiplexed session active (%s)", server.Alias, msg), "#A0FFA0")
+			case active:
+				status = fmt.Sprintf("[#FF6B6B]stale control socket[-] [#888888](%s)[-]", tview.Escape(err.Error()))
+				t.showStatusTempColor(fmt.Sprintf("%s: control socket at %s is not answering: %v", server.Alias, path, err), "#FF6B6B")
+			default:
+				status = "[white]no active master[-]"
+				t.showStatusTemp(fmt.Sprintf("%s: no active master at %s", server.Alias, path))
+			}
+			t.setMultiplexStatus(server.Alias, status)
+		})
+	}()
+}
+
+// handleCloseMaster asks for confirmation and then tears down the selected server's control
+// master with `ssh -O exit`, which also ends every session sharing it.
+func (t *tui) handleCloseMaster() {
+	server, ok := t.serverList.GetSelectedServer()
+	if !ok {
+		return
+	}
+
+	closeMaster := func() {
+		t.showStatusTemp(fmt.Sprintf("Closing master connection for %s…", server.Alias))
+		go func() {
+			msg, err := t.serverService.ControlMaster(server.Alias, domain.ControlExit)
+			t.app.QueueUpdateDraw(func() {
+				if err != nil {
+					t.showStatusTempColor(fmt.Sprintf("Close master %s: %v", server.Alias, err), "#FF6B6B")
+					return
+				}
+				t.setMultiplexStatus(server.Alias, "[white]master closed[-]")
+				t.showStatusTemp(fmt.Sprintf("%s: %s", server.Alias, msg))
+			})
+		}()
+	}
+
+	msg := fmt.Sprintf("Close the master connection for %s?\n\nSessions sharing it will be disconnected.", tview.Escape(server.Alias))
+	t.showConfirm(msg, closeMasterConfirmation, t.handleModalClose, closeMaster)
+}
+
+// closeMasterConfirmation guards closing a master connection. Close has no shortcut, so
+// only moving to it on purpose confirms; k and c keep the connection.
+var closeMasterConfirmation = confirmation{
+	cancelLabel:  "[yellow]K[-]eep",
+	confirmLabel: "Close",
+	cancelKey:    'k',
+	timedOut:     "Close cancelled after no input",
+}
+
+// setMultiplexStatus stores a multiplexing status for alias and redraws the details pane if
+// it is still showing that server.
+func (t *tui) setMultiplexStatus(alias, status string) {
+	t.details.SetMultiplexStatus(alias, status)
+	if current, ok := t.serverList.GetSelectedServer(); ok && current.Alias == alias {
+		t.details.UpdateServer(current)
+	}
+}
+
+// handleServerMove moves the selected Host entry up or down in the SSH config and
+// switches to config-order sorting so the new position is visible. Pinned servers are
+// reordered among the pins instead; handleConfigMove moves them in the config.
+func (t *tui) handleServerMove(offset int) {
+	server, ok := t.serverList.GetSelectedServer()
+	if !ok || !t.ensureEditable(server) {
+		return
+	}
+	if !server.PinnedAt.IsZero() {
+		t.handlePinnedMove(server, offset)
+		return
+	}
+	t.moveInConfig(server, offset)
+}
+
+// handleConfigMove moves the selected Host entry in the SSH config whether or not it is
+// pinned. A pinned server stays among the pins in the list, so only the config changes.
+func (t *tui) handleConfigMove(offset int) {
+	if server, ok := t.serverList.GetSelectedServer(); ok && t.ensureEditable(server) {
+		t.moveInConfig(server, offset)
+	}
+}
+
+func (t *tui) moveInConfig(server domain.Server, offset int) {
+	if !t.ensureConfigWritable() {
+		return
+	}
+	neighbour, err := t.serverService.MoveServer(server.Alias, offset)
+	if err != nil {
+		t.showStatusTempColor(fmt.Sprintf("Move failed: %v", err), "#FF6B6B")
+		return
+	}
+	if t.sortMode != SortByConfigOrder {
+		t.sortMode = SortByConfigOrder
+		t.updateListTitle()
+	}
+	t.refreshServerList()
+
+	where := "below"
+	if offset < 0 {
+		where = "above"
+	}
+	t.showStatusTemp(fmt.Sprintf("Moved %s %s %s in the SSH config", server.Alias, where, neighbour))
+}
+
+// handlePinnedMove changes the position of a pinned server among the pinned servers.
+func (t *tui) handlePinnedMove(server domain.Server, offset int) {
+	neighbour, err := t.serverService.MovePinned(server.Alias, offset)
+	if err != nil {
+		t.showStatusTempColor(fmt.Sprintf("Move failed: %v", err), "#FF6B6B")
+		return
+	}
+	t.refreshServerList()
+
+	where := "below"
+	if offset < 0 {
+		where = "above"
+	}
+	t.showStatusTemp(fmt.Sprintf("Pinned %s %s %s", server.Alias, where, neighbour))
 }
 
 func (t *tui) handleSortToggle() {
@@ -114,23 +528,212 @@ func (t *tui) handleSortReverse() {
 	t.refreshServerList()
 }
 
+// handleToggleResolved switches the list between the literal HostName/Port of each entry
+// and where `ssh -G` says ssh would actually connect.
+func (t *tui) handleToggleResolved() {
+	t.showResolved = !t.showResolved
+	t.updateListTitle()
+	if !t.showResolved {
+		t.serverList.SetEndpoints(nil)
+		t.showStatusTemp("Showing literal host:port from the SSH config")
+		return
+	}
+	t.showStatusTemp("Resolving host:port with ssh -G…")
+	t.resolveEndpointsInBackground()
+}
+
+// resolveEndpointsInBackground resolves every server's endpoint off the UI goroutine and
+// shows the results if resolved mode is still on when they arrive.
+func (t *tui) resolveEndpointsInBackground() {
+	go func() {
+		servers, err := t.serverService.ListServers("")
+		if err != nil {
+			return
+		}
+		aliases := make([]string, 0, len(servers))
+		for _, s := range servers {
+			aliases = append(aliases, s.Alias)
+		}
+		endpoints := t.serverService.ResolveEndpoints(aliases)
+		t.app.QueueUpdateDraw(func() {
+			if t.showResolved {
+				t.serverList.SetEndpoints(endpoints)
+			}
+		})
+	}()
+}
+
 func (t *tui) handleCopyCommand() {
 	if server, ok := t.serverList.GetSelectedServer(); ok {
-		cmd := BuildSSHCommand(server)
-		if err := clipboard.WriteAll(cmd); err == nil {
-			t.showStatusTemp("Copied: " + cmd)
-		} else {
-			t.showStatusTemp("Failed to copy to clipboard")
+		cmd := BuildSSHCommand(server, t.display.defaultIdentityFile)
+		t.copyToClipboard(cmd, "Copied: "+cmd)
+	}
+}
+
+// listWidthStep is how many percentage points < and > move the list/details split.
+const listWidthStep = 5
+
+// handleResizeSplit grows (positive delta) or shrinks the server list relative to the
+// details pane and saves the new split to the config file.
+func (t *tui) handleResizeSplit(delta int) {
+	width := config.ClampListWidth(t.listWidth + delta)
+	if width == t.listWidth {
+		t.showStatusTempColor(fmt.Sprintf("List size is already at %d%%", width), "#FFD75F")
+		return
+	}
+	t.listWidth = width
+	t.content.ResizeItem(t.left, 0, width).ResizeItem(t.right, 0, 100-width)
+
+	t.saveLayoutPreference("list_width_percent", width, fmt.Sprintf("List size: %d%%", width))
+}
+
+// handleToggleLayout switches between details beside the list and details below it,
+// and saves the choice to the config file.
+func (t *tui) handleToggleLayout() {
+	t.stacked = !t.stacked
+	t.content.SetDirection(t.contentDirection())
+	layout, label := config.LayoutSideBySide, "Layout: side by side"
+	if t.stacked {
+		layout, label = config.LayoutStacked, "Layout: stacked"
+	}
+	t.saveLayoutPreference("layout", layout, label)
+}
+
+// layoutSaveDelay debounces layout saves, so holding < or > writes the config file once.
+const layoutSaveDelay = 500 * time.Millisecond
+
+// saveLayoutPreference reports msg and stores a layout setting in the config file once the
+// user stops changing it for layoutSaveDelay.
+func (t *tui) saveLayoutPreference(key string, value any, msg string) {
+	t.showStatusTemp(msg)
+	if t.cfg.Path() == "" {
+		return
+	}
+
+	t.layoutMu.Lock()
+	defer t.layoutMu.Unlock()
+	if t.layoutPending == nil {
+		t.layoutPending = make(map[string]any)
+	}
+	t.layoutPending[key] = value
+	if t.layoutTimer != nil {
+		t.layoutTimer.Stop()
+	}
+	t.layoutTimer = time.AfterFunc(layoutSaveDelay, func() {
+		if err := t.flushLayoutPreferences(); err != nil {
+			t.app.QueueUpdateDraw(func() {
+				t.showStatusTempColor(fmt.Sprintf("Layout not saved: %v", err), "#FF6B6B")
+			})
 		}
+	})
+}
+
+// flushLayoutPreferences writes layout settings still waiting for the debounce; Run calls
+// it on exit so a change made just before quitting is kept.
+func (t *tui) flushLayoutPreferences() error {
+	t.layoutMu.Lock()
+	defer t.layoutMu.Unlock()
+	if t.layoutTimer != nil {
+		t.layoutTimer.Stop()
+	}
+	path := t.cfg.Path()
+	var errs []error
+	for key, value := range t.layoutPending {
+		if err := config.SetValue(path, key, value); err != nil {
+			t.logger.Errorw("failed to save layout preference", "error", err, "key", key, "path", path)
+			errs = append(errs, err)
+		}
+	}
+	t.layoutPending = nil
+	return errors.Join(errs...)
+}
+
+// handleCopyConfigBlock copies the selected server's SSH config Host block, for sharing
+// the entry rather than a one-off command.
+func (t *tui) handleCopyConfigBlock() {
+	server, ok := t.serverList.GetSelectedServer()
+	if !ok {
+		return
+	}
+	t.copyToClipboard(t.serverService.ConfigBlock(server), "Copied config block for "+server.Alias)
+}
+
+// handleCopyServerJSON copies the selected server's config fields and metadata (tags, pin,
+// usage counts) as JSON, for pasting into tickets or scripts.
+func (t *tui) handleCopyServerJSON() {
+	server, ok := t.serverList.GetSelectedServer()
+	if !ok {
+		return
+	}
+	text, err := t.serverService.ServerJSON(server)
+	if err != nil {
+		t.showStatusTempColor(fmt.Sprintf("Failed to encode %s as JSON: %v", server.Alias, err), "#FF6B6B")
+		return
+	}
+	t.copyToClipboard(text, "Copied "+server.Alias+" as JSON")
+}
+
+// handleCopyAllCommands copies the ssh command of every server in the current (filtered) list, one per line.
+func (t *tui) handleCopyAllCommands() {
+	servers := t.serverList.GetServers()
+	if len(servers) == 0 {
+		t.showStatusTempColor("No servers to copy", "#FF6B6B")
+		return
+	}
+	cmds := make([]string, 0, len(servers))
+	for _, server := range servers {
+		cmds = append(cmds, BuildSSHCommand(server, t.display.defaultIdentityFile))
 	}
+	t.copyToClipboard(strings.Join(cmds, "\n"), fmt.Sprintf("Copied %d commands", len(cmds)))
 }
 
 func (t *tui) handleTagsEdit() {
+	if !t.ensureConfigWritable() {
+		return
+	}
+	if server, ok := t.serverList.GetSelectedServer(); ok && t.ensureEditable(server) {
+		t.showTagEditor(server)
+	}
+}
+
+func (t *tui) handleQuickEdit() {
+	if !t.ensureConfigWritable() {
+		return
+	}
+	if server, ok := t.serverList.GetSelectedServer(); ok && t.ensureEditable(server) {
+		t.showQuickEdit(server)
+	}
+}
+
+func (t *tui) handleAutoTunnelsEdit() {
+	if server, ok := t.serverList.GetSelectedServer(); ok && t.ensureEditable(server) {
+		t.showEditAutoTunnelsForm(server)
+	}
+}
+
+func (t *tui) handleShowFiles() {
 	if server, ok := t.serverList.GetSelectedServer(); ok {
-		t.showEditTagsForm(server)
+		t.showServerFilesModal(server)
 	}
 }
 
+func (t *tui) handleOpenURL() {
+	server, ok := t.serverList.GetSelectedServer()
+	if !ok {
+		return
+	}
+	u := server.URL()
+	if u == "" {
+		t.showStatusTempColor("No url: tag on "+server.Alias, "#FF6B6B")
+		return
+	}
+	if err := t.serverService.OpenURL(u); err != nil {
+		t.showStatusTempColor(fmt.Sprintf("Open failed: %v", err), "#FF6B6B")
+		return
+	}
+	t.showStatusTemp("Opened " + u)
+}
+
 func (t *tui) handleNavigateDown() {
 	if t.app.GetFocus() == t.serverList {
 		currentIdx := t.serverList.GetCurrentItem()
@@ -154,10 +757,16 @@ func (t *tui) handleNavigateUp() {
 	}
 }
 
+// handleSearchInput lists the servers matching query as it is typed. The total shown next to
+// the matches is the one counted by the last full load, so keystrokes list servers only once.
 func (t *tui) handleSearchInput(query string) {
 	filtered, _ := t.serverService.ListServers(query)
 	sortServersForUI(filtered, t.sortMode)
-	t.serverList.UpdateServers(filtered)
+	total := t.listTotal
+	if strings.TrimSpace(query) == "" {
+		total = len(filtered)
+	}
+	t.showServers(filtered, query, total)
 	if len(filtered) == 0 {
 		t.details.ShowEmpty()
 	}
@@ -168,21 +777,258 @@ func (t *tui) handleSearchToggle() {
 }
 
 func (t *tui) handleServerConnect() {
-	if server, ok := t.serverList.GetSelectedServer(); ok {
+	server, ok := t.serverList.GetSelectedServer()
+	if !ok {
+		return
+	}
+
+	// The list may predate an external edit of the SSH config: re-read it so a removed host
+	// gets an explanation instead of ssh's "could not resolve hostname".
+	if current, err := t.serverService.ListServers(""); err == nil {
+		fresh, found := findServer(current, server.Alias)
+		if !found {
+			t.showServerGoneModal(server.Alias)
+			return
+		}
+		server = fresh
+	}
+
+	var loose []string
+	for _, key := range server.IdentityFiles {
+		if ok, mode := t.serverService.CheckKeyPermissions(key); !ok {
+			loose = append(loose, fmt.Sprintf("%s (%04o)", key, mode))
+		}
+	}
+	if len(loose) > 0 {
+		t.showKeyPermissionsModal(server, loose)
+		return
+	}
+	t.connect(server)
+}
+
+func (t *tui) connect(server domain.Server) {
+	t.app.Suspend(func() {
+		// The TUI cannot draw while ssh owns the terminal, so announce the connection in
+		// plain (dimmed) text; otherwise the handshake looks like a blank pause.
+		fmt.Printf("\033[2mConnecting to %s…\033[0m\n", connectTarget(server))
+		_ = t.serverService.SSH(server.Alias)
+	})
+	t.refreshServerList()
+}
 
-		t.app.Suspend(func() {
-			_ = t.serverService.SSH(server.Alias)
+// showServerGoneModal explains that the selected host was removed from the SSH config
+// since the list was loaded, and offers to reload the list.
+func (t *tui) showServerGoneModal(alias string) {
+	modal := tview.NewModal().
+		SetText(fmt.Sprintf("%s no longer exists in the SSH config; it was probably changed outside lazyssh.\n\nRefresh the server list?", tview.Escape(alias))).
+		AddButtons([]string{"[yellow]R[-]efresh", "[yellow]C[-]ancel"}).
+		SetDoneFunc(func(buttonIndex int, buttonLabel string) {
+			t.handleModalClose()
+			if buttonIndex == 0 {
+				t.refreshServerList()
+				t.showStatusTemp("Server list refreshed")
+			}
 		})
-		t.refreshServerList()
+
+	modal.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch event.Rune() {
+		case 'r', 'R':
+			t.handleModalClose()
+			t.refreshServerList()
+			t.showStatusTemp("Server list refreshed")
+			return nil
+		case 'c', 'C':
+			t.handleModalClose()
+			return nil
+		}
+		return event
+	})
+
+	t.app.SetRoot(modal, true)
+}
+
+// handleServerConnectVerbose connects once with ssh -vvv and keeps the debug output on screen
+// until Enter is pressed, since returning to the TUI would otherwise clear it.
+func (t *tui) handleServerConnectVerbose() {
+	server, ok := t.serverList.GetSelectedServer()
+	if !ok {
+		return
 	}
+	t.app.Suspend(func() {
+		fmt.Printf("\033[2mConnecting to %s with ssh -vvv…\033[0m\n", connectTarget(server))
+		if err := t.serverService.SSHVerbose(server.Alias); err != nil {
+			fmt.Printf("\nssh exited: %v\n", err)
+		}
+		fmt.Print("\033[2mPress Enter to return to lazyssh\033[0m")
+		_, _ = bufio.NewReader(os.Stdin).ReadString('\n')
+	})
+	t.refreshServerList()
+}
+
+// showKeyPermissionsModal warns that ssh will refuse group/world-readable keys and offers to chmod 600 them.
+func (t *tui) showKeyPermissionsModal(server domain.Server, loose []string) {
+	msg := fmt.Sprintf("These keys are readable by other users and ssh will refuse them:\n\n%s\n\nRestrict them to you (chmod 600)?",
+		tview.Escape(strings.Join(loose, "\n")))
+
+	fixAndConnect := func() {
+		for _, key := range server.IdentityFiles {
+			if ok, _ := t.serverService.CheckKeyPermissions(key); ok {
+				continue
+			}
+			if err := t.serverService.FixKeyPermissions(key); err != nil {
+				t.handleModalClose()
+				t.showStatusTempColor(fmt.Sprintf("Could not fix permissions: %v", err), "#FF6B6B")
+				return
+			}
+		}
+		t.handleModalClose()
+		t.connect(server)
+	}
+
+	modal := tview.NewModal().
+		SetText(msg).
+		AddButtons([]string{"[yellow]F[-]ix & connect", "Connect [yellow]a[-]nyway", "[yellow]C[-]ancel"}).
+		SetDoneFunc(func(buttonIndex int, buttonLabel string) {
+			switch buttonIndex {
+			case 0:
+				fixAndConnect()
+			case 1:
+				t.handleModalClose()
+				t.connect(server)
+			default:
+				t.handleModalClose()
+			}
+		})
+
+	modal.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch event.Rune() {
+		case 'f', 'F':
+			fixAndConnect()
+			return nil
+		case 'a', 'A':
+			t.handleModalClose()
+			t.connect(server)
+			return nil
+		case 'c', 'C':
+			t.handleModalClose()
+			return nil
+		}
+		return event
+	})
+
+	t.app.SetRoot(modal, true)
 }
 
 func (t *tui) handleServerSelectionChange(server domain.Server) {
+	if res, ok := t.serverService.CachedPing(server.Alias); ok {
+		t.details.SetPing(server.Alias, res)
+	} else if t.cfg.PingOnSelect {
+		t.pingInBackground(server)
+	}
+	t.checkAgentInBackground(server)
 	t.details.UpdateServer(server)
 }
 
+// handleAddKeysToAgent loads the selected server's keys into ssh-agent with ssh-add, which
+// prompts for passphrases on the terminal, then refreshes the "in agent" markers.
+func (t *tui) handleAddKeysToAgent() {
+	server, ok := t.serverList.GetSelectedServer()
+	if !ok {
+		return
+	}
+	if len(server.IdentityFiles) == 0 {
+		t.showStatusTempColor(fmt.Sprintf("%s has no IdentityFile to add", server.Alias), "#FFD75F")
+		return
+	}
+	failed := 0
+	t.app.Suspend(func() {
+		for _, f := range server.IdentityFiles {
+			if loaded, err := t.serverService.AgentHasKey(f); err == nil && loaded {
+				continue
+			}
+			if err := t.serverService.AddKeyToAgent(f); err != nil {
+				fmt.Printf("%v\n", err)
+				failed++
+			}
+		}
+		if failed > 0 {
+			fmt.Print("\033[2mPress Enter to return to lazyssh\033[0m")
+			_, _ = bufio.NewReader(os.Stdin).ReadString('\n')
+		}
+	})
+	t.checkAgentInBackground(server)
+	if failed > 0 {
+		t.showStatusTempColor(fmt.Sprintf("ssh-add failed for %d key(s)", failed), "#FF6B6B")
+		return
+	}
+	t.showStatusTemp("Keys loaded into ssh-agent")
+}
+
+// checkAgentInBackground looks up whether the server's keys are loaded in ssh-agent and
+// redraws the details pane with the result. Only one check per alias runs at a time.
+func (t *tui) checkAgentInBackground(server domain.Server) {
+	if len(server.IdentityFiles) == 0 || t.agentChecking[server.Alias] {
+		return
+	}
+	t.agentChecking[server.Alias] = true
+	go func() {
+		loaded := make(map[string]bool, len(server.IdentityFiles))
+		agentDown := false
+		for _, f := range server.IdentityFiles {
+			ok, err := t.serverService.AgentHasKey(f)
+			if errors.Is(err, domain.ErrAgentUnavailable) {
+				agentDown = true
+				break
+			}
+			if err == nil {
+				loaded[f] = ok
+			}
+		}
+		t.app.QueueUpdateDraw(func() {
+			delete(t.agentChecking, server.Alias)
+			if agentDown {
+				t.details.SetAgentDown()
+			}
+			for f, ok := range loaded {
+				t.details.SetAgentKey(f, ok)
+			}
+			if current, ok := t.serverList.GetSelectedServer(); ok && current.Alias == server.Alias {
+				t.details.UpdateServer(current)
+			}
+		})
+	}()
+}
+
+// pingInBackground pings server without status messages; the result shows up in the
+// details pane. Only one ping per alias runs at a time.
+func (t *tui) pingInBackground(server domain.Server) {
+	if t.pinging[server.Alias] {
+		return
+	}
+	t.pinging[server.Alias] = true
+	go func() {
+		res := t.serverService.Ping(server)
+		t.app.QueueUpdateDraw(func() {
+			delete(t.pinging, server.Alias)
+			t.recordPing(server.Alias, res)
+		})
+	}()
+}
+
+// recordPing stores a ping result for the details pane and redraws it if alias is selected.
+// It must run on the UI goroutine.
+func (t *tui) recordPing(alias string, res domain.PingResult) {
+	t.details.SetPing(alias, res)
+	if current, ok := t.serverList.GetSelectedServer(); ok && current.Alias == alias {
+		t.details.UpdateServer(current)
+	}
+}
+
 func (t *tui) handleServerAdd() {
-	form := NewServerForm(ServerFormAdd, nil).
+	if !t.ensureConfigWritable() {
+		return
+	}
+	form := NewServerForm(ServerFormAdd, nil, t.display).
 		SetApp(t.app).
 		SetVersionInfo(t.version, t.commit).
 		OnSave(t.handleServerSave).
@@ -190,9 +1036,44 @@ func (t *tui) handleServerAdd() {
 	t.app.SetRoot(form, true)
 }
 
+func (t *tui) handleServerAddFromCommand() {
+	if !t.ensureConfigWritable() {
+		return
+	}
+	form := tview.NewForm()
+	form.SetBorder(true).
+		SetTitle(" Add from ssh command ").
+		SetTitleAlign(tview.AlignCenter)
+
+	form.AddInputField("Command:", "", 60, nil, nil)
+
+	form.AddButton("Parse", func() {
+		text := form.GetFormItem(0).(*tview.InputField).GetText()
+		server, err := ParseSSHCommand(text)
+		if err != nil {
+			form.SetTitle(fmt.Sprintf(" [#FF6B6B]%v[-] ", err))
+			return
+		}
+		serverForm := NewServerForm(ServerFormAdd, &server, t.display).
+			SetApp(t.app).
+			SetVersionInfo(t.version, t.commit).
+			OnSave(t.handleServerSave).
+			OnCancel(t.handleFormCancel)
+		t.app.SetRoot(serverForm, true)
+	})
+	form.AddButton("Cancel", func() { t.returnToMain() })
+	form.SetCancelFunc(func() { t.returnToMain() })
+
+	t.app.SetRoot(form, true)
+	t.app.SetFocus(form)
+}
+
 func (t *tui) handleServerEdit() {
-	if server, ok := t.serverList.GetSelectedServer(); ok {
-		form := NewServerForm(ServerFormEdit, &server).
+	if !t.ensureConfigWritable() {
+		return
+	}
+	if server, ok := t.serverList.GetSelectedServer(); ok && t.ensureEditable(server) {
+		form := NewServerForm(ServerFormEdit, &server, t.display).
 			SetApp(t.app).
 			SetVersionInfo(t.version, t.commit).
 			OnSave(t.handleServerSave).
@@ -225,11 +1106,28 @@ func (t *tui) handleServerSave(server domain.Server, original *domain.Server) {
 }
 
 func (t *tui) handleServerDelete() {
-	if server, ok := t.serverList.GetSelectedServer(); ok {
+	if !t.ensureConfigWritable() {
+		return
+	}
+	if marked := t.serverList.MarkedServers(); len(marked) > 0 {
+		for _, server := range marked {
+			if !t.ensureEditable(server) {
+				return
+			}
+		}
+		t.showBulkDeleteConfirmModal(marked)
+		return
+	}
+	if server, ok := t.serverList.GetSelectedServer(); ok && t.ensureEditable(server) {
 		t.showDeleteConfirmModal(server)
 	}
 }
 
+func (t *tui) handleToggleMark() {
+	n := t.serverList.ToggleMarked()
+	t.showStatusTemp(fmt.Sprintf("%d selected", n))
+}
+
 func (t *tui) handleFormCancel() {
 	t.returnToMain()
 }
@@ -240,22 +1138,66 @@ func (t *tui) handlePingSelected() {
 
 		t.showStatusTemp(fmt.Sprintf("Pinging %s…", alias))
 		go func() {
-			up, dur, err := t.serverService.Ping(server)
+			res := t.serverService.Ping(server)
 			t.app.QueueUpdateDraw(func() {
-				if err != nil {
-					t.showStatusTempColor(fmt.Sprintf("Ping %s: DOWN (%v)", alias, err), "#FF6B6B")
-					return
+				t.recordPing(alias, res)
+				via := ""
+				if res.Via != "" {
+					via = " via " + res.Via
 				}
-				if up {
-					t.showStatusTempColor(fmt.Sprintf("Ping %s: UP (%s)", alias, dur), "#A0FFA0")
-				} else {
-					t.showStatusTempColor(fmt.Sprintf("Ping %s: DOWN", alias), "#FF6B6B")
+				switch {
+				case res.Up:
+					t.showStatusTempColor(fmt.Sprintf("Ping %s%s: UP (%s)", alias, via, res.Latency), "#A0FFA0")
+				case server.RequiresVPN:
+					t.showStatusTempColor(fmt.Sprintf("Ping %s%s: VPN? (unreachable; this host requires a VPN)", alias, via), "#FFD75F")
+				case res.Err != nil:
+					t.showStatusTempColor(fmt.Sprintf("Ping %s%s: DOWN (%v)", alias, via, res.Err), "#FF6B6B")
+				default:
+					t.showStatusTempColor(fmt.Sprintf("Ping %s%s: DOWN", alias, via), "#FF6B6B")
 				}
 			})
 		}()
 	}
 }
 
+// handlePingAll pings every server in the current (filtered) list in the background and reports a summary.
+func (t *tui) handlePingAll() {
+	servers := t.serverList.GetServers()
+	if len(servers) == 0 {
+		return
+	}
+
+	t.showStatusTemp(fmt.Sprintf("Pinging %d servers…", len(servers)))
+	go func() {
+		results := t.serverService.PingAll(servers)
+		up, down, vpn := 0, 0, 0
+		for _, s := range servers {
+			switch res := results[s.Alias]; {
+			case res.Up:
+				up++
+			case s.RequiresVPN:
+				vpn++
+			default:
+				down++
+			}
+		}
+		t.app.QueueUpdateDraw(func() {
+			for alias, res := range results {
+				t.recordPing(alias, res)
+			}
+			msg := fmt.Sprintf("Ping: %d up, %d down", up, down)
+			if vpn > 0 {
+				msg += fmt.Sprintf(", %d VPN?", vpn)
+			}
+			if down > 0 {
+				t.showStatusTempColor(msg, "#FF6B6B")
+				return
+			}
+			t.showStatusTempColor(msg, "#A0FFA0")
+		})
+	}()
+}
+
 func (t *tui) handleModalClose() {
 	t.returnToMain()
 }
@@ -263,7 +1205,6 @@ func (t *tui) handleModalClose() {
 // handleRefreshBackground refreshes the server list in the background without leaving the current screen.
 // It preserves the current search query and selection, shows transient status, and avoids concurrent runs.
 func (t *tui) handleRefreshBackground() {
-	currentIdx := t.serverList.GetCurrentItem()
 	query := ""
 	if t.searchVisible {
 		query = t.searchBar.InputField.GetText()
@@ -271,27 +1212,20 @@ func (t *tui) handleRefreshBackground() {
 
 	t.showStatusTemp("Refreshing…")
 
-	go func(prevIdx int, q string) {
-		servers, err := t.serverService.ListServers(q)
+	go func(q string) {
+		shown, all, err := t.loadServers(q)
 		if err != nil {
 			t.app.QueueUpdateDraw(func() {
 				t.showStatusTempColor(fmt.Sprintf("Refresh failed: %v", err), "#FF6B6B")
 			})
 			return
 		}
-		sortServersForUI(servers, t.sortMode)
 		t.app.QueueUpdateDraw(func() {
-			t.serverList.UpdateServers(servers)
-			// Try to restore selection if still valid
-			if prevIdx >= 0 && prevIdx < t.serverList.List.GetItemCount() {
-				t.serverList.SetCurrentItem(prevIdx)
-				if srv, ok := t.serverList.GetSelectedServer(); ok {
-					t.details.UpdateServer(srv)
-				}
-			}
-			t.showStatusTemp(fmt.Sprintf("Refreshed %d servers", len(servers)))
+			t.showLoadedServers(shown, all, q)
+			t.showStatusTemp(fmt.Sprintf("Refreshed %d servers", len(shown)))
+			t.showStorageWarnings()
 		})
-	}(currentIdx, query)
+	}(query)
 }
 
 // =============================================================================
@@ -310,64 +1244,129 @@ func (t *tui) showDeleteConfirmModal(server domain.Server) {
 	msg := fmt.Sprintf("Delete server %s (%s@%s:%d)?\n\nThis action cannot be undone.",
 		server.Alias, server.User, server.Host, server.Port)
 
+	t.showDeleteConfirm(msg, func() {
+		if err := t.serverService.DeleteServer(server); err != nil {
+			t.showStatusTempColor(fmt.Sprintf("Delete failed: %v", err), "#FF6B6B")
+			return
+		}
+		t.refreshServerList()
+	})
+}
+
+// showServerFilesModal lists the SSH config entry, metadata file and identity files behind a server.
+func (t *tui) showServerFilesModal(server domain.Server) {
+	files, _ := t.serverService.ServerFiles(server.Alias)
+
+	configLoc := files.ConfigPath
+	if files.ConfigLine > 0 {
+		configLoc = fmt.Sprintf("%s:%d", files.ConfigPath, files.ConfigLine)
+	}
+	lines := []string{
+		"SSH config: " + configLoc,
+		"Metadata:   " + files.MetadataPath,
+	}
+	for _, key := range server.IdentityFiles {
+		path := expandHomePath(key)
+		if exists, _, _ := validateFilePath(key); !exists {
+			path += " (missing)"
+		}
+		lines = append(lines, "Identity:   "+path)
+	}
+	plain := strings.Join(lines, "\n")
+
 	modal := tview.NewModal().
-		SetText(msg).
-		AddButtons([]string{"[yellow]C[-]ancel", "[yellow]D[-]elete"}).
+		SetText(fmt.Sprintf("Files for %s\n\n%s", server.Alias, tview.Escape(plain))).
+		AddButtons([]string{"[yellow]C[-]opy", "Close"}).
 		SetDoneFunc(func(buttonIndex int, buttonLabel string) {
-			if buttonIndex == 1 {
-				_ = t.serverService.DeleteServer(server)
-				t.refreshServerList()
-			}
 			t.handleModalClose()
+			if buttonIndex == 0 {
+				t.copyFilePaths(plain)
+			}
 		})
-
-	// Add keyboard shortcuts for the modal
 	modal.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
-		switch event.Rune() {
-		case 'c', 'C':
-			// Cancel
-			t.handleModalClose()
-			return nil
-		case 'd', 'D':
-			// Delete
-			_ = t.serverService.DeleteServer(server)
-			t.refreshServerList()
+		if event.Rune() == 'c' || event.Rune() == 'C' {
 			t.handleModalClose()
+			t.copyFilePaths(plain)
 			return nil
 		}
-		// ESC key already handled by default modal behavior
 		return event
 	})
 
 	t.app.SetRoot(modal, true)
 }
 
-func (t *tui) showEditTagsForm(server domain.Server) {
+func (t *tui) copyFilePaths(text string) {
+	t.copyToClipboard(text, "Copied file paths")
+}
+
+// maxBulkDeleteListed caps how many aliases the bulk delete confirmation spells out.
+const maxBulkDeleteListed = 10
+
+func (t *tui) showBulkDeleteConfirmModal(servers []domain.Server) {
+	names := make([]string, 0, maxBulkDeleteListed+1)
+	for i, s := range servers {
+		if i == maxBulkDeleteListed {
+			names = append(names, fmt.Sprintf("…and %d more", len(servers)-maxBulkDeleteListed))
+			break
+		}
+		names = append(names, s.Alias)
+	}
+	msg := fmt.Sprintf("Delete %d servers?\n\n%s\n\nThis action cannot be undone.",
+		len(servers), tview.Escape(strings.Join(names, "\n")))
+
+	t.showDeleteConfirm(msg, func() {
+		deleted, failed := 0, 0
+		for _, s := range servers {
+			if err := t.serverService.DeleteServer(s); err != nil {
+				failed++
+				continue
+			}
+			deleted++
+		}
+		t.serverList.ClearMarked()
+		t.refreshServerList()
+		if failed > 0 {
+			t.showStatusTempColor(fmt.Sprintf("Deleted %d servers, %d failed", deleted, failed), "#FF6B6B")
+			return
+		}
+		t.showStatusTemp(fmt.Sprintf("Deleted %d servers", deleted))
+	})
+}
+
+// showEditAutoTunnelsForm edits the local forwards lazyssh opens with every session to the
+// server. They live in metadata rather than as LocalForward in the SSH config.
+func (t *tui) showEditAutoTunnelsForm(server domain.Server) {
 	form := tview.NewForm()
 	form.SetBorder(true).
-		SetTitle(fmt.Sprintf(" Edit Tags: %s ", server.Alias)).
+		SetTitle(fmt.Sprintf(" Auto Tunnels: %s ", server.Alias)).
 		SetTitleAlign(tview.AlignCenter)
 
-	defaultTags := strings.Join(server.Tags, ", ")
-	form.AddInputField("Tags (comma):", defaultTags, 40, nil, nil)
+	form.AddInputField("Forwards (comma):", strings.Join(server.AutoTunnels, ", "), 50, nil, nil)
+	form.AddCheckbox("Enabled:", !server.AutoTunnelsDisabled, nil)
 
 	form.AddButton("Save", func() {
-		text := strings.TrimSpace(form.GetFormItem(0).(*tview.InputField).GetText())
-		var tags []string
-
+		text := form.GetFormItem(0).(*tview.InputField).GetText()
+		enabled := form.GetFormItem(1).(*tview.Checkbox).IsChecked()
+		var specs []string
 		for _, part := range strings.Split(text, ",") {
-			if s := strings.TrimSpace(part); s != "" {
-				tags = append(tags, s)
+			spec := strings.TrimSpace(part)
+			if spec == "" {
+				continue
+			}
+			if err := validatePortForward(spec); err != nil {
+				form.SetTitle(fmt.Sprintf(" [#FF6B6B]%s: %v[-] ", tview.Escape(spec), err))
+				return
 			}
+			specs = append(specs, spec)
 		}
 
-		newServer := server
-		newServer.Tags = tags
-		_ = t.serverService.UpdateServer(server, newServer)
-		// Refresh UI and go back
+		if err := t.serverService.SetAutoTunnels(server.Alias, specs, !enabled); err != nil {
+			t.showStatusTempColor(fmt.Sprintf("Failed to update auto tunnels: %v", err), "#FF6B6B")
+			return
+		}
 		t.refreshServerList()
 		t.returnToMain()
-		t.showStatusTemp("Tags updated")
+		t.showStatusTemp("Auto tunnels updated")
 	})
 	form.AddButton("Cancel", func() { t.returnToMain() })
 	form.SetCancelFunc(func() { t.returnToMain() })
@@ -397,9 +1396,49 @@ func (t *tui) refreshServerList() {
 	if t.searchVisible {
 		query = t.searchBar.InputField.GetText()
 	}
-	filtered, _ := t.serverService.ListServers(query)
-	sortServersForUI(filtered, t.sortMode)
-	t.serverList.UpdateServers(filtered)
+	shown, all, err := t.loadServers(query)
+	if err != nil {
+		t.showStatusTempColor(fmt.Sprintf("Failed to load servers: %v", err), "#FF6B6B")
+		return
+	}
+	t.showLoadedServers(shown, all, query)
+	t.showStorageWarnings()
+	if t.showResolved {
+		t.resolveEndpointsInBackground()
+	}
+}
+
+// showStorageWarnings surfaces non-fatal storage problems (e.g. recovered metadata) in the status bar.
+func (t *tui) showStorageWarnings() {
+	if warnings := t.serverService.DrainWarnings(); len(warnings) > 0 {
+		t.showStatusTempColor(strings.Join(warnings, "  • "), "#FF6B6B")
+	}
+}
+
+// ensureConfigWritable reports whether the SSH config can be saved, explaining in the
+// status bar why the action is unavailable when it cannot.
+func (t *tui) ensureConfigWritable() bool {
+	if t.readOnlyConfig == "" {
+		return true
+	}
+	t.showStatusTempColor("Read-only config: "+t.readOnlyConfig+" is not writable, so add/edit/delete are disabled", "#FF6B6B")
+	return false
+}
+
+// ensureEditable reports whether server can be changed, explaining in the status bar when
+// it comes from the remote inventory, which lazyssh only reads.
+func (t *tui) ensureEditable(server domain.Server) bool {
+	if !server.ReadOnly {
+		return true
+	}
+	t.showStatusTempColor(server.Alias+" is from the remote inventory and read-only", "#FF6B6B")
+	return false
+}
+
+// ensureMetadataWritable is ensureConfigWritable for changes to tags and pins, which only
+// reach the SSH config with metadata_in_config.
+func (t *tui) ensureMetadataWritable() bool {
+	return !t.cfg.MetadataInConfig || t.ensureConfigWritable()
 }
 
 func (t *tui) returnToMain() {
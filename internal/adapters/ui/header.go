@@ -15,23 +15,44 @@
 package ui
 
 import (
+	"fmt"
 	"strings"
 	"time"
 
 	"github.com/gdamore/tcell/v2"
 	"github.com/rivo/tview"
+
+	"github.com/Adembc/lazyssh/internal/core/domain"
 )
 
+// HeaderStats is the activity summary shown in the header.
+type HeaderStats struct {
+	// HostsToday counts servers last connected to today.
+	HostsToday int
+	// Sessions is the total number of sessions recorded across all servers.
+	Sessions int
+}
+
+// StatsProvider returns the header's activity summary; ok is false when there is nothing
+// to show, e.g. no usage has been recorded yet.
+type StatsProvider func() (stats HeaderStats, ok bool)
+
 type AppHeader struct {
 	*tview.Flex
+	display   displaySettings
 	version   string
 	gitCommit string
 	repoURL   string
+
+	center     *tview.TextView
+	centerBase string
+	stats      StatsProvider
 }
 
-func NewAppHeader(version, gitCommit, repoURL string) *AppHeader {
+func NewAppHeader(display displaySettings, version, gitCommit, repoURL string) *AppHeader {
 	header := &AppHeader{
 		Flex:      tview.NewFlex(),
+		display:   display,
 		version:   version,
 		repoURL:   repoURL,
 		gitCommit: gitCommit,
@@ -64,7 +85,7 @@ func (h *AppHeader) buildLeftSection(bg tcell.Color) *tview.TextView {
 		SetDynamicColors(true).
 		SetTextAlign(tview.AlignLeft)
 	left.SetBackgroundColor(bg)
-	stylizedName := "🚀 [#FFFFFF::b]lazy[-][#55D7FF::b]ssh[-]"
+	stylizedName := h.display.glyph("🚀", ">") + " [#FFFFFF::b]lazy[-][#55D7FF::b]ssh[-]"
 	left.SetText(stylizedName)
 	return left
 }
@@ -90,23 +111,69 @@ func (h *AppHeader) buildCenterSection(bg tcell.Color) *tview.TextView {
 	}
 
 	center.SetText(text)
+	h.center, h.centerBase = center, text
 	return center
 }
 
+// SetStatsProvider sets where the header's activity summary comes from and shows it.
+func (h *AppHeader) SetStatsProvider(p StatsProvider) *AppHeader {
+	h.stats = p
+	h.RefreshStats()
+	return h
+}
+
+// RefreshStats re-reads the activity summary; without a provider, or when it has nothing
+// to report, only the version chips are shown.
+func (h *AppHeader) RefreshStats() {
+	if h.center == nil {
+		return
+	}
+	text := h.centerBase
+	if h.stats != nil {
+		if stats, ok := h.stats(); ok {
+			text += "  [#AAAAAA]" + formatHeaderStats(stats) + "[-]"
+		}
+	}
+	h.center.SetText(text)
+}
+
+// formatHeaderStats describes stats for the header. Sessions are counted over all time,
+// since only a per-server total is recorded, so they are labelled as a total.
+func formatHeaderStats(stats HeaderStats) string {
+	return fmt.Sprintf("%d host(s) today · %d sessions total", stats.HostsToday, stats.Sessions)
+}
+
+// computeHeaderStats summarizes usage from the servers' metadata; ok is false when no
+// session has been recorded.
+func computeHeaderStats(servers []domain.Server, now time.Time) (HeaderStats, bool) {
+	var stats HeaderStats
+	y, m, d := now.Date()
+	for _, s := range servers {
+		stats.Sessions += s.SSHCount
+		if s.LastSeen.IsZero() {
+			continue
+		}
+		if sy, sm, sd := s.LastSeen.In(now.Location()).Date(); sy == y && sm == m && sd == d {
+			stats.HostsToday++
+		}
+	}
+	return stats, stats.Sessions > 0 || stats.HostsToday > 0
+}
+
 func (h *AppHeader) buildRightSection(bg tcell.Color) *tview.TextView {
 	right := tview.NewTextView().
 		SetDynamicColors(true).
 		SetTextAlign(tview.AlignRight)
 	right.SetBackgroundColor(bg)
 	currentTime := time.Now().Format("Mon, 02 Jan 2006 15:04")
-	right.SetText("[#55AAFF::u]🔗 " + h.repoURL + "[-]  [#AAAAAA]• " + currentTime + "[-]")
+	right.SetText("[#55AAFF::u]" + h.display.glyph("🔗 ", "") + h.repoURL + "[-]  [#AAAAAA]• " + currentTime + "[-]")
 	return right
 }
 
 func (h *AppHeader) createSeparator() *tview.TextView {
 	separator := tview.NewTextView().SetDynamicColors(true)
 	separator.SetBackgroundColor(tcell.Color235)
-	separator.SetText("[#444444]" + strings.Repeat("─", 200) + "[-]")
+	separator.SetText("[#444444]" + strings.Repeat(h.display.glyph("─", "-"), 200) + "[-]")
 	return separator
 }
 
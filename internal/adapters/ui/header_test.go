@@ -0,0 +1,59 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ui
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Adembc/lazyssh/internal/core/domain"
+)
+
+func TestComputeHeaderStats(t *testing.T) {
+	now := time.Date(2025, time.March, 15, 14, 30, 0, 0, time.UTC)
+	tests := []struct {
+		name    string
+		servers []domain.Server
+		want    HeaderStats
+		wantOK  bool
+	}{
+		{name: "no usage", servers: []domain.Server{{Alias: "a"}}, wantOK: false},
+		{
+			name: "today and earlier",
+			servers: []domain.Server{
+				{Alias: "a", LastSeen: now.Add(-time.Hour), SSHCount: 4},
+				{Alias: "b", LastSeen: time.Date(2025, time.March, 14, 23, 0, 0, 0, time.UTC), SSHCount: 2},
+				{Alias: "c"},
+			},
+			want:   HeaderStats{HostsToday: 1, Sessions: 6},
+			wantOK: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := computeHeaderStats(tt.servers, now)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("computeHeaderStats() = %+v, %v, want %+v, %v", got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestFormatHeaderStats(t *testing.T) {
+	got := formatHeaderStats(HeaderStats{HostsToday: 2, Sessions: 41})
+	if want := "2 host(s) today · 41 sessions total"; got != want {
+		t.Errorf("formatHeaderStats() = %q, want %q", got, want)
+	}
+}
@@ -15,13 +15,27 @@
 package ui
 
 import (
+	"github.com/Adembc/lazyssh/internal/config"
 	"github.com/gdamore/tcell/v2"
 	"github.com/rivo/tview"
 )
 
-func NewHintBar() *tview.TextView {
+// enterHints name the Enter action in the hint bar for each config.EnterAction value.
+var enterHints = map[string]string{
+	config.EnterActionConnect: "SSH",
+	config.EnterActionEdit:    "Edit",
+	config.EnterActionDetails: "Details",
+}
+
+// NewHintBar lists the most used keys, with Enter doing enterAction. When Enter does not
+// connect, x is shown as the way to.
+func NewHintBar(enterAction string) *tview.TextView {
 	hint := tview.NewTextView().SetDynamicColors(true)
 	hint.SetBackgroundColor(tcell.Color233)
-	hint.SetText("[#BBBBBB]Press [::b]/[-:-:b] to search…  •  ↑↓ Navigate  •  Enter SSH  •  c Copy SSH  •  g Ping  •  r Refresh  •  a Add  •  e Edit  •  t Tags  •  d Delete  •  p Pin/Unpin  •  s Sort[-]")
+	enter := "Enter " + enterHints[enterAction]
+	if enterAction != config.EnterActionConnect {
+		enter += "  •  x SSH"
+	}
+	hint.SetText("[#BBBBBB]Press [::b]/[-:-:b] to search…  •  ↑↓ Navigate  •  " + enter + "  •  c Copy SSH  •  g Ping  •  r Refresh  •  a Add  •  e Edit  •  t Tags  •  d Delete  •  p Pin/Unpin  •  s Sort  •  ? Help[-]")
 	return hint
 }
@@ -0,0 +1,257 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Adembc/lazyssh/internal/config"
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// keyBinding documents one main-screen shortcut. handleGlobalKeys implements the keys;
+// this registry is the single source for the help screen and the details command list, and
+// TestKeyBindingsCoverHandledKeys fails when a handled key is missing from it.
+type keyBinding struct {
+	key         string
+	description string
+	category    string
+}
+
+const (
+	categoryNavigation = "Navigation"
+	categoryConnection = "Connection"
+	categoryEditing    = "Editing"
+	categoryFiltering  = "Filtering"
+	categoryAdvanced   = "Advanced"
+)
+
+// keyCategories is the order categories are shown in.
+var keyCategories = []string{categoryNavigation, categoryConnection, categoryEditing, categoryFiltering, categoryAdvanced}
+
+var keyBindings = []keyBinding{
+	{"↑/↓, j/k", "Move selection", categoryNavigation},
+	{"Tab", "Focus details to scroll (Tab/Esc back)", categoryNavigation},
+	{"w", "Toggle details word-wrap", categoryNavigation},
+	{"< / >", "Shrink / grow the list", categoryNavigation},
+	{"v", "Details beside / below the list", categoryNavigation},
+	{"?", "Show this help", categoryNavigation},
+	{"q", "Quit", categoryNavigation},
+
+	{"Enter", "SSH connect", categoryConnection},
+	{"x", "SSH connect", categoryConnection},
+	{"c", "Copy SSH command", categoryConnection},
+	{"C", "Copy all listed commands", categoryConnection},
+	{"y", "Copy SSH config block", categoryConnection},
+	{"J", "Copy server as JSON", categoryConnection},
+	{"g", "Ping server", categoryConnection},
+	{"G", "Ping all listed", categoryConnection},
+	{"o", "Open url: tag in browser", categoryConnection},
+	{"m", "Check multiplexed session", categoryConnection},
+	{"M", "Close multiplexed master", categoryConnection},
+	{"V", "Connect once with ssh -vvv", categoryConnection},
+	{"I", "Load the server's keys into ssh-agent", categoryConnection},
+	{"P", "Test connection path through proxies", categoryConnection},
+
+	{"a", "Add new server", categoryEditing},
+	{"A", "Add server from a pasted ssh command", categoryEditing},
+	{"e", "Edit entry", categoryEditing},
+	{"E", "Quick-edit a single field", categoryEditing},
+	{"t", "Edit tags inline (Tab to reorder)", categoryEditing},
+	{"u", "Edit auto tunnels (-L opened on connect)", categoryEditing},
+	{"T", "Manage all tags", categoryEditing},
+	{"p", "Pin/Unpin", categoryEditing},
+	{"Space", "Select for bulk delete (Esc clears)", categoryEditing},
+	{"d", "Delete entry (or selected)", categoryEditing},
+
+	{"/", "Search", categoryFiltering},
+	{"s", "Cycle sort field", categoryFiltering},
+	{"S", "Reverse sort order", categoryFiltering},
+	{"R", "Toggle literal vs ssh -G resolved host:port", categoryFiltering},
+	{"r", "Refresh list", categoryFiltering},
+
+	{"Ctrl+↑/↓", "Move entry in SSH config (pinned: in pin order)", categoryAdvanced},
+	{"Alt+↑/↓", "Move entry in SSH config, pinned too", categoryAdvanced},
+	{"f", "Show file paths", categoryAdvanced},
+	{"L", "Toggle session logging for server", categoryAdvanced},
+	{"K", "Show effective ciphers/MACs/KEX", categoryAdvanced},
+}
+
+// enterDescriptions describe Enter in the help for each config.EnterAction value.
+var enterDescriptions = map[string]string{
+	config.EnterActionConnect: "SSH connect",
+	config.EnterActionEdit:    "Edit entry",
+	config.EnterActionDetails: "Focus details (Enter again connects)",
+}
+
+// bindingsFor returns keyBindings with Enter described as doing enterAction, as configured
+// by enter_action.
+func bindingsFor(enterAction string) []keyBinding {
+	bindings := append([]keyBinding(nil), keyBindings...)
+	for i := range bindings {
+		if bindings[i].key == "Enter" {
+			bindings[i].description = enterDescriptions[enterAction]
+		}
+	}
+	return bindings
+}
+
+// searchSyntax documents the query forms the search bar understands; terms combine with AND.
+var searchSyntax = []struct {
+	query       string
+	description string
+}{
+	{"text", "Alias, host, user or tag contains text"},
+	{"host:10.0", "Only HostName contains 10.0"},
+	{"user:root", "Only User contains root"},
+	{"alias:web", "Only an alias contains web"},
+	{"stale:true", "Only stale servers (stale:false hides them)"},
+}
+
+// renderKeyBindings renders the registry grouped by category, with Enter doing enterAction.
+// With compact set, headers are omitted and lines are indented for the details pane.
+func renderKeyBindings(compact bool, enterAction string) string {
+	if !compact {
+		return renderHelp("", enterAction)
+	}
+	var b strings.Builder
+	for _, category := range keyCategories {
+		for _, kb := range bindingsFor(enterAction) {
+			if kb.category == category {
+				fmt.Fprintf(&b, "  %s: %s\n", kb.key, kb.description)
+			}
+		}
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
+
+// renderHelp renders the help screen: bindings grouped by category, with Enter doing
+// enterAction, then the search syntax, keeping only entries that match filter. Categories
+// without a match are left out.
+func renderHelp(filter, enterAction string) string {
+	words := strings.Fields(strings.ToLower(filter))
+	var b strings.Builder
+	section := func(title string, lines []string) {
+		if len(lines) == 0 {
+			return
+		}
+		if b.Len() > 0 {
+			b.WriteString("\n")
+		}
+		fmt.Fprintf(&b, "[::b]%s[-:-:-]\n", title)
+		for _, line := range lines {
+			b.WriteString(line + "\n")
+		}
+	}
+	for _, category := range keyCategories {
+		var lines []string
+		for _, kb := range bindingsFor(enterAction) {
+			if kb.category == category && matchesHelpFilter(words, kb.key, kb.description, kb.category) {
+				lines = append(lines, fmt.Sprintf("  [yellow]%-10s[-] %s", tview.Escape(kb.key), kb.description))
+			}
+		}
+		section(category, lines)
+	}
+	var lines []string
+	for _, s := range searchSyntax {
+		if matchesHelpFilter(words, s.query, s.description, "search") {
+			lines = append(lines, fmt.Sprintf("  [yellow]%-10s[-] %s", s.query, s.description))
+		}
+	}
+	section("Search", lines)
+
+	if b.Len() == 0 {
+		return "[#888888]No shortcuts match \"" + tview.Escape(filter) + "\"[-]"
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
+
+// matchesHelpFilter reports whether every filter word occurs in one of fields.
+func matchesHelpFilter(words []string, fields ...string) bool {
+	text := strings.ToLower(strings.Join(fields, " "))
+	for _, w := range words {
+		if !strings.Contains(text, w) {
+			return false
+		}
+	}
+	return true
+}
+
+// showHelpModal shows every shortcut grouped by category, filtered as you type.
+// Arrows and PgUp/PgDn scroll; Esc clears the filter, or closes the help when it is empty.
+func (t *tui) showHelpModal() {
+	text := tview.NewTextView().
+		SetDynamicColors(true).
+		SetScrollable(true).
+		SetText(renderHelp("", t.display.enterAction))
+
+	filter := tview.NewInputField().
+		SetLabel(" Filter: ").
+		SetFieldWidth(0).
+		SetPlaceholder("type to find a shortcut, e.g. pin")
+	filter.SetChangedFunc(func(query string) {
+		text.SetText(renderHelp(query, t.display.enterAction)).ScrollToBeginning()
+	})
+	filter.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		row, _ := text.GetScrollOffset()
+		switch event.Key() {
+		case tcell.KeyEscape:
+			if filter.GetText() != "" {
+				filter.SetText("")
+				return nil
+			}
+			t.handleModalClose()
+			return nil
+		case tcell.KeyUp:
+			text.ScrollTo(max(row-1, 0), 0)
+			return nil
+		case tcell.KeyDown:
+			text.ScrollTo(row+1, 0)
+			return nil
+		case tcell.KeyPgUp:
+			text.ScrollTo(max(row-10, 0), 0)
+			return nil
+		case tcell.KeyPgDn:
+			text.ScrollTo(row+10, 0)
+			return nil
+		}
+		return event
+	})
+
+	panel := tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(filter, 1, 0, true).
+		AddItem(text, 0, 1, false)
+	panel.SetBorder(true).
+		SetTitle(" Help — Esc to close ").
+		SetTitleAlign(tview.AlignCenter).
+		SetBorderColor(tcell.Color238).
+		SetTitleColor(tcell.Color250)
+
+	// Center a fixed-size box over the main screen.
+	box := tview.NewFlex().
+		AddItem(nil, 0, 1, false).
+		AddItem(tview.NewFlex().SetDirection(tview.FlexRow).
+			AddItem(nil, 0, 1, false).
+			AddItem(panel, 0, 4, true).
+			AddItem(nil, 0, 1, false), 60, 0, true).
+		AddItem(nil, 0, 1, false)
+
+	t.app.SetRoot(tview.NewPages().
+		AddPage("main", t.root, true, true).
+		AddPage("help", box, true, true), true)
+	t.app.SetFocus(filter)
+}
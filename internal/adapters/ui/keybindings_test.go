@@ -0,0 +1,138 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ui
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/Adembc/lazyssh/internal/config"
+)
+
+func TestKeyBindingsRegistry(t *testing.T) {
+	known := make(map[string]bool, len(keyCategories))
+	for _, c := range keyCategories {
+		known[c] = true
+	}
+
+	full := renderKeyBindings(false, config.EnterActionConnect)
+	compact := renderKeyBindings(true, config.EnterActionConnect)
+	for _, kb := range keyBindings {
+		if !known[kb.category] {
+			t.Errorf("binding %q has unknown category %q", kb.key, kb.category)
+		}
+		if !strings.Contains(full, kb.description) || !strings.Contains(compact, kb.key+": "+kb.description) {
+			t.Errorf("binding %q missing from rendered help", kb.key)
+		}
+	}
+	for _, c := range keyCategories {
+		if !strings.Contains(full, c) {
+			t.Errorf("category %q missing from help", c)
+		}
+	}
+}
+
+func TestRenderHelpFilter(t *testing.T) {
+	got := renderHelp("UNPIN", config.EnterActionConnect)
+	if !strings.Contains(got, "Pin/Unpin") {
+		t.Errorf("filter %q should keep the pin binding, got:\n%s", "UNPIN", got)
+	}
+	if strings.Contains(got, "Copy SSH command") || strings.Contains(got, categoryConnection) {
+		t.Errorf("filter %q should drop unrelated bindings and empty categories, got:\n%s", "UNPIN", got)
+	}
+	if got := renderHelp("host:", config.EnterActionConnect); !strings.Contains(got, "Only HostName contains") {
+		t.Errorf("filter should also search the search syntax, got:\n%s", got)
+	}
+	if got := renderHelp("zzz", config.EnterActionConnect); !strings.Contains(got, "No shortcuts match") {
+		t.Errorf("renderHelp(zzz) = %q, want a no-match note", got)
+	}
+}
+
+func TestEnterActionHelp(t *testing.T) {
+	if got := renderHelp("enter", config.EnterActionDetails); !strings.Contains(got, "Focus details (Enter again connects)") {
+		t.Errorf("help with Enter focusing details =\n%s", got)
+	}
+	if got := renderHelp("enter", config.EnterActionEdit); !strings.Contains(got, "Edit entry") || strings.Contains(got, "SSH connect") {
+		t.Errorf("help with Enter editing =\n%s", got)
+	}
+	if got := renderHelp("enter", config.EnterActionConnect); !strings.Contains(got, "SSH connect") {
+		t.Errorf("help with Enter connecting =\n%s", got)
+	}
+	if got := NewHintBar(config.EnterActionEdit).GetText(false); !strings.Contains(got, "x SSH") {
+		t.Errorf("hint bar with Enter editing does not offer x to connect: %q", got)
+	}
+	if got := NewHintBar(config.EnterActionConnect).GetText(false); strings.Contains(got, "x SSH") {
+		t.Errorf("hint bar with Enter connecting lists x too: %q", got)
+	}
+}
+
+// TestKeyBindingsCoverHandledKeys keeps the help in sync with handleGlobalKeys: every rune
+// the handler matches must be documented in keyBindings.
+func TestKeyBindingsCoverHandledKeys(t *testing.T) {
+	documented := make(map[rune]bool)
+	for _, kb := range keyBindings {
+		parts := []string{kb.key}
+		if len([]rune(kb.key)) > 1 {
+			parts = strings.FieldsFunc(kb.key, func(r rune) bool { return r == '/' || r == ',' })
+		}
+		for _, part := range parts {
+			part = strings.TrimSpace(part)
+			if part == "Space" {
+				part = " "
+			}
+			if r := []rune(part); len(r) == 1 {
+				documented[r[0]] = true
+			}
+		}
+	}
+
+	file, err := parser.ParseFile(token.NewFileSet(), "handlers.go", nil, 0)
+	if err != nil {
+		t.Fatalf("parse handlers.go: %v", err)
+	}
+	var handler *ast.FuncDecl
+	for _, decl := range file.Decls {
+		if fn, ok := decl.(*ast.FuncDecl); ok && fn.Name.Name == "handleGlobalKeys" {
+			handler = fn
+		}
+	}
+	if handler == nil {
+		t.Fatal("handleGlobalKeys not found in handlers.go")
+	}
+
+	handled := 0
+	ast.Inspect(handler.Body, func(n ast.Node) bool {
+		lit, ok := n.(*ast.BasicLit)
+		if !ok || lit.Kind != token.CHAR {
+			return true
+		}
+		r, _, _, err := strconv.UnquoteChar(lit.Value[1:len(lit.Value)-1], '\'')
+		if err != nil {
+			t.Fatalf("unquote %s: %v", lit.Value, err)
+		}
+		handled++
+		if !documented[r] {
+			t.Errorf("key %q is handled by handleGlobalKeys but missing from keyBindings", r)
+		}
+		return true
+	})
+	if handled == 0 {
+		t.Fatal("found no rune keys in handleGlobalKeys")
+	}
+}
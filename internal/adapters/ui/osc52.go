@@ -0,0 +1,63 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ui
+
+import (
+	"encoding/base64"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// screenDCSChunk keeps each GNU screen passthrough under its 768-byte DCS limit.
+const screenDCSChunk = 76
+
+// osc52Sequence returns the OSC 52 escape that asks the terminal to put text on the system
+// clipboard. Inside tmux or GNU screen (told apart by getenv) the sequence is wrapped in a
+// DCS passthrough so it reaches the outer terminal; tmux needs allow-passthrough enabled.
+func osc52Sequence(text string, getenv func(string) string) string {
+	seq := "\x1b]52;c;" + base64.StdEncoding.EncodeToString([]byte(text)) + "\a"
+	switch {
+	case getenv("TMUX") != "":
+		return "\x1bPtmux;" + strings.ReplaceAll(seq, "\x1b", "\x1b\x1b") + "\x1b\\"
+	case getenv("STY") != "" || strings.HasPrefix(getenv("TERM"), "screen"):
+		var b strings.Builder
+		for len(seq) > 0 {
+			n := min(screenDCSChunk, len(seq))
+			b.WriteString("\x1bP" + seq[:n] + "\x1b\\")
+			seq = seq[n:]
+		}
+		return b.String()
+	}
+	return seq
+}
+
+// writeOSC52 sends text to the terminal's clipboard through the controlling terminal,
+// falling back to stdout where there is no /dev/tty. The terminal does not acknowledge
+// the request, so success only means the sequence was written.
+func writeOSC52(text string) error {
+	seq := osc52Sequence(text, os.Getenv)
+	tty, err := os.OpenFile("/dev/tty", os.O_WRONLY, 0)
+	if err != nil {
+		_, err = os.Stdout.WriteString(seq)
+	} else {
+		_, err = tty.WriteString(seq)
+		_ = tty.Close()
+	}
+	if err != nil {
+		return fmt.Errorf("write OSC 52 sequence: %w", err)
+	}
+	return nil
+}
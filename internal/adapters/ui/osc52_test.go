@@ -0,0 +1,55 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ui
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestOSC52Sequence(t *testing.T) {
+	env := func(vars map[string]string) func(string) string {
+		return func(k string) string { return vars[k] }
+	}
+	const plain = "\x1b]52;c;c3NoIHdlYg==\a" // base64("ssh web")
+
+	tests := []struct {
+		name string
+		vars map[string]string
+		want string
+	}{
+		{"plain terminal", map[string]string{"TERM": "xterm-256color"}, plain},
+		{"tmux doubles escapes", map[string]string{"TMUX": "/tmp/tmux-1000/default,1,0", "TERM": "screen-256color"}, "\x1bPtmux;\x1b\x1b]52;c;c3NoIHdlYg==\a\x1b\\"},
+		{"screen", map[string]string{"STY": "1234.pts-0"}, "\x1bP" + plain + "\x1b\\"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := osc52Sequence("ssh web", env(tt.vars)); got != tt.want {
+				t.Errorf("osc52Sequence() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	long := osc52Sequence(strings.Repeat("x", 200), env(map[string]string{"STY": "1"}))
+	chunks := strings.Split(strings.TrimSuffix(long, "\x1b\\"), "\x1b\\")
+	if len(chunks) < 2 {
+		t.Fatalf("long screen sequence has %d chunks, want several", len(chunks))
+	}
+	for _, chunk := range chunks {
+		if payload := strings.TrimPrefix(chunk, "\x1bP"); len(payload) > screenDCSChunk {
+			t.Errorf("screen chunk carries %d bytes, want at most %d", len(payload), screenDCSChunk)
+		}
+	}
+}
@@ -0,0 +1,110 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ui
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/Adembc/lazyssh/internal/core/domain"
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// quickEditField is one field offered by the quick-edit menu. name is its key in
+// GetFieldValidators, so the popup validates exactly like the full form.
+type quickEditField struct {
+	label string
+	name  string
+	get   func(s domain.Server) string
+	set   func(s *domain.Server, value string)
+}
+
+var quickEditFields = []quickEditField{
+	{"Host", "Host",
+		func(s domain.Server) string { return s.Host },
+		func(s *domain.Server, v string) { s.Host = v }},
+	{"User", "User",
+		func(s domain.Server) string { return s.User },
+		func(s *domain.Server, v string) { s.User = v }},
+	{"Port", "Port",
+		func(s domain.Server) string { return strconv.Itoa(s.Port) },
+		func(s *domain.Server, v string) {
+			s.Port = 22
+			if port, err := strconv.Atoi(v); err == nil {
+				s.Port = port
+			}
+		}},
+	{"Keys", "Keys",
+		func(s domain.Server) string { return strings.Join(s.IdentityFiles, ", ") },
+		func(s *domain.Server, v string) { s.IdentityFiles = splitList(v) }},
+	{"ProxyJump", "ProxyJump",
+		func(s domain.Server) string { return s.ProxyJump },
+		func(s *domain.Server, v string) { s.ProxyJump = v }},
+	{"ProxyCommand", "ProxyCommand",
+		func(s domain.Server) string { return s.ProxyCommand },
+		func(s *domain.Server, v string) { s.ProxyCommand = v }},
+	{"RemoteCommand", "RemoteCommand",
+		func(s domain.Server) string { return s.RemoteCommand },
+		func(s *domain.Server, v string) { s.RemoteCommand = v }},
+	{"ConnectTimeout", "ConnectTimeout",
+		func(s domain.Server) string { return s.ConnectTimeout },
+		func(s *domain.Server, v string) { s.ConnectTimeout = v }},
+	{"LocalForward", "LocalForward",
+		func(s domain.Server) string { return strings.Join(s.LocalForward, ", ") },
+		func(s *domain.Server, v string) { s.LocalForward = splitList(v) }},
+}
+
+// applyQuickEdit validates value for f and returns a copy of server with it applied.
+func applyQuickEdit(server domain.Server, f quickEditField, value string) (domain.Server, error) {
+	value = strings.TrimSpace(value)
+	if msg := checkField(f.name, value); msg != "" {
+		return server, errors.New(msg)
+	}
+	updated := server
+	f.set(&updated, value)
+	return updated, nil
+}
+
+// showQuickEdit shows a small menu of common fields over the selected row; picking one
+// edits just that field in the row editor, leaving the rest of the entry untouched.
+func (t *tui) showQuickEdit(server domain.Server) {
+	menu := tview.NewList().
+		ShowSecondaryText(false).
+		SetHighlightFullLine(true).
+		SetSelectedBackgroundColor(tcell.Color24)
+	menu.SetBorder(true).
+		SetTitle(fmt.Sprintf(" Edit %s ", tview.Escape(server.Alias))).
+		SetTitleAlign(tview.AlignLeft).
+		SetBorderColor(tcell.Color24)
+
+	for _, f := range quickEditFields {
+		menu.AddItem(fmt.Sprintf("%-15s [#888888]%s[-]", f.label, tview.Escape(f.get(server))), "", 0, func() {
+			t.showRowEditor(server.Alias, f.label+": ", f.get(server), func(text string) error {
+				updated, err := applyQuickEdit(server, f, text)
+				if err != nil {
+					return err
+				}
+				return t.serverService.UpdateServer(server, updated)
+			}, f.label+" updated")
+		})
+	}
+	menu.SetDoneFunc(t.returnToMain)
+	menu.SetRect(t.rowOverlayRect(rowEditorWidth, len(quickEditFields)+2))
+
+	t.showRowOverlay("quickedit", menu)
+}
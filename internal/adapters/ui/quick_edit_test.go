@@ -0,0 +1,105 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ui
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/Adembc/lazyssh/internal/core/domain"
+)
+
+func quickEditFieldNamed(t *testing.T, label string) quickEditField {
+	t.Helper()
+	for _, f := range quickEditFields {
+		if f.label == label {
+			return f
+		}
+	}
+	t.Fatalf("no quick-edit field %q", label)
+	return quickEditField{}
+}
+
+func TestApplyQuickEdit(t *testing.T) {
+	base := domain.Server{
+		Alias:         "web",
+		Host:          "10.0.0.1",
+		User:          "deploy",
+		Port:          2222,
+		IdentityFiles: []string{"~/.ssh/a"},
+		Tags:          []string{"prod"},
+	}
+	tests := []struct {
+		name    string
+		field   string
+		value   string
+		check   func(s domain.Server) bool
+		wantErr bool
+	}{
+		{"host", "Host", " 10.0.0.2 ", func(s domain.Server) bool { return s.Host == "10.0.0.2" }, false},
+		{"empty host", "Host", "", nil, true},
+		{"port", "Port", "2200", func(s domain.Server) bool { return s.Port == 2200 }, false},
+		{"empty port resets to 22", "Port", "", func(s domain.Server) bool { return s.Port == 22 }, false},
+		{"bad port", "Port", "70000", nil, true},
+		{"bad user", "User", "9user", nil, true},
+		{"proxy jump", "ProxyJump", "bastion", func(s domain.Server) bool { return s.ProxyJump == "bastion" }, false},
+		{"local forwards", "LocalForward", "8080:localhost:80, 5432:db:5432", func(s domain.Server) bool {
+			return reflect.DeepEqual(s.LocalForward, []string{"8080:localhost:80", "5432:db:5432"})
+		}, false},
+		{"bad forward", "LocalForward", "8080", nil, true},
+		{"bad timeout", "ConnectTimeout", "-1", nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := applyQuickEdit(base, quickEditFieldNamed(t, tt.field), tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("applyQuickEdit() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				if !reflect.DeepEqual(got, base) {
+					t.Errorf("applyQuickEdit() changed the server on error: %+v", got)
+				}
+				return
+			}
+			if !tt.check(got) {
+				t.Errorf("applyQuickEdit() = %+v", got)
+			}
+			if got.User != base.User || !reflect.DeepEqual(got.Tags, base.Tags) || !reflect.DeepEqual(got.IdentityFiles, base.IdentityFiles) {
+				t.Errorf("applyQuickEdit() touched other fields: %+v", got)
+			}
+		})
+	}
+}
+
+func TestQuickEditFieldsRoundTrip(t *testing.T) {
+	s := domain.Server{
+		Host:           "h",
+		User:           "u",
+		Port:           2222,
+		IdentityFiles:  []string{"~/.ssh/a", "~/.ssh/b"},
+		ProxyJump:      "j",
+		ProxyCommand:   "nc %h %p",
+		RemoteCommand:  "tmux a",
+		ConnectTimeout: "10",
+		LocalForward:   []string{"8080:localhost:80"},
+	}
+	for _, f := range quickEditFields {
+		var got domain.Server
+		f.set(&got, f.get(s))
+		if f.get(got) != f.get(s) {
+			t.Errorf("%s: get(set(%q)) = %q", f.label, f.get(s), f.get(got))
+		}
+	}
+}
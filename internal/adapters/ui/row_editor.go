@@ -0,0 +1,202 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Adembc/lazyssh/internal/core/domain"
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+const (
+	rowEditorWidth  = 50
+	rowEditorHeight = 3
+)
+
+// showTagEditor edits the selected server's tags in the row editor. Tab switches to
+// showTagOrder to reorder the tags typed so far.
+func (t *tui) showTagEditor(server domain.Server) {
+	t.editTags(server, strings.Join(server.Tags, ", "))
+}
+
+func (t *tui) editTags(server domain.Server, text string) {
+	field := t.showRowEditor(server.Alias, "Tags: ", text, func(text string) error {
+		newServer := server
+		newServer.Tags = splitList(text)
+		return t.serverService.UpdateServer(server, newServer)
+	}, "Tags updated")
+	field.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Key() != tcell.KeyTab {
+			return event
+		}
+		if tags := splitList(field.GetText()); len(tags) > 1 {
+			t.showTagOrder(server, tags)
+		}
+		return nil
+	})
+}
+
+// showTagOrder lists tags over the selected row so they can be moved with Ctrl+Up/Down, like
+// entries in the server list. The list view shows the first tags, so order matters. Enter
+// saves the order, Tab goes back to editing the text and Esc cancels.
+func (t *tui) showTagOrder(server domain.Server, tags []string) {
+	list := tview.NewList().
+		ShowSecondaryText(false).
+		SetHighlightFullLine(true).
+		SetSelectedBackgroundColor(tcell.Color24)
+	list.SetBorder(true).
+		SetTitle(" Ctrl+↑/↓ move, Enter save, Tab edit ").
+		SetTitleAlign(tview.AlignLeft).
+		SetBorderColor(tcell.Color24)
+	fill := func(current int) {
+		list.Clear()
+		for _, tag := range tags {
+			list.AddItem(t.display.renderTagChip(tag), "", 0, nil)
+		}
+		list.SetCurrentItem(current)
+	}
+	fill(0)
+
+	list.SetSelectedFunc(func(int, string, string, rune) {
+		newServer := server
+		newServer.Tags = tags
+		if err := t.serverService.UpdateServer(server, newServer); err != nil {
+			t.returnToMain()
+			t.showStatusTempColor(fmt.Sprintf("Update tags failed: %v", err), "#FF6B6B")
+			return
+		}
+		t.refreshServerList()
+		t.returnToMain()
+		t.showStatusTemp("Tags updated")
+	})
+	list.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch {
+		case event.Modifiers()&tcell.ModCtrl != 0 && event.Key() == tcell.KeyUp:
+			fill(moveTag(tags, list.GetCurrentItem(), -1))
+			return nil
+		case event.Modifiers()&tcell.ModCtrl != 0 && event.Key() == tcell.KeyDown:
+			fill(moveTag(tags, list.GetCurrentItem(), 1))
+			return nil
+		case event.Key() == tcell.KeyTab:
+			t.editTags(server, strings.Join(tags, ", "))
+			return nil
+		case event.Key() == tcell.KeyEscape:
+			t.returnToMain()
+			return nil
+		case event.Rune() == 'j':
+			return tcell.NewEventKey(tcell.KeyDown, 0, tcell.ModNone)
+		case event.Rune() == 'k':
+			return tcell.NewEventKey(tcell.KeyUp, 0, tcell.ModNone)
+		}
+		return event
+	})
+	list.SetRect(t.rowOverlayRect(rowEditorWidth, len(tags)+2))
+
+	t.showRowOverlay("tagorder", list)
+}
+
+// moveTag swaps the tag at i with its neighbour delta (-1 or 1) away and returns the tag's
+// new index; a move past either end leaves tags unchanged.
+func moveTag(tags []string, i, delta int) int {
+	j := i + delta
+	if i < 0 || i >= len(tags) || j < 0 || j >= len(tags) {
+		return i
+	}
+	tags[i], tags[j] = tags[j], tags[i]
+	return j
+}
+
+// showRowEditor edits one value in a small bordered field drawn over the selected list row,
+// so the list and search stay in view. Enter calls save: on success the list is refreshed
+// (keeping cursor and search) and done is shown; an error is shown in the editor's title and
+// the editor stays open. Esc cancels. The field is returned so callers can add keys.
+func (t *tui) showRowEditor(alias, label, value string, save func(text string) error, done string) *tview.InputField {
+	title := fmt.Sprintf(" %s — Enter save, Esc cancel ", tview.Escape(alias))
+	field := tview.NewInputField().
+		SetLabel(label).
+		SetText(value).
+		SetFieldBackgroundColor(tcell.Color236)
+	field.SetBorder(true).
+		SetTitle(title).
+		SetTitleAlign(tview.AlignLeft).
+		SetBorderColor(tcell.Color24)
+	field.SetRect(t.rowOverlayRect(rowEditorWidth, rowEditorHeight))
+
+	field.SetChangedFunc(func(string) { field.SetTitle(title) })
+	field.SetDoneFunc(func(key tcell.Key) {
+		switch key {
+		case tcell.KeyEnter:
+			if err := save(field.GetText()); err != nil {
+				field.SetTitle(fmt.Sprintf(" [#FF6B6B]%s[-] ", tview.Escape(err.Error())))
+				return
+			}
+			t.refreshServerList()
+			t.returnToMain()
+			t.showStatusTemp(done)
+		case tcell.KeyEscape:
+			t.returnToMain()
+		}
+	})
+
+	t.showRowOverlay("editor", field)
+	return field
+}
+
+// showRowOverlay shows p over the main screen. It keeps its own rect (resize=false) so it
+// sits on the selected row instead of filling the screen.
+func (t *tui) showRowOverlay(name string, p tview.Primitive) {
+	t.app.SetRoot(tview.NewPages().
+		AddPage("main", t.root, true, true).
+		AddPage(name, p, false, true), true)
+	t.app.SetFocus(p)
+}
+
+// rowOverlayRect returns the rect for a width x height overlay on the selected list row.
+func (t *tui) rowOverlayRect(width, height int) (int, int, int, int) {
+	listX, listY, listW, listH := t.serverList.GetInnerRect()
+	offset, _ := t.serverList.GetOffset()
+	row := listY + t.serverList.GetCurrentItem() - offset
+	return overlayRect(listX, listY, listW, listH, row, width, height)
+}
+
+// overlayRect places a boxWidth x boxHeight overlay over the list row at screen line row,
+// inside the list's inner area (x, y, width, height). The box starts one line above the row
+// so its first inner line covers the row, and is shifted to stay within the list.
+func overlayRect(x, y, width, height, row, boxWidth, boxHeight int) (int, int, int, int) {
+	w := min(boxWidth, width)
+	h := min(boxHeight, height)
+	top := row - 1
+	if top+h > y+height {
+		top = y + height - h
+	}
+	if top < y {
+		top = y
+	}
+	return x, top, w, h
+}
+
+// splitList splits comma-separated input, dropping blanks and surrounding spaces.
+func splitList(text string) []string {
+	var items []string
+	for _, part := range strings.Split(text, ",") {
+		if s := strings.TrimSpace(part); s != "" {
+			items = append(items, s)
+		}
+	}
+	return items
+}
@@ -0,0 +1,84 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ui
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestOverlayRect(t *testing.T) {
+	tests := []struct {
+		name          string
+		width, height int
+		row           int
+		boxHeight     int
+		wantY, wantW  int
+		wantH         int
+	}{
+		{"middle row", 80, 20, 10, rowEditorHeight, 9, rowEditorWidth, rowEditorHeight},
+		{"first row stays inside", 80, 20, 1, rowEditorHeight, 1, rowEditorWidth, rowEditorHeight},
+		{"last row shifts up", 80, 20, 20, rowEditorHeight, 18, rowEditorWidth, rowEditorHeight},
+		{"narrow list", 30, 20, 5, rowEditorHeight, 4, 30, rowEditorHeight},
+		{"tall menu shifts up", 80, 20, 15, 10, 11, rowEditorWidth, 10},
+		{"menu taller than list", 80, 6, 3, 10, 1, rowEditorWidth, 6},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			x, y, w, h := overlayRect(2, 1, tt.width, tt.height, tt.row, rowEditorWidth, tt.boxHeight)
+			if x != 2 || y != tt.wantY || w != tt.wantW || h != tt.wantH {
+				t.Errorf("overlayRect() = (%d, %d, %d, %d), want (2, %d, %d, %d)", x, y, w, h, tt.wantY, tt.wantW, tt.wantH)
+			}
+		})
+	}
+}
+
+func TestSplitList(t *testing.T) {
+	tests := []struct {
+		in   string
+		want []string
+	}{
+		{"", nil},
+		{" , ,", nil},
+		{"prod, web ,db", []string{"prod", "web", "db"}},
+	}
+	for _, tt := range tests {
+		if got := splitList(tt.in); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("splitList(%q) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestMoveTag(t *testing.T) {
+	tests := []struct {
+		name      string
+		i, delta  int
+		want      []string
+		wantIndex int
+	}{
+		{"up", 1, -1, []string{"b", "a", "c"}, 0},
+		{"down", 1, 1, []string{"a", "c", "b"}, 2},
+		{"past the top", 0, -1, []string{"a", "b", "c"}, 0},
+		{"past the bottom", 2, 1, []string{"a", "b", "c"}, 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tags := []string{"a", "b", "c"}
+			if got := moveTag(tags, tt.i, tt.delta); got != tt.wantIndex || !reflect.DeepEqual(tags, tt.want) {
+				t.Errorf("moveTag(%d, %d) = %d, %v; want %d, %v", tt.i, tt.delta, got, tags, tt.wantIndex, tt.want)
+			}
+		})
+	}
+}
@@ -21,20 +21,22 @@ import (
 
 type SearchBar struct {
 	*tview.InputField
+	display  displaySettings
 	onSearch func(string)
 	onEscape func()
 }
 
-func NewSearchBar() *SearchBar {
+func NewSearchBar(display displaySettings) *SearchBar {
 	search := &SearchBar{
 		InputField: tview.NewInputField(),
+		display:    display,
 	}
 	search.build()
 	return search
 }
 
 func (s *SearchBar) build() {
-	s.InputField.SetLabel(" 🔍 Search: ").
+	s.InputField.SetLabel(" " + s.display.glyph("🔍 ", "") + "Search: ").
 		SetFieldBackgroundColor(tcell.Color233).
 		SetFieldTextColor(tcell.Color252).
 		SetFieldWidth(30).
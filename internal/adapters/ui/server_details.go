@@ -16,7 +16,9 @@ package ui
 
 import (
 	"fmt"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/Adembc/lazyssh/internal/core/domain"
 	"github.com/gdamore/tcell/v2"
@@ -25,11 +27,35 @@ import (
 
 type ServerDetails struct {
 	*tview.TextView
+	display displaySettings
+	wrap    bool
+	alias   string // server currently shown; the scroll position resets when it changes
+	// muxStatus holds the last multiplexing check result per alias.
+	muxStatus map[string]string
+	// pings holds the last ping result per alias for the latency line.
+	pings map[string]domain.PingResult
+	// pingHistory holds the last pingHistorySize results per alias, oldest first, for the
+	// sparkline. It is kept in memory only.
+	pingHistory map[string][]domain.PingResult
+	// crypto holds the effective algorithms per alias, once looked up with K.
+	crypto map[string]domain.CryptoSettings
+	// agentKeys records per identity file whether its key is loaded in ssh-agent; files
+	// missing from the map have not been checked (or could not be fingerprinted).
+	agentKeys map[string]bool
+	// agentDown is set when the last agent check found no ssh-agent.
+	agentDown bool
 }
 
-func NewServerDetails() *ServerDetails {
+func NewServerDetails(display displaySettings) *ServerDetails {
 	details := &ServerDetails{
-		TextView: tview.NewTextView(),
+		TextView:    tview.NewTextView(),
+		display:     display,
+		wrap:        true,
+		muxStatus:   make(map[string]string),
+		pings:       make(map[string]domain.PingResult),
+		pingHistory: make(map[string][]domain.PingResult),
+		crypto:      make(map[string]domain.CryptoSettings),
+		agentKeys:   make(map[string]bool),
 	}
 	details.build()
 	return details
@@ -37,7 +63,7 @@ func NewServerDetails() *ServerDetails {
 
 func (sd *ServerDetails) build() {
 	sd.TextView.SetDynamicColors(true).
-		SetWrap(true).
+		SetWrap(sd.wrap).
 		SetBorder(true).
 		SetTitle(" Details ").
 		SetTitleAlign(tview.AlignCenter).
@@ -45,30 +71,94 @@ func (sd *ServerDetails) build() {
 		SetTitleColor(tcell.Color250)
 }
 
+// ToggleWrap switches word-wrap on or off and returns the new state. With wrap off,
+// long lines can be scrolled horizontally once the pane is focused.
+func (sd *ServerDetails) ToggleWrap() bool {
+	sd.wrap = !sd.wrap
+	sd.TextView.SetWrap(sd.wrap)
+	return sd.wrap
+}
+
+// SetFocused highlights the border while the pane has keyboard focus for scrolling.
+// SetMultiplexStatus records the result of a control socket check, shown in the
+// Multiplexing section until the next check for the same alias.
+func (sd *ServerDetails) SetMultiplexStatus(alias, status string) {
+	sd.muxStatus[alias] = status
+}
+
+// SetCrypto records the effective algorithms for alias, shown in the Crypto section.
+func (sd *ServerDetails) SetCrypto(alias string, c domain.CryptoSettings) {
+	sd.crypto[alias] = c
+}
+
+// SetAgentKey records whether the key at identityFile is loaded in ssh-agent.
+func (sd *ServerDetails) SetAgentKey(identityFile string, loaded bool) {
+	sd.agentKeys[identityFile] = loaded
+	sd.agentDown = false
+}
+
+// SetAgentDown records that no ssh-agent could be reached.
+func (sd *ServerDetails) SetAgentDown() {
+	sd.agentDown = true
+}
+
+// SetPing records a ping result for alias, shown as the latency line and added to the
+// alias's ping history. A cached result handed out again is not added twice.
+func (sd *ServerDetails) SetPing(alias string, res domain.PingResult) {
+	sd.pings[alias] = res
+	history := sd.pingHistory[alias]
+	if n := len(history); n > 0 && history[n-1].CheckedAt.Equal(res.CheckedAt) {
+		return
+	}
+	history = append(history, res)
+	if len(history) > pingHistorySize {
+		history = history[len(history)-pingHistorySize:]
+	}
+	sd.pingHistory[alias] = history
+}
+
+// Ping returns the last ping result recorded for alias.
+func (sd *ServerDetails) Ping(alias string) (domain.PingResult, bool) {
+	res, ok := sd.pings[alias]
+	return res, ok
+}
+
+func (sd *ServerDetails) SetFocused(focused bool) {
+	if focused {
+		sd.TextView.SetBorderColor(tcell.Color33).SetTitle(" Details — ↑/↓ scroll • w wrap • Tab/Esc back ")
+		return
+	}
+	sd.TextView.SetBorderColor(tcell.Color238).SetTitle(" Details ")
+}
+
 // renderTagChips builds colored tag chips for details view.
-func renderTagChips(tags []string) string {
+func (sd *ServerDetails) renderTagChips(tags []string) string {
 	if len(tags) == 0 {
 		return ""
 	}
 	chips := make([]string, 0, len(tags))
 	for _, t := range tags {
-		chips = append(chips, fmt.Sprintf("[black:#5FAFFF] %s [-:-:-]", t))
+		chips = append(chips, sd.display.renderTagChip(t))
 	}
 	return strings.Join(chips, " ")
 }
 
 func (sd *ServerDetails) UpdateServer(server domain.Server) {
-	lastSeen := server.LastSeen.Format("2006-01-02 15:04:05")
-	if server.LastSeen.IsZero() {
-		lastSeen = "Never"
+	lastSeen := "Never"
+	if !server.LastSeen.IsZero() {
+		lastSeen = fmt.Sprintf("[white]%s[-] [#888888](%s)[-]",
+			humanizeDuration(server.LastSeen), server.LastSeen.Local().Format("Mon 2006-01-02 15:04:05 MST"))
+	}
+	serverKey := sd.renderIdentityFiles(server.IdentityFiles)
+	if serverKey == "" && sd.display.defaultIdentityFile != "" {
+		serverKey = fmt.Sprintf("%s [#888888](default)[-]", sd.display.defaultIdentityFile)
 	}
-	serverKey := strings.Join(server.IdentityFiles, ", ")
 
 	pinnedStr := "true"
 	if server.PinnedAt.IsZero() {
 		pinnedStr = "false"
 	}
-	tagsText := renderTagChips(server.Tags)
+	tagsText := sd.renderTagChips(server.Tags)
 
 	// Basic information
 	aliasText := strings.Join(server.Aliases, ", ")
@@ -76,6 +166,9 @@ func (sd *ServerDetails) UpdateServer(server domain.Server) {
 	userText := server.User
 
 	hostText := server.Host
+	if server.Host == "" {
+		hostText = "[#888888](uses alias)[-]"
+	}
 
 	portText := fmt.Sprintf("%d", server.Port)
 	if server.Port == 0 {
@@ -88,6 +181,50 @@ func (sd *ServerDetails) UpdateServer(server domain.Server) {
 		serverKey, tagsText, pinnedStr,
 		lastSeen, server.SSHCount)
 
+	if server.ReadOnly {
+		text += "  Source: [white]remote inventory[-] [#888888](read-only)[-]\n"
+	}
+
+	res, pinged := sd.pings[server.Alias]
+	text += fmt.Sprintf("  Latency: %s\n", renderLatency(res, pinged, server.RequiresVPN))
+	if history := sd.pingHistory[server.Alias]; len(history) > 1 {
+		text += fmt.Sprintf("  History: %s\n", sd.display.renderSparkline(history))
+	}
+	if server.RequiresVPN {
+		text += "  Requires VPN: [white]yes[-]\n"
+	}
+	if server.PingTarget != "" {
+		text += fmt.Sprintf("  Ping target: [white]%s[-]\n", tview.Escape(server.PingTarget))
+	}
+
+	if u := server.URL(); u != "" {
+		text += fmt.Sprintf("  URL: [#55AAFF::u]%s[-:-:-]\n", tview.Escape(u))
+	}
+
+	if server.SessionLogging != nil {
+		state := "off"
+		if *server.SessionLogging {
+			state = "on"
+		}
+		text += fmt.Sprintf("  Session log: [white]%s[-] [#888888](per-server)[-]\n", state)
+	}
+
+	if len(server.AutoTunnels) > 0 {
+		state := ""
+		if server.AutoTunnelsDisabled {
+			state = " [#888888](disabled)[-]"
+		}
+		text += fmt.Sprintf("  Auto tunnels: [white]%s[-]%s\n", tview.Escape(strings.Join(server.AutoTunnels, ", ")), state)
+	}
+
+	if server.LastError != "" {
+		when := ""
+		if !server.LastErrorAt.IsZero() {
+			when = " [#888888](" + server.LastErrorAt.Format("2006-01-02 15:04:05") + ")[-]"
+		}
+		text += fmt.Sprintf("  Last error: [#FF6B6B]%s[-]%s\n", tview.Escape(server.LastError), when)
+	}
+
 	// Advanced settings section (only show non-empty fields)
 	// Organized by logical grouping for better readability
 	type fieldEntry struct {
@@ -127,9 +264,6 @@ func (sd *ServerDetails) UpdateServer(server domain.Server) {
 				{"Compression", server.Compression},
 				{"TCPKeepAlive", server.TCPKeepAlive},
 				{"BatchMode", server.BatchMode},
-				{"ControlMaster", server.ControlMaster},
-				{"ControlPath", server.ControlPath},
-				{"ControlPersist", server.ControlPersist},
 			},
 		},
 		{
@@ -203,7 +337,19 @@ func (sd *ServerDetails) UpdateServer(server domain.Server) {
 		for _, field := range group.fields {
 			if field.value != "" {
 				hasAdvanced = true
+				if insecureSetting(field.name, field.value) {
+					advancedText += fmt.Sprintf("  %s: [#FF6B6B]%s %saccepts any host key[-]\n", field.name, field.value, sd.display.glyph("⚠ ", "! "))
+					continue
+				}
 				advancedText += fmt.Sprintf("  %s: [white]%s[-]\n", field.name, field.value)
+				if field.name == "ProxyCommand" {
+					if proxyCommandShadowed(server) {
+						advancedText += "    [#FFD75F]" + sd.display.glyph("⚠ ", "! ") + "ProxyJump is also set: ssh uses whichever comes first in the config, copied commands use ProxyJump[-]\n"
+					}
+					if expanded := expandProxyTokens(field.value, server); expanded != field.value {
+						advancedText += fmt.Sprintf("    [#888888]runs: %s[-]\n", tview.Escape(expanded))
+					}
+				}
 			}
 		}
 	}
@@ -212,12 +358,228 @@ func (sd *ServerDetails) UpdateServer(server domain.Server) {
 		text += advancedText
 	}
 
+	text += renderMultiplexing(server, sd.muxStatus[server.Alias])
+	if c, ok := sd.crypto[server.Alias]; ok {
+		text += sd.display.renderCrypto(c)
+	}
+
 	// Commands list
-	text += "\n[::b]Commands:[-]\n  Enter: SSH connect\n  c: Copy SSH command\n  g: Ping server\n  r: Refresh list\n  a: Add new server\n  e: Edit entry\n  t: Edit tags\n  d: Delete entry\n  p: Pin/Unpin"
+	text += "\n[::b]Commands:[-]\n" + renderKeyBindings(true, sd.display.enterAction)
 
 	sd.TextView.SetText(text)
+	if server.Alias != sd.alias {
+		sd.alias = server.Alias
+		sd.TextView.ScrollToBeginning()
+	}
+}
+
+// Latency bucket boundaries for renderLatency.
+const (
+	fastLatency = 50 * time.Millisecond
+	okLatency   = 200 * time.Millisecond
+)
+
+// renderLatency formats a ping result colored by latency bucket: green below 50ms, yellow
+// below 200ms, red above that or when the host is down, and grey when it was never pinged.
+// An unreachable host that requires a VPN shows an amber "VPN?" instead of "down".
+func renderLatency(res domain.PingResult, ok, requiresVPN bool) string {
+	if !ok {
+		return "[#888888]unknown (g to ping)[-]"
+	}
+	via := ""
+	if res.Via != "" {
+		via = " via " + tview.Escape(res.Via)
+	}
+	checked := fmt.Sprintf(" [#888888](%s%s)[-]", humanizeDuration(res.CheckedAt), via)
+	if !res.Up {
+		if requiresVPN {
+			return "[#FFD75F]VPN?[-]" + checked
+		}
+		return "[#FF6B6B]down[-]" + checked
+	}
+	color := "#FF6B6B"
+	switch {
+	case res.Latency < fastLatency:
+		color = "#A0FFA0"
+	case res.Latency < okLatency:
+		color = "#FFD75F"
+	}
+	return fmt.Sprintf("[%s]%s[-]%s", color, res.Latency.Round(time.Millisecond), checked)
+}
+
+// pingHistorySize is how many recent ping results the sparkline shows per alias.
+const pingHistorySize = 20
+
+// renderSparkline draws one block per ping result, oldest first, scaled between the lowest and
+// highest latency in the history; failed pings show as a red cross. The range follows the bars.
+func (d displaySettings) renderSparkline(history []domain.PingResult) string {
+	bars := []rune(d.glyph("▁▂▃▄▅▆▇█", "_.-=+*#@"))
+	var lo, hi time.Duration
+	first := true
+	for _, res := range history {
+		if !res.Up {
+			continue
+		}
+		if first || res.Latency < lo {
+			lo = res.Latency
+		}
+		if first || res.Latency > hi {
+			hi = res.Latency
+		}
+		first = false
+	}
+
+	var b strings.Builder
+	b.WriteString("[#55AAFF]")
+	for _, res := range history {
+		if !res.Up {
+			b.WriteString("[#FF6B6B]" + d.glyph("×", "x") + "[#55AAFF]")
+			continue
+		}
+		level := 0
+		if hi > lo {
+			level = int((res.Latency - lo) * time.Duration(len(bars)-1) / (hi - lo))
+		}
+		b.WriteRune(bars[level])
+	}
+	b.WriteString("[-]")
+	if !first {
+		fmt.Fprintf(&b, " [#888888]%s%s%s[-]", lo.Round(time.Millisecond), d.glyph("–", "-"), hi.Round(time.Millisecond))
+	}
+	return b.String()
+}
+
+// renderMultiplexing renders the connection multiplexing settings and the last control socket
+// check, or nothing when neither is present.
+func renderMultiplexing(server domain.Server, status string) string {
+	fields := []struct{ name, value string }{
+		{"ControlMaster", server.ControlMaster},
+		{"ControlPath", server.ControlPath},
+		{"ControlPersist", server.ControlPersist},
+	}
+	var b strings.Builder
+	for _, f := range fields {
+		if f.value != "" {
+			fmt.Fprintf(&b, "  %s: [white]%s[-]\n", f.name, tview.Escape(f.value))
+		}
+	}
+	if status != "" {
+		fmt.Fprintf(&b, "  Status: %s\n", status)
+	}
+	if b.Len() == 0 {
+		return ""
+	}
+	return "\n[::b]Multiplexing:[-]\n" + b.String()
+}
+
+// renderCrypto lists the algorithms ssh would offer, deprecated ones in red.
+func (d displaySettings) renderCrypto(c domain.CryptoSettings) string {
+	lists := []struct {
+		name string
+		algs []domain.CryptoAlgorithm
+	}{
+		{"Ciphers", c.Ciphers},
+		{"MACs", c.MACs},
+		{"KexAlgorithms", c.KexAlgorithms},
+		{"HostKeyAlgorithms", c.HostKeyAlgorithms},
+	}
+	var b strings.Builder
+	b.WriteString("\n[::b]Effective crypto:[-]\n")
+	for _, l := range lists {
+		names := make([]string, 0, len(l.algs))
+		for _, a := range l.algs {
+			if a.Deprecated {
+				names = append(names, "[#FF6B6B]"+tview.Escape(a.Name)+d.glyph(" ⚠", " !")+"[-]")
+				continue
+			}
+			names = append(names, tview.Escape(a.Name))
+		}
+		fmt.Fprintf(&b, "  %s: [white]%s[-]\n", l.name, strings.Join(names, ", "))
+	}
+	if n := c.DeprecatedCount(); n > 0 {
+		fmt.Fprintf(&b, "  [#FF6B6B]%d deprecated algorithm(s) offered[-]\n", n)
+	}
+	return b.String()
 }
 
 func (sd *ServerDetails) ShowEmpty() {
+	sd.alias = ""
 	sd.TextView.SetText("No servers match the current filter.")
 }
+
+// insecureSetting reports whether an advanced setting turns off host key verification,
+// so the details pane can make it stand out.
+func insecureSetting(name, value string) bool {
+	if name != "StrictHostKeyChecking" {
+		return false
+	}
+	return strings.EqualFold(value, "no") || strings.EqualFold(value, "off")
+}
+
+// expandProxyTokens substitutes the ssh_config tokens in a ProxyCommand the way ssh does
+// (%h host, %p port, %r user, %n alias, %% a literal %), for display only: the config keeps
+// the literal command. %r stays literal when no User is set, since ssh then uses the local
+// user name, and unknown tokens are left as they are.
+func expandProxyTokens(command string, server domain.Server) string {
+	host := server.Host
+	if host == "" {
+		host = server.Alias
+	}
+	port := server.Port
+	if port == 0 {
+		port = 22
+	}
+
+	var b strings.Builder
+	for i := 0; i < len(command); i++ {
+		if command[i] != '%' || i+1 == len(command) {
+			b.WriteByte(command[i])
+			continue
+		}
+		switch command[i+1] {
+		case 'h':
+			b.WriteString(host)
+		case 'p':
+			b.WriteString(strconv.Itoa(port))
+		case 'r':
+			if server.User == "" {
+				b.WriteString("%r")
+			} else {
+				b.WriteString(server.User)
+			}
+		case 'n':
+			b.WriteString(server.Alias)
+		case '%':
+			b.WriteByte('%')
+		default:
+			b.WriteString(command[i : i+2])
+		}
+		i++
+	}
+	return b.String()
+}
+
+// renderIdentityFiles lists the server's keys, each marked with whether it is loaded in
+// ssh-agent once that has been checked.
+func (sd *ServerDetails) renderIdentityFiles(files []string) string {
+	if len(files) == 0 {
+		return ""
+	}
+	parts := make([]string, 0, len(files))
+	for _, f := range files {
+		part := tview.Escape(f)
+		if loaded, ok := sd.agentKeys[f]; ok && !sd.agentDown {
+			if loaded {
+				part += " [#A0FFA0]" + sd.display.glyph("✓", "+") + " in agent[white]"
+			} else {
+				part += " [#888888]not in agent[white]"
+			}
+		}
+		parts = append(parts, part)
+	}
+	text := strings.Join(parts, ", ")
+	if sd.agentDown {
+		text += " [#888888](no ssh-agent)[white]"
+	}
+	return text
+}
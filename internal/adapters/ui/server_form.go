@@ -65,9 +65,11 @@ type ServerForm struct {
 	helpMode      HelpDisplayMode    // Current help display mode
 	currentField  string             // Currently focused field
 	mainContainer *tview.Flex        // Container for form and help panel
+	splitHost     func()             // Splits user@host:port in the Host field; nil when editing
+	display       displaySettings    // Display settings for the header and the default key
 }
 
-func NewServerForm(mode ServerFormMode, original *domain.Server) *ServerForm {
+func NewServerForm(mode ServerFormMode, original *domain.Server, display displaySettings) *ServerForm {
 	// Create help panel
 	helpPanel := tview.NewTextView().
 		SetDynamicColors(true).
@@ -89,6 +91,7 @@ func NewServerForm(mode ServerFormMode, original *domain.Server) *ServerForm {
 		forms:         make(map[string]*tview.Form),
 		mode:          mode,
 		original:      original,
+		display:       display,
 		validation:    NewValidationState(),
 		helpPanel:     helpPanel,
 		helpMode:      HelpModeNormal, // Show help panel by default
@@ -115,7 +118,7 @@ func NewServerForm(mode ServerFormMode, original *domain.Server) *ServerForm {
 
 func (sf *ServerForm) build() {
 	// Create header
-	sf.header = NewAppHeader(sf.version, sf.commit, RepoURL)
+	sf.header = NewAppHeader(sf.display, sf.version, sf.commit, RepoURL)
 
 	// Create forms for each tab
 	sf.createBasicForm()
@@ -643,13 +646,23 @@ func parseOptionValue(option string) string {
 	return option
 }
 
-// findOptionIndex finds the index of a value in options slice
-func (sf *ServerForm) findOptionIndex(options []string, value string) int {
+// currentOption returns the dropdown options and the index to preselect for value. A value the
+// list does not offer (set by hand in the SSH config, or an interface missing on this machine)
+// is appended as its own option, so saving the form keeps it instead of resetting it to the default.
+func (sf *ServerForm) currentOption(options []string, value string) ([]string, int) {
+	if i, ok := lookupOption(options, value); ok || value == "" {
+		return options, i
+	}
+	return append(options, value), len(options)
+}
+
+// lookupOption finds value in options; ok is false, with index 0, when no option matches.
+func lookupOption(options []string, value string) (int, bool) {
 	// Empty value should match "default [gray](...)[-]" or "default (...)" option
 	if value == "" {
 		for i, opt := range options {
 			if strings.HasPrefix(opt, "default [gray](") || strings.HasPrefix(opt, "default (") {
-				return i
+				return i, true
 			}
 		}
 	}
@@ -657,7 +670,7 @@ func (sf *ServerForm) findOptionIndex(options []string, value string) int {
 	// Look for exact match first
 	for i, opt := range options {
 		if strings.EqualFold(opt, value) {
-			return i
+			return i, true
 		}
 	}
 
@@ -667,12 +680,12 @@ func (sf *ServerForm) findOptionIndex(options []string, value string) int {
 		if spaceIdx := strings.Index(opt, " "); spaceIdx > 0 {
 			baseOpt := opt[:spaceIdx]
 			if strings.EqualFold(baseOpt, value) {
-				return i
+				return i, true
 			}
 		}
 	}
 
-	return 0 // Default to first option
+	return 0, false // Default to first option
 }
 
 // matchesSequence checks if all characters in pattern appear in sequence within text
@@ -906,43 +919,41 @@ func (sf *ServerForm) createAlgorithmAutocomplete(suggestions []string) func(str
 
 // validateField validates a single field and updates the validation state
 func (sf *ServerForm) validateField(fieldName, value string) string {
-	fieldValidators := GetFieldValidators()
-	validator, exists := fieldValidators[fieldName]
+	err := checkField(fieldName, value)
+	sf.validation.SetError(fieldName, err)
+	return err
+}
+
+// checkField validates a field value against its rule in GetFieldValidators and returns
+// the error message, or "" when the value is valid or the field has no rule.
+func checkField(fieldName, value string) string {
+	validator, exists := GetFieldValidators()[fieldName]
 	if !exists {
-		// No validator for this field, it's valid
-		sf.validation.SetError(fieldName, "")
 		return ""
 	}
 
 	// Check required
 	if validator.Required && strings.TrimSpace(value) == "" {
-		err := fmt.Sprintf("%s is required", fieldName)
-		sf.validation.SetError(fieldName, err)
-		return err
+		return fmt.Sprintf("%s is required", fieldName)
 	}
 
 	// If field is empty and not required, it's valid
 	if value == "" {
-		sf.validation.SetError(fieldName, "")
 		return ""
 	}
 
 	// Check custom validation function
 	if validator.Validate != nil {
 		if err := validator.Validate(value); err != nil {
-			sf.validation.SetError(fieldName, err.Error())
 			return err.Error()
 		}
 	}
 
 	// Check regex pattern
 	if validator.Pattern != nil && !validator.Pattern.MatchString(value) {
-		sf.validation.SetError(fieldName, validator.Message)
 		return validator.Message
 	}
 
-	// Field is valid
-	sf.validation.SetError(fieldName, "")
 	return ""
 }
 
@@ -997,13 +1008,7 @@ func (sf *ServerForm) addValidatedInputField(form *tview.Form, label, fieldName,
 
 	// Add change handler for real-time validation
 	field.SetChangedFunc(func(text string) {
-		if err := sf.validateField(fieldName, text); err != "" {
-			// Show error in the label with red color
-			field.SetLabel(fmt.Sprintf("[red]%s[-]", originalLabel))
-		} else {
-			// Clear error indication, restore original label
-			field.SetLabel(originalLabel)
-		}
+		sf.markFieldValidity(field, originalLabel, fieldName, text)
 	})
 
 	// Add focus handler to show help
@@ -1020,6 +1025,43 @@ func (sf *ServerForm) addValidatedInputField(form *tview.Form, label, fieldName,
 	return field
 }
 
+// markFieldValidity validates text and colors the field's label red while it is invalid.
+func (sf *ServerForm) markFieldValidity(field *tview.InputField, label, fieldName, text string) {
+	if err := sf.validateField(fieldName, text); err != "" {
+		field.SetLabel(fmt.Sprintf("[red]%s[-]", label))
+		return
+	}
+	field.SetLabel(label)
+}
+
+// splitHostShorthand makes the Host field accept user@host:port: when the field is left, or
+// the form saved, the user and port move to their own fields and only the host stays. Those
+// fields can still be edited afterwards. Typing is not split on every keystroke, otherwise
+// "host:2" would be split before the rest of the port is entered; until then only the host
+// part is validated. The split hooks onto blur because tview's Form replaces every item's
+// finished func when it takes focus.
+func (sf *ServerForm) splitHostShorthand(hostField, userField, portField *tview.InputField) {
+	const hostLabel = "Host/IP:"
+	sf.splitHost = func() {
+		user, host, port := parseUserHostPort(hostField.GetText())
+		if user == "" && port == 0 {
+			return
+		}
+		if user != "" {
+			userField.SetText(user)
+		}
+		if port > 0 {
+			portField.SetText(strconv.Itoa(port))
+		}
+		hostField.SetText(host)
+	}
+	hostField.SetChangedFunc(func(text string) {
+		_, host, _ := parseUserHostPort(text)
+		sf.markFieldValidity(hostField, hostLabel, "Host", host)
+	})
+	hostField.SetBlurFunc(sf.splitHost)
+}
+
 // validateAllFields validates all fields in the current form
 func (sf *ServerForm) validateAllFields() bool {
 	// Clear all previous errors first
@@ -1035,6 +1077,7 @@ func (sf *ServerForm) validateAllFields() bool {
 	sf.validateField("User", data.User)
 	sf.validateField("Keys", data.Key)
 	sf.validateField("Tags", data.Tags)
+	sf.validateField("PingTarget", data.PingTarget)
 
 	// Connection fields
 	sf.validateField("ConnectTimeout", data.ConnectTimeout)
@@ -1062,9 +1105,10 @@ func (sf *ServerForm) validateAllFields() bool {
 	return !sf.validation.HasErrors()
 }
 
-// getDefaultValues returns default form values based on mode
+// getDefaultValues returns default form values based on mode. In add mode, a non-nil
+// original pre-fills the form (e.g. from a pasted ssh command).
 func (sf *ServerForm) getDefaultValues() ServerFormData {
-	if sf.mode == ServerFormEdit && sf.original != nil {
+	if sf.original != nil {
 		return ServerFormData{
 			Alias:                sf.original.Alias,
 			Host:                 sf.original.Host,
@@ -1072,6 +1116,8 @@ func (sf *ServerForm) getDefaultValues() ServerFormData {
 			Port:                 fmt.Sprint(sf.original.Port),
 			Key:                  strings.Join(sf.original.IdentityFiles, ", "),
 			Tags:                 strings.Join(sf.original.Tags, ", "),
+			RequiresVPN:          sf.original.RequiresVPN,
+			PingTarget:           sf.original.PingTarget,
 			ProxyJump:            sf.original.ProxyJump,
 			ProxyCommand:         sf.original.ProxyCommand,
 			RemoteCommand:        sf.original.RemoteCommand,
@@ -1141,11 +1187,11 @@ func (sf *ServerForm) getDefaultValues() ServerFormData {
 	// For new servers, use empty values instead of SSH defaults
 	// SSH defaults will be applied by the SSH client if values are not specified
 	return ServerFormData{
-		Alias: "",   // Explicitly empty for new servers
-		Host:  "",   // Explicitly empty for new servers
-		User:  "",   // Empty for new servers (SSH will use current username)
-		Port:  "22", // Keep port 22 as it's the standard SSH port
-		Key:   "",   // Empty for new servers (SSH will try default keys)
+		Alias: "",                             // Explicitly empty for new servers
+		Host:  "",                             // Explicitly empty for new servers
+		User:  "",                             // Empty for new servers (SSH will use current username)
+		Port:  "22",                           // Keep port 22 as it's the standard SSH port
+		Key:   sf.display.defaultIdentityFile, // default_identity_file, or empty so SSH tries its default keys
 		Tags:  "",
 
 		// All other fields should be empty for new servers
@@ -1243,9 +1289,12 @@ func (sf *ServerForm) createBasicForm() {
 
 	// Add validated input fields
 	sf.addValidatedInputField(form, "Alias:", "Alias", defaultValues.Alias, 20, GetFieldPlaceholder("Alias"))
-	sf.addValidatedInputField(form, "Host/IP:", "Host", defaultValues.Host, 20, GetFieldPlaceholder("Host"))
-	sf.addValidatedInputField(form, "User:", "User", defaultValues.User, 20, GetFieldPlaceholder("User"))
-	sf.addValidatedInputField(form, "Port:", "Port", defaultValues.Port, 20, GetFieldPlaceholder("Port"))
+	hostField := sf.addValidatedInputField(form, "Host/IP:", "Host", defaultValues.Host, 20, GetFieldPlaceholder("Host"))
+	userField := sf.addValidatedInputField(form, "User:", "User", defaultValues.User, 20, GetFieldPlaceholder("User"))
+	portField := sf.addValidatedInputField(form, "Port:", "Port", defaultValues.Port, 20, GetFieldPlaceholder("Port"))
+	if sf.mode == ServerFormAdd {
+		sf.splitHostShorthand(hostField, userField, portField)
+	}
 
 	// Keys field with autocomplete
 	keysField := sf.addValidatedInputField(form, "Keys:", "Keys", defaultValues.Key, 40, GetFieldPlaceholder("Keys"))
@@ -1253,6 +1302,8 @@ func (sf *ServerForm) createBasicForm() {
 
 	// Tags field
 	sf.addValidatedInputField(form, "Tags:", "Tags", defaultValues.Tags, 30, GetFieldPlaceholder("Tags"))
+	form.AddCheckbox("Requires VPN:", defaultValues.RequiresVPN, nil)
+	sf.addValidatedInputField(form, "Ping target:", "PingTarget", defaultValues.PingTarget, 30, GetFieldPlaceholder("PingTarget"))
 
 	// Add save and cancel buttons
 	form.AddButton("Save", sf.handleSaveButton)
@@ -1277,12 +1328,12 @@ func (sf *ServerForm) createConnectionForm() {
 
 	// RequestTTY dropdown
 	requestTTYOptions := createOptionsWithDefault("RequestTTY", []string{"", "yes", "no", "force", "auto"})
-	requestTTYIndex := sf.findOptionIndex(requestTTYOptions, defaultValues.RequestTTY)
+	requestTTYOptions, requestTTYIndex := sf.currentOption(requestTTYOptions, defaultValues.RequestTTY)
 	sf.addDropDownWithHelp(form, "RequestTTY:", "RequestTTY", requestTTYOptions, requestTTYIndex)
 
 	// SessionType dropdown (OpenSSH 8.7+)
 	sessionTypeOptions := createOptionsWithDefault("SessionType", []string{"", "none (-N)", "subsystem (-s)", "default"})
-	sessionTypeIndex := sf.findOptionIndex(sessionTypeOptions, defaultValues.SessionType)
+	sessionTypeOptions, sessionTypeIndex := sf.currentOption(sessionTypeOptions, defaultValues.SessionType)
 	sf.addDropDownWithHelp(form, "SessionType:", "SessionType", sessionTypeOptions, sessionTypeIndex)
 
 	form.AddTextView("\n[yellow]▶ Connection Settings[-]", "", 0, 1, true, false)
@@ -1292,7 +1343,7 @@ func (sf *ServerForm) createConnectionForm() {
 
 	// BatchMode dropdown (moved from Keep-Alive)
 	batchModeOptions := createOptionsWithDefault("BatchMode", []string{"", "yes", "no"})
-	batchModeIndex := sf.findOptionIndex(batchModeOptions, defaultValues.BatchMode)
+	batchModeOptions, batchModeIndex := sf.currentOption(batchModeOptions, defaultValues.BatchMode)
 	sf.addDropDownWithHelp(form, "BatchMode:", "BatchMode", batchModeOptions, batchModeIndex)
 
 	form.AddTextView("\n[yellow]▶ Bind Options[-]", "", 0, 1, true, false)
@@ -1300,26 +1351,26 @@ func (sf *ServerForm) createConnectionForm() {
 
 	// BindInterface dropdown with available network interfaces
 	interfaceOptions := append([]string{""}, GetNetworkInterfaces()...)
-	bindInterfaceIndex := sf.findOptionIndex(interfaceOptions, defaultValues.BindInterface)
+	interfaceOptions, bindInterfaceIndex := sf.currentOption(interfaceOptions, defaultValues.BindInterface)
 	sf.addDropDownWithHelp(form, "BindInterface:", "BindInterface", interfaceOptions, bindInterfaceIndex)
 
 	// AddressFamily dropdown
 	addressFamilyOptions := createOptionsWithDefault("AddressFamily", []string{"", "any", "inet", "inet6"})
-	addressFamilyIndex := sf.findOptionIndex(addressFamilyOptions, defaultValues.AddressFamily)
+	addressFamilyOptions, addressFamilyIndex := sf.currentOption(addressFamilyOptions, defaultValues.AddressFamily)
 	sf.addDropDownWithHelp(form, "AddressFamily:", "AddressFamily", addressFamilyOptions, addressFamilyIndex)
 
 	form.AddTextView("\n[yellow]▶ Hostname Canonicalization[-]", "", 0, 1, true, false)
 
 	// CanonicalizeHostname dropdown
 	canonicalizeOptions := createOptionsWithDefault("CanonicalizeHostname", []string{"", "yes", "no", "always"})
-	canonicalizeIndex := sf.findOptionIndex(canonicalizeOptions, defaultValues.CanonicalizeHostname)
+	canonicalizeOptions, canonicalizeIndex := sf.currentOption(canonicalizeOptions, defaultValues.CanonicalizeHostname)
 	sf.addDropDownWithHelp(form, "CanonicalizeHostname:", "CanonicalizeHostname", canonicalizeOptions, canonicalizeIndex)
 
 	sf.addInputFieldWithHelp(form, "CanonicalDomains:", "CanonicalDomains", defaultValues.CanonicalDomains, 40, GetFieldPlaceholder("CanonicalDomains"))
 
 	// CanonicalizeFallbackLocal dropdown
 	fallbackOptions := createOptionsWithDefault("CanonicalizeFallbackLocal", []string{"", "yes", "no"})
-	fallbackIndex := sf.findOptionIndex(fallbackOptions, defaultValues.CanonicalizeFallbackLocal)
+	fallbackOptions, fallbackIndex := sf.currentOption(fallbackOptions, defaultValues.CanonicalizeFallbackLocal)
 	sf.addDropDownWithHelp(form, "CanonicalizeFallbackLocal:", "CanonicalizeFallbackLocal", fallbackOptions, fallbackIndex)
 
 	sf.addValidatedInputField(form, "CanonicalizeMaxDots:", "CanonicalizeMaxDots", defaultValues.CanonicalizeMaxDots, 10, GetFieldPlaceholder("CanonicalizeMaxDots"))
@@ -1332,18 +1383,18 @@ func (sf *ServerForm) createConnectionForm() {
 
 	// Compression dropdown
 	compressionOptions := createOptionsWithDefault("Compression", []string{"", "yes", "no"})
-	compressionIndex := sf.findOptionIndex(compressionOptions, defaultValues.Compression)
+	compressionOptions, compressionIndex := sf.currentOption(compressionOptions, defaultValues.Compression)
 	sf.addDropDownWithHelp(form, "Compression:", "Compression", compressionOptions, compressionIndex)
 
 	// TCPKeepAlive dropdown
 	tcpKeepAliveOptions := createOptionsWithDefault("TCPKeepAlive", []string{"", "yes", "no"})
-	tcpKeepAliveIndex := sf.findOptionIndex(tcpKeepAliveOptions, defaultValues.TCPKeepAlive)
+	tcpKeepAliveOptions, tcpKeepAliveIndex := sf.currentOption(tcpKeepAliveOptions, defaultValues.TCPKeepAlive)
 	sf.addDropDownWithHelp(form, "TCPKeepAlive:", "TCPKeepAlive", tcpKeepAliveOptions, tcpKeepAliveIndex)
 
 	form.AddTextView("\n[yellow]▶ Multiplexing[-]", "", 0, 1, true, false)
 	// ControlMaster dropdown
 	controlMasterOptions := createOptionsWithDefault("ControlMaster", []string{"", "yes", "no", "auto", "ask", "autoask"})
-	controlMasterIndex := sf.findOptionIndex(controlMasterOptions, defaultValues.ControlMaster)
+	controlMasterOptions, controlMasterIndex := sf.currentOption(controlMasterOptions, defaultValues.ControlMaster)
 	sf.addDropDownWithHelp(form, "ControlMaster:", "ControlMaster", controlMasterOptions, controlMasterIndex)
 	sf.addInputFieldWithHelp(form, "ControlPath:", "ControlPath", defaultValues.ControlPath, 40, GetFieldPlaceholder("ControlPath"))
 	sf.addInputFieldWithHelp(form, "ControlPersist:", "ControlPersist", defaultValues.ControlPersist, 20, GetFieldPlaceholder("ControlPersist"))
@@ -1371,34 +1422,34 @@ func (sf *ServerForm) createForwardingForm() {
 
 	// ClearAllForwardings dropdown
 	clearAllForwardingsOptions := createOptionsWithDefault("ClearAllForwardings", []string{"", "yes", "no"})
-	clearAllForwardingsIndex := sf.findOptionIndex(clearAllForwardingsOptions, defaultValues.ClearAllForwardings)
+	clearAllForwardingsOptions, clearAllForwardingsIndex := sf.currentOption(clearAllForwardingsOptions, defaultValues.ClearAllForwardings)
 	sf.addDropDownWithHelp(form, "ClearAllForwardings:", "ClearAllForwardings", clearAllForwardingsOptions, clearAllForwardingsIndex)
 
 	// ExitOnForwardFailure dropdown
 	exitOnForwardFailureOptions := createOptionsWithDefault("ExitOnForwardFailure", []string{"", "yes", "no"})
-	exitOnForwardFailureIndex := sf.findOptionIndex(exitOnForwardFailureOptions, defaultValues.ExitOnForwardFailure)
+	exitOnForwardFailureOptions, exitOnForwardFailureIndex := sf.currentOption(exitOnForwardFailureOptions, defaultValues.ExitOnForwardFailure)
 	sf.addDropDownWithHelp(form, "ExitOnForwardFailure:", "ExitOnForwardFailure", exitOnForwardFailureOptions, exitOnForwardFailureIndex)
 
 	// GatewayPorts dropdown
 	gatewayPortsOptions := createOptionsWithDefault("GatewayPorts", []string{"", "yes", "no", "clientspecified"})
-	gatewayPortsIndex := sf.findOptionIndex(gatewayPortsOptions, defaultValues.GatewayPorts)
+	gatewayPortsOptions, gatewayPortsIndex := sf.currentOption(gatewayPortsOptions, defaultValues.GatewayPorts)
 	sf.addDropDownWithHelp(form, "GatewayPorts:", "GatewayPorts", gatewayPortsOptions, gatewayPortsIndex)
 
 	form.AddTextView("\n[yellow]▶ Agent & X11 Forwarding[-]", "", 0, 1, true, false)
 
 	// ForwardAgent dropdown
 	forwardAgentOptions := createOptionsWithDefault("ForwardAgent", []string{"", "yes", "no"})
-	forwardAgentIndex := sf.findOptionIndex(forwardAgentOptions, defaultValues.ForwardAgent)
+	forwardAgentOptions, forwardAgentIndex := sf.currentOption(forwardAgentOptions, defaultValues.ForwardAgent)
 	sf.addDropDownWithHelp(form, "ForwardAgent:", "ForwardAgent", forwardAgentOptions, forwardAgentIndex)
 
 	// ForwardX11 dropdown
 	forwardX11Options := createOptionsWithDefault("ForwardX11", []string{"", "yes", "no"})
-	forwardX11Index := sf.findOptionIndex(forwardX11Options, defaultValues.ForwardX11)
+	forwardX11Options, forwardX11Index := sf.currentOption(forwardX11Options, defaultValues.ForwardX11)
 	sf.addDropDownWithHelp(form, "ForwardX11:", "ForwardX11", forwardX11Options, forwardX11Index)
 
 	// ForwardX11Trusted dropdown
 	forwardX11TrustedOptions := createOptionsWithDefault("ForwardX11Trusted", []string{"", "yes", "no"})
-	forwardX11TrustedIndex := sf.findOptionIndex(forwardX11TrustedOptions, defaultValues.ForwardX11Trusted)
+	forwardX11TrustedOptions, forwardX11TrustedIndex := sf.currentOption(forwardX11TrustedOptions, defaultValues.ForwardX11Trusted)
 	sf.addDropDownWithHelp(form, "ForwardX11Trusted:", "ForwardX11Trusted", forwardX11TrustedOptions, forwardX11TrustedIndex)
 
 	// Add save and cancel buttons
@@ -1483,12 +1534,12 @@ func (sf *ServerForm) createAuthenticationForm() {
 
 	// PubkeyAuthentication dropdown
 	pubkeyOptions := createOptionsWithDefault("PubkeyAuthentication", []string{"", "yes", "no"})
-	pubkeyIndex := sf.findOptionIndex(pubkeyOptions, defaultValues.PubkeyAuthentication)
+	pubkeyOptions, pubkeyIndex := sf.currentOption(pubkeyOptions, defaultValues.PubkeyAuthentication)
 	sf.addDropDownWithHelp(form, "PubkeyAuthentication:", "PubkeyAuthentication", pubkeyOptions, pubkeyIndex)
 
 	// IdentitiesOnly dropdown - controls whether to use only specified identity files
 	identitiesOnlyOptions := createOptionsWithDefault("IdentitiesOnly", []string{"", "yes", "no"})
-	identitiesOnlyIndex := sf.findOptionIndex(identitiesOnlyOptions, defaultValues.IdentitiesOnly)
+	identitiesOnlyOptions, identitiesOnlyIndex := sf.currentOption(identitiesOnlyOptions, defaultValues.IdentitiesOnly)
 	sf.addDropDownWithHelp(form, "IdentitiesOnly:", "IdentitiesOnly", identitiesOnlyOptions, identitiesOnlyIndex)
 
 	// SSH Agent settings
@@ -1496,7 +1547,7 @@ func (sf *ServerForm) createAuthenticationForm() {
 
 	// AddKeysToAgent dropdown
 	addKeysOptions := createOptionsWithDefault("AddKeysToAgent", []string{"", "yes", "no", "ask", "confirm"})
-	addKeysIndex := sf.findOptionIndex(addKeysOptions, defaultValues.AddKeysToAgent)
+	addKeysOptions, addKeysIndex := sf.currentOption(addKeysOptions, defaultValues.AddKeysToAgent)
 	sf.addDropDownWithHelp(form, "AddKeysToAgent:", "AddKeysToAgent", addKeysOptions, addKeysIndex)
 
 	sf.addInputFieldWithHelp(form, "IdentityAgent:", "IdentityAgent", defaultValues.IdentityAgent, 40, GetFieldPlaceholder("IdentityAgent"))
@@ -1506,12 +1557,12 @@ func (sf *ServerForm) createAuthenticationForm() {
 
 	// PasswordAuthentication dropdown
 	passwordOptions := createOptionsWithDefault("PasswordAuthentication", []string{"", "yes", "no"})
-	passwordIndex := sf.findOptionIndex(passwordOptions, defaultValues.PasswordAuthentication)
+	passwordOptions, passwordIndex := sf.currentOption(passwordOptions, defaultValues.PasswordAuthentication)
 	sf.addDropDownWithHelp(form, "PasswordAuthentication:", "PasswordAuthentication", passwordOptions, passwordIndex)
 
 	// KbdInteractiveAuthentication dropdown
 	kbdInteractiveOptions := createOptionsWithDefault("KbdInteractiveAuthentication", []string{"", "yes", "no"})
-	kbdInteractiveIndex := sf.findOptionIndex(kbdInteractiveOptions, defaultValues.KbdInteractiveAuthentication)
+	kbdInteractiveOptions, kbdInteractiveIndex := sf.currentOption(kbdInteractiveOptions, defaultValues.KbdInteractiveAuthentication)
 	sf.addDropDownWithHelp(form, "KbdInteractiveAuthentication:", "KbdInteractiveAuthentication", kbdInteractiveOptions, kbdInteractiveIndex)
 
 	// NumberOfPasswordPrompts field
@@ -1550,37 +1601,37 @@ func (sf *ServerForm) createAdvancedForm() {
 
 	// StrictHostKeyChecking dropdown
 	strictHostKeyOptions := createOptionsWithDefault("StrictHostKeyChecking", []string{"", "yes", "no", "ask", "accept-new"})
-	strictHostKeyIndex := sf.findOptionIndex(strictHostKeyOptions, defaultValues.StrictHostKeyChecking)
+	strictHostKeyOptions, strictHostKeyIndex := sf.currentOption(strictHostKeyOptions, defaultValues.StrictHostKeyChecking)
 	sf.addDropDownWithHelp(form, "StrictHostKeyChecking:", "StrictHostKeyChecking", strictHostKeyOptions, strictHostKeyIndex)
 
 	// CheckHostIP dropdown
 	checkHostIPOptions := createOptionsWithDefault("CheckHostIP", []string{"", "yes", "no"})
-	checkHostIPIndex := sf.findOptionIndex(checkHostIPOptions, defaultValues.CheckHostIP)
+	checkHostIPOptions, checkHostIPIndex := sf.currentOption(checkHostIPOptions, defaultValues.CheckHostIP)
 	sf.addDropDownWithHelp(form, "CheckHostIP:", "CheckHostIP", checkHostIPOptions, checkHostIPIndex)
 
 	// FingerprintHash dropdown
 	fingerprintHashOptions := createOptionsWithDefault("FingerprintHash", []string{"", "md5", "sha256"})
-	fingerprintHashIndex := sf.findOptionIndex(fingerprintHashOptions, defaultValues.FingerprintHash)
+	fingerprintHashOptions, fingerprintHashIndex := sf.currentOption(fingerprintHashOptions, defaultValues.FingerprintHash)
 	sf.addDropDownWithHelp(form, "FingerprintHash:", "FingerprintHash", fingerprintHashOptions, fingerprintHashIndex)
 
 	// VerifyHostKeyDNS dropdown
 	verifyHostKeyDNSOptions := createOptionsWithDefault("VerifyHostKeyDNS", []string{"", "yes", "no", "ask"})
-	verifyHostKeyDNSIndex := sf.findOptionIndex(verifyHostKeyDNSOptions, defaultValues.VerifyHostKeyDNS)
+	verifyHostKeyDNSOptions, verifyHostKeyDNSIndex := sf.currentOption(verifyHostKeyDNSOptions, defaultValues.VerifyHostKeyDNS)
 	sf.addDropDownWithHelp(form, "VerifyHostKeyDNS:", "VerifyHostKeyDNS", verifyHostKeyDNSOptions, verifyHostKeyDNSIndex)
 
 	// UpdateHostKeys dropdown
 	updateHostKeysOptions := createOptionsWithDefault("UpdateHostKeys", []string{"", "yes", "no", "ask"})
-	updateHostKeysIndex := sf.findOptionIndex(updateHostKeysOptions, defaultValues.UpdateHostKeys)
+	updateHostKeysOptions, updateHostKeysIndex := sf.currentOption(updateHostKeysOptions, defaultValues.UpdateHostKeys)
 	sf.addDropDownWithHelp(form, "UpdateHostKeys:", "UpdateHostKeys", updateHostKeysOptions, updateHostKeysIndex)
 
 	// HashKnownHosts dropdown
 	hashKnownHostsOptions := createOptionsWithDefault("HashKnownHosts", []string{"", "yes", "no"})
-	hashKnownHostsIndex := sf.findOptionIndex(hashKnownHostsOptions, defaultValues.HashKnownHosts)
+	hashKnownHostsOptions, hashKnownHostsIndex := sf.currentOption(hashKnownHostsOptions, defaultValues.HashKnownHosts)
 	sf.addDropDownWithHelp(form, "HashKnownHosts:", "HashKnownHosts", hashKnownHostsOptions, hashKnownHostsIndex)
 
 	// VisualHostKey dropdown
 	visualHostKeyOptions := createOptionsWithDefault("VisualHostKey", []string{"", "yes", "no"})
-	visualHostKeyIndex := sf.findOptionIndex(visualHostKeyOptions, defaultValues.VisualHostKey)
+	visualHostKeyOptions, visualHostKeyIndex := sf.currentOption(visualHostKeyOptions, defaultValues.VisualHostKey)
 	sf.addDropDownWithHelp(form, "VisualHostKey:", "VisualHostKey", visualHostKeyOptions, visualHostKeyIndex)
 
 	// UserKnownHostsFile field with autocomplete and validation
@@ -1610,7 +1661,7 @@ func (sf *ServerForm) createAdvancedForm() {
 
 	// PermitLocalCommand dropdown
 	permitLocalCommandOptions := createOptionsWithDefault("PermitLocalCommand", []string{"", "yes", "no"})
-	permitLocalCommandIndex := sf.findOptionIndex(permitLocalCommandOptions, defaultValues.PermitLocalCommand)
+	permitLocalCommandOptions, permitLocalCommandIndex := sf.currentOption(permitLocalCommandOptions, defaultValues.PermitLocalCommand)
 	sf.addDropDownWithHelp(form, "PermitLocalCommand:", "PermitLocalCommand", permitLocalCommandOptions, permitLocalCommandIndex)
 
 	// EscapeChar input field
@@ -1624,7 +1675,7 @@ func (sf *ServerForm) createAdvancedForm() {
 
 	// LogLevel dropdown
 	logLevelOptions := createOptionsWithDefault("LogLevel", []string{"", "QUIET", "FATAL", "ERROR", "INFO", "VERBOSE", "DEBUG", "DEBUG1", "DEBUG2", "DEBUG3"})
-	logLevelIndex := sf.findOptionIndex(logLevelOptions, defaultValues.LogLevel)
+	logLevelOptions, logLevelIndex := sf.currentOption(logLevelOptions, defaultValues.LogLevel)
 	sf.addDropDownWithHelp(form, "LogLevel:", "LogLevel", logLevelOptions, logLevelIndex)
 
 	// Add save and cancel buttons
@@ -1645,6 +1696,9 @@ type ServerFormData struct {
 	Port  string
 	Key   string
 	Tags  string
+	// RequiresVPN and PingTarget are lazyssh metadata rather than SSH config directives.
+	RequiresVPN bool
+	PingTarget  string
 
 	// Connection and proxy settings
 	ProxyJump            string
@@ -1773,6 +1827,19 @@ func (sf *ServerForm) getFormData() ServerFormData {
 		return ""
 	}
 
+	getCheckboxValue := func(fieldName string) bool {
+		for _, form := range sf.forms {
+			for i := 0; i < form.GetFormItemCount(); i++ {
+				if checkbox, ok := form.GetFormItem(i).(*tview.Checkbox); ok {
+					if strings.HasPrefix(stripColorTags(strings.TrimSpace(checkbox.GetLabel())), fieldName) {
+						return checkbox.IsChecked()
+					}
+				}
+			}
+		}
+		return false
+	}
+
 	return ServerFormData{
 		Alias: getFieldText("Alias:"),
 		Host:  getFieldText("Host/IP:"),
@@ -1780,6 +1847,9 @@ func (sf *ServerForm) getFormData() ServerFormData {
 		Port:  getFieldText("Port:"),
 		Key:   getFieldText("Keys:"),
 		Tags:  getFieldText("Tags:"),
+
+		RequiresVPN: getCheckboxValue("Requires VPN:"),
+		PingTarget:  getFieldText("Ping target:"),
 		// Connection and proxy settings
 		ProxyJump:            getFieldText("ProxyJump:"),
 		ProxyCommand:         getFieldText("ProxyCommand:"),
@@ -1875,6 +1945,9 @@ func (sf *ServerForm) handleSaveButton() {
 
 // handleSave validates and saves the form, returns true if successful
 func (sf *ServerForm) handleSave() bool {
+	if sf.splitHost != nil {
+		sf.splitHost()
+	}
 	// First validate all fields with the new validation system
 	if !sf.validateAllFields() {
 		// Show validation errors
@@ -1936,7 +2009,11 @@ func (sf *ServerForm) handleSave() bool {
 
 	server := sf.dataToServer(data)
 	if sf.onSave != nil {
-		sf.onSave(server, sf.original)
+		if sf.mode == ServerFormEdit {
+			sf.onSave(server, sf.original)
+		} else {
+			sf.onSave(server, nil)
+		}
 	}
 	return true // Save successful
 }
@@ -2032,6 +2109,21 @@ func (sf *ServerForm) hasUnsavedChanges() bool {
 	return sf.serversDiffer(currentServer, *sf.original)
 }
 
+// formMetadataFields are the lazyssh metadata fields the form edits.
+var formMetadataFields = map[string]bool{"Tags": true, "RequiresVPN": true, "PingTarget": true}
+
+// formComparesField reports whether a domain.Server field counts towards unsaved changes:
+// directives, the alias and the metadata the form edits, but not bookkeeping.
+func formComparesField(name string) bool {
+	switch domain.ServerFieldKind(name) {
+	case domain.FieldState:
+		return false
+	case domain.FieldMeta:
+		return formMetadataFields[name]
+	}
+	return true
+}
+
 // serversDiffer compares two servers for differences using reflection
 func (sf *ServerForm) serversDiffer(a, b domain.Server) bool {
 	// Use reflection to compare all fields
@@ -2039,14 +2131,6 @@ func (sf *ServerForm) serversDiffer(a, b domain.Server) bool {
 	valB := reflect.ValueOf(b)
 	typeA := valA.Type()
 
-	// Fields to skip during comparison (lazyssh metadata fields)
-	skipFields := map[string]bool{
-		"Aliases":  true, // Computed field
-		"LastSeen": true, // Metadata field
-		"PinnedAt": true, // Metadata field
-		"SSHCount": true, // Metadata field
-	}
-
 	// Iterate through all fields
 	for i := 0; i < valA.NumField(); i++ {
 		fieldA := valA.Field(i)
@@ -2054,7 +2138,7 @@ func (sf *ServerForm) serversDiffer(a, b domain.Server) bool {
 		fieldName := typeA.Field(i).Name
 
 		// Skip unexported fields and metadata fields
-		if !fieldA.CanInterface() || skipFields[fieldName] {
+		if !fieldA.CanInterface() || !formComparesField(fieldName) {
 			continue
 		}
 
@@ -2188,6 +2272,8 @@ func (sf *ServerForm) dataToServer(data ServerFormData) domain.Server {
 		Port:                 port,
 		IdentityFiles:        keys,
 		Tags:                 tags,
+		RequiresVPN:          data.RequiresVPN,
+		PingTarget:           strings.TrimSpace(data.PingTarget),
 		ProxyJump:            data.ProxyJump,
 		ProxyCommand:         data.ProxyCommand,
 		RemoteCommand:        data.RemoteCommand,
@@ -2281,7 +2367,7 @@ func (sf *ServerForm) SetVersionInfo(version, commit string) *ServerForm {
 		sf.build()
 	} else {
 		// Rebuild header if already exists
-		sf.header = NewAppHeader(sf.version, sf.commit, RepoURL)
+		sf.header = NewAppHeader(sf.display, sf.version, sf.commit, RepoURL)
 	}
 	return sf
 }
@@ -0,0 +1,107 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ui
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/Adembc/lazyssh/internal/core/domain"
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+func TestCurrentOption(t *testing.T) {
+	logLevels := createOptionsWithDefault("LogLevel", []string{"", "QUIET", "INFO", "DEBUG3"})
+	sf := &ServerForm{}
+
+	tests := []struct {
+		name        string
+		value       string
+		wantIndex   int
+		wantOptions []string
+	}{
+		{"empty selects default", "", 0, logLevels},
+		{"listed value", "DEBUG3", 3, logLevels},
+		{"case-insensitive", "quiet", 1, logLevels},
+		{"unlisted value is kept", "DEBUG9", 4, append(append([]string{}, logLevels...), "DEBUG9")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			options, index := sf.currentOption(append([]string{}, logLevels...), tt.value)
+			if index != tt.wantIndex || !reflect.DeepEqual(options, tt.wantOptions) {
+				t.Errorf("currentOption(%q) = %v, %d; want %v, %d", tt.value, options, index, tt.wantOptions, tt.wantIndex)
+			}
+			if got := parseOptionValue(options[index]); tt.value != "" && !strings.EqualFold(got, tt.value) {
+				t.Errorf("saved value = %q, want %q", got, tt.value)
+			}
+		})
+	}
+}
+
+// typeInto delivers text to field one rune at a time, as tcell does for pasted text
+// without bracketed paste.
+func typeInto(field *tview.InputField, text string) {
+	handler := field.InputHandler()
+	for _, r := range text {
+		handler(tcell.NewEventKey(tcell.KeyRune, r, tcell.ModNone), func(tview.Primitive) {})
+	}
+}
+
+// basicField returns the Basic tab's input field whose label starts with name.
+func basicField(t *testing.T, sf *ServerForm, name string) *tview.InputField {
+	t.Helper()
+	form := sf.forms["Basic"]
+	for i := 0; i < form.GetFormItemCount(); i++ {
+		if field, ok := form.GetFormItem(i).(*tview.InputField); ok && strings.HasPrefix(stripColorTags(field.GetLabel()), name) {
+			return field
+		}
+	}
+	t.Fatalf("no %s field", name)
+	return nil
+}
+
+func TestServerFormSplitsHostShorthand(t *testing.T) {
+	t.Run("on leaving the field", func(t *testing.T) {
+		sf := NewServerForm(ServerFormAdd, nil, displaySettings{}).SetVersionInfo("test", "")
+		host := basicField(t, sf, "Host")
+		typeInto(host, "deploy@db.example.com:2222")
+		if got := host.GetText(); got != "deploy@db.example.com:2222" {
+			t.Fatalf("split while typing: Host = %q", got)
+		}
+		if label := host.GetLabel(); strings.Contains(label, "[red]") {
+			t.Errorf("shorthand marked invalid while typing: %q", label)
+		}
+		host.Blur()
+		data := sf.getFormData()
+		if data.Host != "db.example.com" || data.User != "deploy" || data.Port != "2222" {
+			t.Errorf("after blur Host=%q User=%q Port=%q", data.Host, data.User, data.Port)
+		}
+	})
+
+	t.Run("on save", func(t *testing.T) {
+		var saved domain.Server
+		sf := NewServerForm(ServerFormAdd, nil, displaySettings{}).SetVersionInfo("test", "").OnSave(func(s domain.Server, _ *domain.Server) { saved = s })
+		typeInto(basicField(t, sf, "Alias"), "db")
+		typeInto(basicField(t, sf, "Host"), "root@10.0.0.7:2200")
+		if !sf.handleSave() {
+			t.Fatalf("save failed: %v", sf.validation.GetAllErrors())
+		}
+		if saved.Host != "10.0.0.7" || saved.User != "root" || saved.Port != 2200 {
+			t.Errorf("saved Host=%q User=%q Port=%d", saved.Host, saved.User, saved.Port)
+		}
+	})
+}
@@ -15,6 +15,8 @@
 package ui
 
 import (
+	"time"
+
 	"github.com/Adembc/lazyssh/internal/core/domain"
 	"github.com/gdamore/tcell/v2"
 	"github.com/rivo/tview"
@@ -22,14 +24,20 @@ import (
 
 type ServerList struct {
 	*tview.List
+	display           displaySettings
 	servers           []domain.Server
+	staleAfter        time.Duration
+	marked            map[string]bool
+	endpoints         map[string]domain.Endpoint
 	onSelection       func(domain.Server)
 	onSelectionChange func(domain.Server)
 }
 
-func NewServerList() *ServerList {
+func NewServerList(display displaySettings) *ServerList {
 	list := &ServerList{
-		List: tview.NewList(),
+		List:    tview.NewList(),
+		display: display,
+		marked:  make(map[string]bool),
 	}
 	list.build()
 	return list
@@ -54,12 +62,20 @@ func (sl *ServerList) build() {
 	})
 }
 
+// UpdateServers replaces the listed servers. The cursor stays on the previously selected
+// alias when it is still listed, and moves to the top otherwise.
 func (sl *ServerList) UpdateServers(servers []domain.Server) {
+	selected := ""
+	if current, ok := sl.GetSelectedServer(); ok {
+		selected = current.Alias
+	}
+
 	sl.servers = servers
 	sl.List.Clear()
 
+	now := time.Now()
 	for i := range servers {
-		primary, secondary := formatServerLine(servers[i])
+		primary, secondary := sl.display.formatServerLine(servers[i], sl.endpoint(servers[i]), servers[i].IsStale(now, sl.staleAfter), sl.marked[servers[i].Alias])
 		idx := i
 		sl.List.AddItem(primary, secondary, 0, func() {
 			if sl.onSelection != nil {
@@ -69,11 +85,24 @@ func (sl *ServerList) UpdateServers(servers []domain.Server) {
 	}
 
 	if sl.List.GetItemCount() > 0 {
-		sl.List.SetCurrentItem(0)
-		if sl.onSelectionChange != nil {
-			sl.onSelectionChange(sl.servers[0])
+		if selected == "" || !sl.SelectAlias(selected) {
+			sl.List.SetCurrentItem(0)
+		}
+		if server, ok := sl.GetSelectedServer(); ok && sl.onSelectionChange != nil {
+			sl.onSelectionChange(server)
+		}
+	}
+}
+
+// SelectAlias moves the cursor to the server with the given alias, reporting whether it is listed.
+func (sl *ServerList) SelectAlias(alias string) bool {
+	for i := range sl.servers {
+		if sl.servers[i].Alias == alias {
+			sl.List.SetCurrentItem(i)
+			return true
 		}
 	}
+	return false
 }
 
 func (sl *ServerList) GetSelectedServer() (domain.Server, bool) {
@@ -84,6 +113,93 @@ func (sl *ServerList) GetSelectedServer() (domain.Server, bool) {
 	return domain.Server{}, false
 }
 
+// GetServers returns the servers currently shown in the list, in display order.
+func (sl *ServerList) GetServers() []domain.Server {
+	return sl.servers
+}
+
+// ToggleMarked flips the multi-select mark on the current server and returns the number of marked servers.
+func (sl *ServerList) ToggleMarked() int {
+	idx := sl.List.GetCurrentItem()
+	if idx < 0 || idx >= len(sl.servers) {
+		return len(sl.marked)
+	}
+	alias := sl.servers[idx].Alias
+	if sl.marked[alias] {
+		delete(sl.marked, alias)
+	} else {
+		sl.marked[alias] = true
+	}
+	sl.redrawItem(idx)
+	return len(sl.marked)
+}
+
+// MarkedServers returns the marked servers in display order.
+func (sl *ServerList) MarkedServers() []domain.Server {
+	out := make([]domain.Server, 0, len(sl.marked))
+	for _, s := range sl.servers {
+		if sl.marked[s.Alias] {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+// ClearMarked removes all multi-select marks.
+func (sl *ServerList) ClearMarked() {
+	if len(sl.marked) == 0 {
+		return
+	}
+	sl.marked = make(map[string]bool)
+	for i := range sl.servers {
+		sl.redrawItem(i)
+	}
+}
+
+// PruneMarked drops marks for servers missing from all, the unfiltered server list. Marks on
+// servers hidden by a search are kept.
+func (sl *ServerList) PruneMarked(all []domain.Server) {
+	exists := make(map[string]bool, len(all))
+	for _, s := range all {
+		exists[s.Alias] = true
+	}
+	for alias := range sl.marked {
+		if !exists[alias] {
+			delete(sl.marked, alias)
+		}
+	}
+}
+
+// redrawItem re-renders a single row in place without moving the cursor.
+func (sl *ServerList) redrawItem(idx int) {
+	s := sl.servers[idx]
+	primary, secondary := sl.display.formatServerLine(s, sl.endpoint(s), s.IsStale(time.Now(), sl.staleAfter), sl.marked[s.Alias])
+	sl.List.SetItemText(idx, primary, secondary)
+}
+
+// SetEndpoints shows the given resolved endpoints instead of the literal HostName and Port;
+// nil goes back to the literal values. Servers missing from endpoints stay literal.
+func (sl *ServerList) SetEndpoints(endpoints map[string]domain.Endpoint) {
+	sl.endpoints = endpoints
+	for i := range sl.servers {
+		sl.redrawItem(i)
+	}
+}
+
+// endpoint returns the endpoint displayed for s.
+func (sl *ServerList) endpoint(s domain.Server) domain.Endpoint {
+	if e, ok := sl.endpoints[s.Alias]; ok {
+		return e
+	}
+	return s.Endpoint()
+}
+
+// SetStaleAfter sets the window after which servers are rendered muted as stale; zero disables it.
+func (sl *ServerList) SetStaleAfter(d time.Duration) *ServerList {
+	sl.staleAfter = d
+	return sl
+}
+
 func (sl *ServerList) OnSelection(fn func(server domain.Server)) *ServerList {
 	sl.onSelection = fn
 	return sl
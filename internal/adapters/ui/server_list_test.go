@@ -0,0 +1,124 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ui
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/Adembc/lazyssh/internal/core/domain"
+	"github.com/Adembc/lazyssh/internal/core/ports"
+)
+
+func TestUpdateServersKeepsSelection(t *testing.T) {
+	sl := NewServerList(displaySettings{})
+	sl.UpdateServers([]domain.Server{{Alias: "a"}, {Alias: "b"}, {Alias: "c"}})
+	sl.SetCurrentItem(1)
+
+	sl.UpdateServers([]domain.Server{{Alias: "b"}, {Alias: "c"}, {Alias: "a"}})
+	if got, _ := sl.GetSelectedServer(); got.Alias != "b" {
+		t.Errorf("selected after reorder = %q, want %q", got.Alias, "b")
+	}
+
+	sl.UpdateServers([]domain.Server{{Alias: "c"}, {Alias: "a"}})
+	if got, _ := sl.GetSelectedServer(); got.Alias != "c" {
+		t.Errorf("selected after removal = %q, want first item %q", got.Alias, "c")
+	}
+}
+
+// listService is a ServerService that lists fixed servers; other methods are not used.
+type listService struct {
+	ports.ServerService
+	servers []domain.Server
+	queries []string
+}
+
+func (s *listService) ListServers(query string) ([]domain.Server, error) {
+	s.queries = append(s.queries, query)
+	return domain.FilterServers(append([]domain.Server(nil), s.servers...), query), nil
+}
+
+func (s *listService) DrainWarnings() []string { return nil }
+
+func TestMarksSurviveSearch(t *testing.T) {
+	svc := &listService{servers: []domain.Server{
+		{Alias: "api", Aliases: []string{"api"}},
+		{Alias: "db", Aliases: []string{"db"}},
+		{Alias: "web", Aliases: []string{"web"}},
+	}}
+	ui := &tui{
+		serverService: svc,
+		serverList:    NewServerList(displaySettings{}),
+		searchBar:     NewSearchBar(displaySettings{}),
+		header:        NewAppHeader(displaySettings{}, "test", "", ""),
+		details:       NewServerDetails(displaySettings{}),
+	}
+	ui.refreshServerList()
+	for _, alias := range []string{"api", "db"} {
+		ui.serverList.SelectAlias(alias)
+		ui.serverList.ToggleMarked()
+	}
+
+	ui.searchVisible = true
+	ui.searchBar.InputField.SetText("web")
+	ui.handleSearchInput("web")
+	ui.refreshServerList() // a reload while the search hides the marked servers
+	if ui.listShown != 1 {
+		t.Fatalf("search for web listed %d servers, want 1", ui.listShown)
+	}
+	ui.searchBar.InputField.SetText("")
+	ui.handleSearchInput("")
+
+	if got := ui.serverList.MarkedServers(); len(got) != 2 || got[0].Alias != "api" || got[1].Alias != "db" {
+		t.Errorf("marked after clearing the search = %+v, want api and db", got)
+	}
+
+	svc.servers = svc.servers[1:]
+	ui.refreshServerList()
+	if got := ui.serverList.MarkedServers(); len(got) != 1 || got[0].Alias != "db" {
+		t.Errorf("marked after api was removed = %+v, want db", got)
+	}
+}
+
+func TestRefreshLoadsServersOnce(t *testing.T) {
+	now := time.Now()
+	svc := &listService{servers: []domain.Server{
+		{Alias: "api", Aliases: []string{"api"}, SSHCount: 3, LastSeen: now},
+		{Alias: "web", Aliases: []string{"web"}, SSHCount: 2},
+	}}
+	ui := &tui{
+		serverService: svc,
+		serverList:    NewServerList(displaySettings{}),
+		searchBar:     NewSearchBar(displaySettings{}),
+		header:        NewAppHeader(displaySettings{}, "test", "", ""),
+		details:       NewServerDetails(displaySettings{}),
+	}
+	ui.header.SetStatsProvider(ui.headerStats)
+	ui.searchVisible = true
+	ui.searchBar.InputField.SetText("web")
+	svc.queries = nil
+
+	ui.refreshServerList()
+	if want := []string{"", "web"}; !reflect.DeepEqual(svc.queries, want) {
+		t.Errorf("ListServers queries = %q, want %q", svc.queries, want)
+	}
+	if stats, ok := ui.headerStats(); !ok || stats.Sessions != 5 {
+		t.Errorf("header stats = %+v, %v; want 5 sessions across all servers", stats, ok)
+	}
+	if ui.listShown != 1 || ui.listTotal != 2 {
+		t.Errorf("list counts = %d of %d, want 1 of 2", ui.listShown, ui.listTotal)
+	}
+}
@@ -29,6 +29,10 @@ const (
 	SortByAliasDesc
 	SortByLastSeenDesc
 	SortByLastSeenAsc
+	// SortByStaleness lists the least recently used servers first, never-used ones on top.
+	SortByStaleness
+	// SortByConfigOrder keeps the order of Host entries in the SSH config, which Ctrl+Up/Down changes.
+	SortByConfigOrder
 )
 
 func (m SortMode) String() string {
@@ -41,22 +45,28 @@ func (m SortMode) String() string {
 		return "Last SSH ↑"
 	case SortByLastSeenDesc:
 		return "Last SSH ↓"
+	case SortByStaleness:
+		return "Stalest first"
+	case SortByConfigOrder:
+		return "Config order"
 	default:
 		return "Alias ↑"
 	}
 }
 
-// ToggleField switches between Alias and LastSeen while preserving direction.
+// ToggleField cycles Alias → LastSeen (preserving direction) → Staleness → Config order → Alias.
 func (m SortMode) ToggleField() SortMode {
 	switch m {
 	case SortByAliasAsc:
 		return SortByLastSeenAsc
 	case SortByAliasDesc:
 		return SortByLastSeenDesc
-	case SortByLastSeenAsc:
+	case SortByLastSeenAsc, SortByLastSeenDesc:
+		return SortByStaleness
+	case SortByStaleness:
+		return SortByConfigOrder
+	case SortByConfigOrder:
 		return SortByAliasAsc
-	case SortByLastSeenDesc:
-		return SortByAliasDesc
 	default:
 		return SortByAliasAsc
 	}
@@ -73,13 +83,19 @@ func (m SortMode) Reverse() SortMode {
 		return SortByLastSeenDesc
 	case SortByLastSeenDesc:
 		return SortByLastSeenAsc
+	case SortByStaleness:
+		// Staleness has a single direction; the reverse is Last SSH ↓.
+		return SortByLastSeenDesc
+	case SortByConfigOrder:
+		// The file order has no meaningful reverse.
+		return SortByConfigOrder
 	default:
 		return SortByAliasAsc
 	}
 }
 
 // sortServersForUI sorts servers according to the rules required by the UI.
-// Pinned servers are always at the top, ordered by pinned date (newest first).
+// Pinned servers are always at the top, in manual pin order and then by pinned date (newest first).
 // Unpinned servers are sorted by the selected mode. "Never" (zero time) goes to
 // the bottom when sorting by last seen asc/desc accordingly. Ties break by Alias asc.
 func sortServersForUI(servers []domain.Server, mode SortMode) {
@@ -90,15 +106,24 @@ func sortServersForUI(servers []domain.Server, mode SortMode) {
 		if pi != pj {
 			return pi
 		}
-		if pi && pj { // both pinned: newer pinned first, tie-break alias
-			if !si.PinnedAt.Equal(sj.PinnedAt) {
-				return si.PinnedAt.After(sj.PinnedAt)
-			}
-			return strings.ToLower(si.Alias) < strings.ToLower(sj.Alias)
+		if pi && pj {
+			return si.PinnedBefore(sj)
 		}
 
 		// both unpinned
 		switch mode {
+		case SortByConfigOrder:
+			return si.ConfigOrder < sj.ConfigOrder
+		case SortByStaleness:
+			zi := si.LastSeen.IsZero()
+			zj := sj.LastSeen.IsZero()
+			if zi != zj {
+				return zi // never-used first
+			}
+			if !zi && !zj && !si.LastSeen.Equal(sj.LastSeen) {
+				return si.LastSeen.Before(sj.LastSeen)
+			}
+			return strings.ToLower(si.Alias) < strings.ToLower(sj.Alias)
 		case SortByLastSeenDesc, SortByLastSeenAsc:
 			zi := si.LastSeen.IsZero()
 			zj := sj.LastSeen.IsZero()
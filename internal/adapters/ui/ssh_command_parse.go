@@ -0,0 +1,296 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ui
+
+import (
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/Adembc/lazyssh/internal/core/domain"
+)
+
+// sshFlagsWithArg are the ssh(1) options that take a value. Those not mapped in
+// applySSHFlag are accepted and ignored.
+const sshFlagsWithArg = "BbcDEeFIiJLlmOopQRSWw"
+
+// ParseSSHCommand builds a server from a command line such as
+// "ssh -p 2222 -i ~/.ssh/key -o ServerAliveInterval=30 ubuntu@host.example.com".
+// Options may follow the destination as with ssh itself; anything after the first
+// argument that is not an option becomes the RemoteCommand. -l, -p and -o HostName take
+// precedence over the destination, matching ssh; the alias always comes from the destination.
+func ParseSSHCommand(cmd string) (domain.Server, error) {
+	args, err := splitShellWords(cmd)
+	if err != nil {
+		return domain.Server{}, err
+	}
+	if len(args) > 0 && filepath.Base(args[0]) == "ssh" {
+		args = args[1:]
+	}
+
+	var server domain.Server
+	destination := ""
+	var command []string
+	optionsDone := false
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch {
+		case len(command) > 0:
+			command = append(command, arg)
+		case arg == "--" && !optionsDone:
+			optionsDone = true
+		case strings.HasPrefix(arg, "-") && len(arg) > 1 && !optionsDone:
+			consumed, err := parseSSHFlagGroup(&server, arg[1:], args[i+1:])
+			if err != nil {
+				return domain.Server{}, err
+			}
+			i += consumed
+		case destination == "":
+			destination = arg
+		default:
+			command = append(command, arg)
+		}
+	}
+	if destination == "" {
+		return domain.Server{}, fmt.Errorf("no destination host in command")
+	}
+
+	user, host, port := splitDestination(destination)
+	if host == "" {
+		return domain.Server{}, fmt.Errorf("no destination host in command")
+	}
+	if server.Host == "" {
+		server.Host = host
+	}
+	if server.User == "" {
+		server.User = user
+	}
+	if server.Port == 0 {
+		server.Port = port
+	}
+	if len(command) > 0 {
+		server.RemoteCommand = strings.Join(command, " ")
+	}
+	server.Alias = aliasFromHost(host)
+	return server, nil
+}
+
+// parseSSHFlagGroup applies a group of single-letter flags such as "At" or "p2222". A flag
+// taking a value uses the rest of the group or the next argument; it returns how many of
+// the following arguments were consumed.
+func parseSSHFlagGroup(server *domain.Server, group string, rest []string) (int, error) {
+	for j := 0; j < len(group); j++ {
+		flag := group[j]
+		if !strings.ContainsRune(sshFlagsWithArg, rune(flag)) {
+			applySSHSwitch(server, flag)
+			continue
+		}
+		if value := group[j+1:]; value != "" {
+			return 0, applySSHFlag(server, flag, value)
+		}
+		if len(rest) == 0 {
+			return 0, fmt.Errorf("option -%c needs a value", flag)
+		}
+		return 1, applySSHFlag(server, flag, rest[0])
+	}
+	return 0, nil
+}
+
+// applySSHFlag applies an ssh option that takes a value.
+func applySSHFlag(server *domain.Server, flag byte, value string) error {
+	switch flag {
+	case 'p':
+		port, err := strconv.Atoi(value)
+		if err != nil || port < 1 || port > 65535 {
+			return fmt.Errorf("invalid port %q", value)
+		}
+		server.Port = port
+	case 'i':
+		server.IdentityFiles = append(server.IdentityFiles, value)
+	case 'l':
+		server.User = value
+	case 'J':
+		server.ProxyJump = value
+	case 'L':
+		server.LocalForward = append(server.LocalForward, value)
+	case 'R':
+		server.RemoteForward = append(server.RemoteForward, value)
+	case 'D':
+		server.DynamicForward = append(server.DynamicForward, value)
+	case 'b':
+		server.BindAddress = value
+	case 'B':
+		server.BindInterface = value
+	case 'c':
+		server.Ciphers = value
+	case 'm':
+		server.MACs = value
+	case 'e':
+		server.EscapeChar = value
+	case 'S':
+		server.ControlPath = value
+	case 'o':
+		return applySSHOption(server, value)
+	}
+	return nil
+}
+
+// applySSHSwitch applies an ssh option without a value; unknown switches are ignored.
+func applySSHSwitch(server *domain.Server, flag byte) {
+	switch flag {
+	case 'A':
+		server.ForwardAgent = sshYes
+	case 'a':
+		server.ForwardAgent = sshNo
+	case 'X':
+		server.ForwardX11 = sshYes
+	case 'x':
+		server.ForwardX11 = sshNo
+	case 'Y':
+		server.ForwardX11 = sshYes
+		server.ForwardX11Trusted = sshYes
+	case 'C':
+		server.Compression = sshYes
+	case 'g':
+		server.GatewayPorts = sshYes
+	case 'M':
+		server.ControlMaster = sshYes
+	case 'N':
+		server.SessionType = sessionTypeNone
+	case 'T':
+		server.RequestTTY = sshNo
+	case 't':
+		server.RequestTTY = sshYes
+	case '4':
+		server.AddressFamily = "inet"
+	case '6':
+		server.AddressFamily = "inet6"
+	}
+}
+
+// sshOptionFields maps -o keywords whose domain.Server field has a different name.
+var sshOptionFields = map[string]string{
+	"hostname":     "Host",
+	"identityfile": "IdentityFiles",
+}
+
+// applySSHOption applies "-o Key=Value" (or "Key Value") to the matching server field.
+func applySSHOption(server *domain.Server, option string) error {
+	key, value, ok := strings.Cut(option, "=")
+	if !ok {
+		key, value, _ = strings.Cut(option, " ")
+	}
+	key, value = strings.TrimSpace(key), strings.TrimSpace(value)
+	if key == "" || value == "" {
+		return fmt.Errorf("invalid option %q", option)
+	}
+
+	if strings.EqualFold(key, "port") {
+		return applySSHFlag(server, 'p', value)
+	}
+	name, ok := sshOptionFields[strings.ToLower(key)]
+	if !ok {
+		name = key
+	}
+	v := reflect.ValueOf(server).Elem()
+	field := v.FieldByNameFunc(func(n string) bool { return strings.EqualFold(n, name) && domain.ServerFieldKind(n) == "" })
+	if !field.IsValid() {
+		return fmt.Errorf("unsupported option %q", key)
+	}
+	switch {
+	case field.Kind() == reflect.String:
+		field.SetString(value)
+	case field.Kind() == reflect.Slice && field.Type().Elem().Kind() == reflect.String:
+		field.Set(reflect.Append(field, reflect.ValueOf(value)))
+	default:
+		return fmt.Errorf("unsupported option %q", key)
+	}
+	return nil
+}
+
+// splitDestination parses [user@]host or ssh://[user@]host[:port].
+func splitDestination(dest string) (user, host string, port int) {
+	if rest, ok := strings.CutPrefix(dest, "ssh://"); ok {
+		return parseUserHostPort(strings.TrimSuffix(rest, "/"))
+	}
+	if i := strings.LastIndex(dest, "@"); i >= 0 {
+		return dest[:i], dest[i+1:], 0
+	}
+	return "", dest, 0
+}
+
+// aliasFromHost derives an alias from a host name, replacing characters aliases may not contain.
+func aliasFromHost(host string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '.', r == '-', r == '_':
+			return r
+		default:
+			return '-'
+		}
+	}, host)
+}
+
+// splitShellWords splits s into arguments like a POSIX shell: whitespace separates words,
+// single quotes are literal, and double quotes and backslashes escape as usual.
+func splitShellWords(s string) ([]string, error) {
+	var words []string
+	var word strings.Builder
+	inWord := false
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c == '\'':
+			end := strings.IndexByte(s[i+1:], '\'')
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated single quote")
+			}
+			word.WriteString(s[i+1 : i+1+end])
+			i += end + 1
+			inWord = true
+		case c == '"':
+			i++
+			for ; i < len(s) && s[i] != '"'; i++ {
+				if s[i] == '\\' && i+1 < len(s) && strings.IndexByte("\"\\$`", s[i+1]) >= 0 {
+					i++
+				}
+				word.WriteByte(s[i])
+			}
+			if i >= len(s) {
+				return nil, fmt.Errorf("unterminated double quote")
+			}
+			inWord = true
+		case c == '\\' && i+1 < len(s):
+			i++
+			word.WriteByte(s[i])
+			inWord = true
+		case c == ' ' || c == '\t' || c == '\n':
+			if inWord {
+				words = append(words, word.String())
+				word.Reset()
+				inWord = false
+			}
+		default:
+			word.WriteByte(c)
+			inWord = true
+		}
+	}
+	if inWord {
+		words = append(words, word.String())
+	}
+	return words, nil
+}
@@ -0,0 +1,87 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ui
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/Adembc/lazyssh/internal/core/domain"
+)
+
+func TestParseSSHCommand(t *testing.T) {
+	tests := []struct {
+		name string
+		cmd  string
+		want domain.Server
+	}{
+		{
+			name: "common flags",
+			cmd:  "ssh -p 2222 -i ~/.ssh/key ubuntu@host.example.com",
+			want: domain.Server{Alias: "host.example.com", Host: "host.example.com", User: "ubuntu", Port: 2222, IdentityFiles: []string{"~/.ssh/key"}},
+		},
+		{
+			name: "joined values and switches",
+			cmd:  "ssh -At -p2222 -lroot 10.0.0.5",
+			want: domain.Server{Alias: "10.0.0.5", Host: "10.0.0.5", User: "root", Port: 2222, ForwardAgent: "yes", RequestTTY: "yes"},
+		},
+		{
+			name: "multiple -o options with quotes",
+			cmd:  `ssh -o ServerAliveInterval=30 -o "ProxyCommand ssh -W %h:%p bastion" -o 'SendEnv=LANG LC_*' -o SendEnv=TZ db`,
+			want: domain.Server{Alias: "db", Host: "db", ServerAliveInterval: "30", ProxyCommand: "ssh -W %h:%p bastion", SendEnv: []string{"LANG LC_*", "TZ"}},
+		},
+		{
+			name: "options after destination and remote command",
+			cmd:  "/usr/bin/ssh admin@web -J bastion -L 8080:localhost:80 uptime -s",
+			want: domain.Server{Alias: "web", Host: "web", User: "admin", ProxyJump: "bastion", LocalForward: []string{"8080:localhost:80"}, RemoteCommand: "uptime -s"},
+		},
+		{
+			name: "flags win over ssh url",
+			cmd:  "ssh -p 22 ssh://deploy@[2001:db8::1]:2200",
+			want: domain.Server{Alias: "2001-db8--1", Host: "2001:db8::1", User: "deploy", Port: 22},
+		},
+		{
+			name: "hostname and user options",
+			cmd:  "ssh -o User=git -o HostName=github.com -o Port=443 gh",
+			want: domain.Server{Alias: "gh", Host: "github.com", User: "git", Port: 443},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseSSHCommand(tt.cmd)
+			if err != nil {
+				t.Fatalf("ParseSSHCommand() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseSSHCommand() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseSSHCommandErrors(t *testing.T) {
+	for _, cmd := range []string{
+		"ssh",
+		"ssh -p",
+		"ssh -p abc host",
+		"ssh -o NoSuchOption=1 host",
+		"ssh -o Tags=x host",
+		`ssh "host`,
+	} {
+		if _, err := ParseSSHCommand(cmd); err == nil {
+			t.Errorf("ParseSSHCommand(%q) expected an error", cmd)
+		}
+	}
+}
@@ -30,3 +30,12 @@ func NewStatusBar() *tview.TextView {
 	status.SetText(DefaultStatusText())
 	return status
 }
+
+// NewReadOnlyBanner builds the persistent warning shown when the SSH config cannot be saved.
+func NewReadOnlyBanner(path string) *tview.TextView {
+	banner := tview.NewTextView().SetDynamicColors(true)
+	banner.SetBackgroundColor(tcell.Color52)
+	banner.SetTextAlign(tview.AlignCenter)
+	banner.SetText("[::b]READ-ONLY CONFIG[::-] " + tview.Escape(path) + " is not writable — add, edit and delete are disabled")
+	return banner
+}
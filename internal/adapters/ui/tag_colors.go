@@ -0,0 +1,68 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ui
+
+import (
+	"hash/fnv"
+	"regexp"
+	"strings"
+)
+
+// tagPalette holds chip background colors chosen to stay readable with black text.
+var tagPalette = []string{
+	"#5FAFFF", // blue
+	"#87D787", // green
+	"#FFAF5F", // orange
+	"#D787D7", // magenta
+	"#5FD7D7", // cyan
+	"#FFD75F", // yellow
+	"#FF8787", // red
+	"#AFAFFF", // lavender
+	"#AFD75F", // lime
+	"#D7AF87", // tan
+}
+
+// validTagColor accepts #RRGGBB or a plain color name understood by tview (e.g. "red").
+var validTagColor = regexp.MustCompile(`^(#[0-9A-Fa-f]{6}|[A-Za-z]+)$`)
+
+// parseTagColors keys the tag_colors config by lower-cased tag name. Invalid color values are
+// ignored.
+func parseTagColors(colors map[string]string) map[string]string {
+	overrides := make(map[string]string, len(colors))
+	for tag, color := range colors {
+		color = strings.TrimSpace(color)
+		if validTagColor.MatchString(color) {
+			overrides[strings.ToLower(strings.TrimSpace(tag))] = color
+		}
+	}
+	return overrides
+}
+
+// tagColor returns the chip color for a tag: the configured override if any, otherwise a
+// palette entry picked by hashing the tag name so the same tag always gets the same color.
+func (d displaySettings) tagColor(tag string) string {
+	key := strings.ToLower(strings.TrimSpace(tag))
+	if c, ok := d.tagColors[key]; ok {
+		return c
+	}
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(key))
+	return tagPalette[h.Sum32()%uint32(len(tagPalette))]
+}
+
+// renderTagChip renders a single colored tag chip.
+func (d displaySettings) renderTagChip(tag string) string {
+	return "[black:" + d.tagColor(tag) + "] " + tag + " [-:-:-]"
+}
@@ -0,0 +1,158 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/Adembc/lazyssh/internal/core/domain"
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// tagUsage is a tag together with the number of servers carrying it.
+type tagUsage struct {
+	tag   string
+	count int
+}
+
+// collectTagUsage counts tag usage across servers, most used first, ties by name.
+func collectTagUsage(servers []domain.Server) []tagUsage {
+	counts := make(map[string]int)
+	for _, s := range servers {
+		for _, t := range s.Tags {
+			counts[t]++
+		}
+	}
+	usage := make([]tagUsage, 0, len(counts))
+	for t, c := range counts {
+		usage = append(usage, tagUsage{tag: t, count: c})
+	}
+	sort.Slice(usage, func(i, j int) bool {
+		if usage[i].count != usage[j].count {
+			return usage[i].count > usage[j].count
+		}
+		return strings.ToLower(usage[i].tag) < strings.ToLower(usage[j].tag)
+	})
+	return usage
+}
+
+// showTagManager lists every tag with its usage count and lets the user rename or delete it everywhere.
+func (t *tui) showTagManager() {
+	t.showTagManagerNotice("")
+}
+
+// showTagManagerNotice is showTagManager with notice, the result of the last rename or delete,
+// in the title. The status bar is hidden while the tag manager is open, so results are shown here.
+func (t *tui) showTagManagerNotice(notice string) {
+	servers, err := t.serverService.ListServers("")
+	if err != nil {
+		t.returnToMain()
+		t.showStatusTempColor(fmt.Sprintf("Failed to load tags: %v", err), "#FF6B6B")
+		return
+	}
+	usage := collectTagUsage(servers)
+
+	title := " Tags — Enter: rename/delete • Esc: back "
+	if notice != "" {
+		title = fmt.Sprintf(" Tags — %s • Esc: back ", notice)
+	}
+	list := tview.NewList().ShowSecondaryText(false)
+	list.SetBorder(true).
+		SetTitle(title).
+		SetTitleAlign(tview.AlignCenter).
+		SetBorderColor(tcell.Color238).
+		SetTitleColor(tcell.Color250)
+	list.SetSelectedBackgroundColor(tcell.Color24).
+		SetSelectedTextColor(tcell.Color255).
+		SetHighlightFullLine(true)
+
+	if len(usage) == 0 {
+		list.AddItem("[#888888]No tags yet. Press t on a server to add some.[-]", "", 0, nil)
+	}
+	for _, u := range usage {
+		u := u
+		label := fmt.Sprintf("%s  [#888888]%d server(s)[-]", t.display.renderTagChip(u.tag), u.count)
+		list.AddItem(label, "", 0, func() { t.showTagActionForm(u) })
+	}
+
+	list.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch {
+		case event.Key() == tcell.KeyEscape, event.Rune() == 'q':
+			t.returnToMain()
+			return nil
+		case event.Rune() == 'j':
+			return tcell.NewEventKey(tcell.KeyDown, 0, tcell.ModNone)
+		case event.Rune() == 'k':
+			return tcell.NewEventKey(tcell.KeyUp, 0, tcell.ModNone)
+		}
+		return event
+	})
+
+	t.app.SetRoot(list, true)
+	t.app.SetFocus(list)
+}
+
+// showTagActionForm offers renaming or deleting a single tag across all servers.
+func (t *tui) showTagActionForm(u tagUsage) {
+	form := tview.NewForm()
+	form.SetBorder(true).
+		SetTitle(fmt.Sprintf(" Tag: %s (%d server(s)) ", u.tag, u.count)).
+		SetTitleAlign(tview.AlignCenter)
+
+	form.AddInputField("New name:", u.tag, 30, nil, nil)
+
+	form.AddButton("Rename", func() {
+		if !t.ensureMetadataWritable() {
+			t.returnToMain()
+			return
+		}
+		newTag := strings.TrimSpace(form.GetFormItem(0).(*tview.InputField).GetText())
+		if newTag == u.tag {
+			t.showTagManager()
+			return
+		}
+		n, err := t.serverService.RenameTag(u.tag, newTag)
+		t.refreshServerList()
+		if err != nil {
+			t.showTagManagerNotice(fmt.Sprintf("[#FF6B6B]Rename failed: %s[-]", tview.Escape(err.Error())))
+			return
+		}
+		t.showTagManagerNotice(fmt.Sprintf("Renamed %s → %s on %d server(s)", tview.Escape(u.tag), tview.Escape(newTag), n))
+	})
+	form.AddButton("Delete", func() {
+		if !t.ensureMetadataWritable() {
+			t.returnToMain()
+			return
+		}
+		msg := fmt.Sprintf("Remove tag %s from %d server(s)?\n\nThis action cannot be undone.", u.tag, u.count)
+		t.showDeleteConfirmOver(msg, func() { t.showTagActionForm(u) }, func() {
+			n, err := t.serverService.DeleteTag(u.tag)
+			t.refreshServerList()
+			if err != nil {
+				t.showTagManagerNotice(fmt.Sprintf("[#FF6B6B]Delete failed: %s[-]", tview.Escape(err.Error())))
+				return
+			}
+			t.showTagManagerNotice(fmt.Sprintf("Removed %s from %d server(s)", tview.Escape(u.tag), n))
+		})
+	})
+	form.AddButton("Back", func() { t.showTagManager() })
+	form.SetCancelFunc(func() { t.showTagManager() })
+
+	t.app.SetRoot(form, true)
+	t.app.SetFocus(form)
+}
@@ -15,9 +15,16 @@
 package ui
 
 import (
+	"os"
+	"strings"
+	"sync"
+	"time"
+
 	"github.com/gdamore/tcell/v2"
 	"go.uber.org/zap"
 
+	"github.com/Adembc/lazyssh/internal/config"
+	"github.com/Adembc/lazyssh/internal/core/domain"
 	"github.com/Adembc/lazyssh/internal/core/ports"
 	"github.com/rivo/tview"
 )
@@ -28,6 +35,9 @@ type App interface {
 
 type tui struct {
 	logger *zap.SugaredLogger
+	cfg    config.Config
+	// display holds the config values the components render with.
+	display displaySettings
 
 	version string
 	commit  string
@@ -44,19 +54,56 @@ type tui struct {
 
 	root    *tview.Flex
 	left    *tview.Flex
+	right   *tview.Flex
 	content *tview.Flex
+	// listWidth is the list's share of the content in percent; details get the rest.
+	listWidth int
+	// stacked places the details below the list rather than beside it.
+	stacked bool
+	// layoutPending holds layout settings waiting for layoutTimer to save them.
+	layoutMu      sync.Mutex
+	layoutPending map[string]any
+	layoutTimer   *time.Timer
 
 	sortMode      SortMode
 	searchVisible bool
+	// showResolved lists each server's `ssh -G` endpoint instead of its literal HostName and Port.
+	showResolved bool
+
+	// readOnlyConfig is the SSH config path when it cannot be written, empty otherwise.
+	readOnlyConfig string
+	readOnlyBanner *tview.TextView
+
+	// pinging tracks aliases with a background ping in flight; only touched on the UI goroutine.
+	pinging map[string]bool
+	// listShown and listTotal count the listed servers and all servers, and listQuery is the
+	// search they were listed for; the list title shows them.
+	listShown, listTotal int
+	listQuery            string
+	// allServers is the unfiltered list last loaded; the header's activity summary is
+	// computed from it. UI goroutine only.
+	allServers []domain.Server
+	// agentChecking tracks aliases with a background ssh-agent check in flight; UI goroutine only.
+	agentChecking map[string]bool
 }
 
-func NewTUI(logger *zap.SugaredLogger, ss ports.ServerService, version, commit string) App {
+func NewTUI(logger *zap.SugaredLogger, ss ports.ServerService, cfg config.Config, version, commit string) App {
+	display := newDisplaySettings(cfg, os.Getenv)
+	if display.ascii {
+		useASCIIBorders()
+	}
 	return &tui{
 		logger:        logger,
+		cfg:           cfg,
+		display:       display,
 		app:           tview.NewApplication(),
 		serverService: ss,
 		version:       version,
 		commit:        commit,
+		pinging:       make(map[string]bool),
+		agentChecking: make(map[string]bool),
+		listWidth:     cfg.ListWidth(),
+		stacked:       cfg.Stacked(),
 	}
 }
 
@@ -69,7 +116,15 @@ func (t *tui) Run() error {
 	t.app.EnableMouse(true)
 	t.initializeTheme().buildComponents().buildLayout().bindEvents().loadInitialData()
 	t.app.SetRoot(t.root, true)
+	stop := make(chan struct{})
+	defer close(stop)
+	if interval := t.cfg.ListRefreshInterval(); interval > 0 {
+		go t.refreshPeriodically(interval, stop)
+	}
+	go t.refreshHeaderPeriodically(stop)
+	go t.serverService.WatchRemoteInventory(stop, func() { t.app.QueueUpdateDraw(t.refreshServerList) })
 	t.logger.Infow("starting TUI application", "version", t.version, "commit", t.commit)
+	defer func() { _ = t.flushLayoutPreferences() }() // errors are logged by the flush
 	if err := t.app.Run(); err != nil {
 		t.logger.Errorw("application run error", "error", err)
 		return err
@@ -90,15 +145,21 @@ func (t *tui) initializeTheme() *tui {
 }
 
 func (t *tui) buildComponents() *tui {
-	t.header = NewAppHeader(t.version, t.commit, RepoURL)
-	t.searchBar = NewSearchBar().
+	t.header = NewAppHeader(t.display, t.version, t.commit, RepoURL).
+		SetStatsProvider(t.headerStats)
+	t.searchBar = NewSearchBar(t.display).
 		OnSearch(t.handleSearchInput).
 		OnEscape(t.hideSearchBar)
-	t.hintBar = NewHintBar()
-	t.serverList = NewServerList().
+	t.hintBar = NewHintBar(t.display.enterAction)
+	t.serverList = NewServerList(t.display).
+		SetStaleAfter(t.cfg.StaleAfter()).
 		OnSelectionChange(t.handleServerSelectionChange)
-	t.details = NewServerDetails()
+	t.details = NewServerDetails(t.display)
 	t.statusBar = NewStatusBar()
+	if path, writable := t.serverService.ConfigWritable(); !writable {
+		t.readOnlyConfig = path
+		t.readOnlyBanner = NewReadOnlyBanner(path)
+	}
 
 	// default sort mode
 	t.sortMode = SortByAliasAsc
@@ -111,36 +172,128 @@ func (t *tui) buildLayout() *tui {
 		AddItem(t.hintBar, 1, 0, false).
 		AddItem(t.serverList, 0, 1, true)
 
-	right := tview.NewFlex().SetDirection(tview.FlexRow).
+	t.right = tview.NewFlex().SetDirection(tview.FlexRow).
 		AddItem(t.details, 0, 1, false)
 
-	t.content = tview.NewFlex().SetDirection(tview.FlexColumn).
-		AddItem(t.left, 0, 3, true).
-		AddItem(right, 0, 2, false)
+	t.content = tview.NewFlex().SetDirection(t.contentDirection()).
+		AddItem(t.left, 0, t.listWidth, true).
+		AddItem(t.right, 0, 100-t.listWidth, false)
 
 	t.root = tview.NewFlex().SetDirection(tview.FlexRow).
-		AddItem(t.header, 2, 0, false).
-		AddItem(t.content, 0, 1, true).
+		AddItem(t.header, 2, 0, false)
+	if t.readOnlyBanner != nil {
+		t.root.AddItem(t.readOnlyBanner, 1, 0, false)
+	}
+	t.root.AddItem(t.content, 0, 1, true).
 		AddItem(t.statusBar, 1, 0, false)
 	return t
 }
 
+// contentDirection returns the flex direction that places the details beside the list,
+// or below it in the stacked layout.
+func (t *tui) contentDirection() int {
+	if t.stacked {
+		return tview.FlexRow
+	}
+	return tview.FlexColumn
+}
+
 func (t *tui) bindEvents() *tui {
 	t.root.SetInputCapture(t.handleGlobalKeys)
 	return t
 }
 
 func (t *tui) loadInitialData() *tui {
-	servers, _ := t.serverService.ListServers("")
-	sortServersForUI(servers, t.sortMode)
-	t.updateListTitle()
-	t.serverList.UpdateServers(servers)
+	shown, all, _ := t.loadServers("")
+	t.showLoadedServers(shown, all, "")
+	t.showStorageWarnings()
 
 	return t
 }
 
+// refreshPeriodically reloads the server list every interval until stop is closed, so
+// metadata written by other instances shows up. Ticks are skipped while the details
+// pane is focused to keep its scroll position.
+func (t *tui) refreshPeriodically(interval time.Duration, stop <-chan struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			t.app.QueueUpdateDraw(func() {
+				if t.app.GetFocus() == t.details {
+					return
+				}
+				t.refreshServerList()
+			})
+		}
+	}
+}
+
+// headerStatsInterval is how often the header's activity summary is recomputed, so "today"
+// rolls over at midnight even when nothing else refreshes the list.
+const headerStatsInterval = time.Minute
+
+// refreshHeaderPeriodically recomputes the header's activity summary until stop is closed.
+func (t *tui) refreshHeaderPeriodically(stop <-chan struct{}) {
+	ticker := time.NewTicker(headerStatsInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			t.app.QueueUpdateDraw(t.header.RefreshStats)
+		}
+	}
+}
+
+// headerStats summarizes usage recorded in the metadata of the last loaded servers for the
+// header, without reading them again.
+func (t *tui) headerStats() (HeaderStats, bool) {
+	return computeHeaderStats(t.allServers, time.Now())
+}
+
 func (t *tui) updateListTitle() {
 	if t.serverList != nil {
-		t.serverList.SetTitle(" Servers — Sort: " + t.sortMode.String() + " ")
+		title := " Servers " + formatListCount(t.listShown, t.listTotal, t.listQuery) + " — Sort: " + t.sortMode.String()
+		if t.showResolved {
+			title += " — Ports: resolved"
+		}
+		t.serverList.SetTitle(title + " ")
 	}
 }
+
+// showServers lists servers, found for query out of total, and updates the title's counts.
+func (t *tui) showServers(servers []domain.Server, query string, total int) {
+	t.serverList.UpdateServers(servers)
+	t.listShown, t.listTotal, t.listQuery = len(servers), total, strings.TrimSpace(query)
+	t.updateListTitle()
+}
+
+// loadServers lists the servers found for query together with all servers, reading the
+// unfiltered list once and the filtered one only when a search narrows it.
+func (t *tui) loadServers(query string) (shown, all []domain.Server, err error) {
+	all, err = t.serverService.ListServers("")
+	if err != nil {
+		return nil, nil, err
+	}
+	if strings.TrimSpace(query) == "" {
+		shown = append([]domain.Server(nil), all...)
+	} else if shown, err = t.serverService.ListServers(query); err != nil {
+		return nil, nil, err
+	}
+	sortServersForUI(shown, t.sortMode)
+	return shown, all, nil
+}
+
+// showLoadedServers lists shown, found for query, out of all, drops marks on servers that
+// no longer exist and recomputes the header's activity summary from all.
+func (t *tui) showLoadedServers(shown, all []domain.Server, query string) {
+	t.allServers = all
+	t.serverList.PruneMarked(all)
+	t.showServers(shown, query, len(all))
+	t.header.RefreshStats()
+}
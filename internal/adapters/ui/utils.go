@@ -18,11 +18,13 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/Adembc/lazyssh/internal/core/domain"
 	"github.com/mattn/go-runewidth"
+	"github.com/rivo/tview"
 )
 
 // SSH config value constants
@@ -37,22 +39,27 @@ const (
 	sessionTypeSubsystem = "subsystem"
 )
 
-// renderTagBadgesForList renders up to two colored tag chips for the server list.
+// renderTagBadgesForList renders up to listMaxTags colored tag chips for the server list.
 // If there are more tags, it appends a subtle gray "+N" badge. Returns an empty
-// string when there are no tags to avoid cluttering the list.
-func renderTagBadgesForList(tags []string) string {
-	if len(tags) == 0 {
+// string when there are no tags, or tags are hidden, to avoid cluttering the list.
+func (d displaySettings) renderTagBadgesForList(tags []string) string {
+	return d.renderTagBadges(tags, d.listMaxTags)
+}
+
+// renderTagBadges renders at most maxTags chips (all of them when negative) and a "+N"
+// badge for the rest.
+func (d displaySettings) renderTagBadges(tags []string, maxTags int) string {
+	if len(tags) == 0 || maxTags == 0 {
 		return ""
 	}
-	maxTags := 2
 	shown := tags
-	if len(tags) > maxTags {
+	if maxTags > 0 && len(tags) > maxTags {
 		shown = tags[:maxTags]
 	}
 	parts := make([]string, 0, len(shown)+1)
 	for _, t := range shown {
-		// Light blue background chip, similar to details view.
-		parts = append(parts, fmt.Sprintf("[black:#5FAFFF] %s [-:-:-]", t))
+		// Per-tag colored chip, same as the details view.
+		parts = append(parts, d.renderTagChip(t))
 	}
 	if extra := len(tags) - len(shown); extra > 0 {
 		parts = append(parts, fmt.Sprintf("[#8A8A8A]+%d[-]", extra))
@@ -70,27 +77,53 @@ func cellPad(s string, width int) string {
 	return s + strings.Repeat(" ", width-w)
 }
 
-func pinnedIcon(pinnedAt time.Time) string {
+func (d displaySettings) pinnedIcon(pinnedAt time.Time) string {
 	// Use emojis for a nicer UI; combined with cellPad to keep widths consistent in tview.
 	if pinnedAt.IsZero() {
-		return "📡" // not pinned
+		return d.glyph("📡", ".") // not pinned
 	}
-	return "📌" // pinned
+	return d.glyph("📌", "*") // pinned
 }
 
-func formatServerLine(s domain.Server) (primary, secondary string) {
-	icon := cellPad(pinnedIcon(s.PinnedAt), 2)
-	// Use a consistent color for alias; the icon reflects pinning
-	primary = fmt.Sprintf("%s [white::b]%-12s[-] [#AAAAAA]%-18s[-] [#888888]Last SSH: %s[-]  %s", icon, s.Alias, s.Host, humanizeDuration(s.LastSeen), renderTagBadgesForList(s.Tags))
+func (d displaySettings) formatServerLine(s domain.Server, addr domain.Endpoint, stale, marked bool) (primary, secondary string) {
+	icon := cellPad(d.pinnedIcon(s.PinnedAt), 2)
+	if marked {
+		icon = "[#FFD75F::b]" + d.glyph("✔", "+") + "[-:-:-]" + icon
+	} else {
+		icon = " " + icon
+	}
+	// Use a consistent color for alias; the icon reflects pinning. Stale servers are muted.
+	aliasStyle, hostStyle := "[white::b]", "[#AAAAAA]"
+	if stale {
+		aliasStyle, hostStyle = "[#6C6C6C]", "[#5F5F5F]"
+	}
+	badges := ""
+	if len(s.AutoTunnels) > 0 && !s.AutoTunnelsDisabled {
+		badges = "[#5FAFFF]" + d.glyph("⇄", "=") + "[-] "
+	}
+	if s.RequiresVPN {
+		badges += "[#AF87FF]VPN[-] "
+	}
+	// Pad by display cells rather than runes (as %-12s would) so wide characters such as
+	// CJK aliases keep the following columns aligned.
+	primary = fmt.Sprintf("%s %s%s[-:-:-] %s%s[-] [#888888]Last SSH: %s[-]  %s%s", icon, aliasStyle, cellPad(s.Alias, 12), hostStyle, cellPad(addr.String(), 18), humanizeDuration(s.LastSeen), badges, d.renderTagBadgesForList(s.Tags))
 	secondary = ""
 	return
 }
 
 func humanizeDuration(t time.Time) string {
+	return humanizeDurationAt(t, time.Now())
+}
+
+// humanizeDurationAt renders t relative to now. Recent values use minutes/hours, the previous
+// calendar day is "yesterday", and months/years are counted on the calendar rather than
+// approximated as 30/365 days so the label does not drift.
+func humanizeDurationAt(t, now time.Time) string {
 	if t.IsZero() {
 		return "never"
 	}
-	d := time.Since(t)
+	t = t.In(now.Location())
+	d := now.Sub(t)
 	if d < time.Minute {
 		return "just now"
 	}
@@ -98,31 +131,65 @@ func humanizeDuration(t time.Time) string {
 		m := int(d.Minutes())
 		return fmt.Sprintf("%dm ago", m)
 	}
-	if d < 48*time.Hour {
-		h := int(d.Hours())
-		return fmt.Sprintf("%dh ago", h)
-	}
-	if d < 60*24*time.Hour {
-		days := int(d.Hours()) / 24
+
+	days := calendarDaysBetween(t, now)
+	switch {
+	case days == 0:
+		return fmt.Sprintf("%dh ago", int(d.Hours()))
+	case days == 1:
+		return "yesterday"
+	case days < 60:
 		return fmt.Sprintf("%dd ago", days)
 	}
-	if d < 365*24*time.Hour {
-		months := int(d.Hours()) / (24 * 30)
-		if months < 1 {
-			months = 1
+
+	months := (now.Year()-t.Year())*12 + int(now.Month()) - int(t.Month())
+	if now.Day() < t.Day() {
+		months--
+	}
+	if months < 12 {
+		return fmt.Sprintf("%dmo ago", max(months, 1))
+	}
+	return fmt.Sprintf("%dy ago", months/12)
+}
+
+// calendarDaysBetween returns the number of calendar-day boundaries between t and now (t <= now).
+func calendarDaysBetween(t, now time.Time) int {
+	y1, m1, d1 := t.Date()
+	y2, m2, d2 := now.Date()
+	start := time.Date(y1, m1, d1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(y2, m2, d2, 0, 0, 0, 0, time.UTC)
+	return int(end.Sub(start).Hours() / 24)
+}
+
+// findServer returns the server with the given alias from servers.
+func findServer(servers []domain.Server, alias string) (domain.Server, bool) {
+	for _, s := range servers {
+		if s.Alias == alias {
+			return s, true
 		}
-		return fmt.Sprintf("%dmo ago", months)
 	}
-	years := int(d.Hours()) / (24 * 365)
-	if years < 1 {
-		years = 1
+	return domain.Server{}, false
+}
+
+// connectTarget describes where an SSH connection goes, e.g. "web (ubuntu@10.0.0.5:2222)".
+func connectTarget(s domain.Server) string {
+	host := s.Host
+	if host == "" {
+		return s.Alias
+	}
+	if s.User != "" {
+		host = s.User + "@" + host
+	}
+	if s.Port != 0 && s.Port != 22 {
+		host = fmt.Sprintf("%s:%d", host, s.Port)
 	}
-	return fmt.Sprintf("%dy ago", years)
+	return fmt.Sprintf("%s (%s)", s.Alias, host)
 }
 
-// BuildSSHCommand constructs a ready-to-run ssh command for the given server.
+// BuildSSHCommand constructs a ready-to-run ssh command for the given server, passing
+// defaultIdentityFile as -i when the server has no IdentityFile.
 // Format: ssh [options] [user@]host [command]
-func BuildSSHCommand(s domain.Server) string {
+func BuildSSHCommand(s domain.Server, defaultIdentityFile string) string {
 	parts := []string{"ssh"}
 
 	// Add proxy and connection options
@@ -166,17 +233,21 @@ func BuildSSHCommand(s domain.Server) string {
 		for _, keyFile := range s.IdentityFiles {
 			parts = append(parts, "-i", quoteIfNeeded(keyFile))
 		}
+	} else if defaultIdentityFile != "" {
+		parts = append(parts, "-i", quoteIfNeeded(defaultIdentityFile))
 	}
 
-	// Host specification
-	userHost := ""
-	switch {
-	case s.User != "" && s.Host != "":
-		userHost = fmt.Sprintf("%s@%s", s.User, s.Host)
-	case s.Host != "":
-		userHost = s.Host
-	default:
-		userHost = s.Alias
+	// Host specification; without a HostName ssh connects to the alias itself.
+	userHost := s.Endpoint().Host
+	if s.User != "" {
+		userHost = s.User + "@" + userHost
+	}
+	if strings.Contains(userHost, `\`) {
+		// A DOMAIN\user login would lose its backslash to the shell; single quotes keep it.
+		userHost = "'" + userHost + "'"
+	} else {
+		// An alias used as the host may contain spaces, which Host lines allow when quoted.
+		userHost = quoteIfNeeded(userHost)
 	}
 	parts = append(parts, userHost)
 
@@ -207,12 +278,22 @@ func addQuotedOption(parts *[]string, key, value string) {
 	}
 }
 
-// addProxyOptions adds proxy-related options to the SSH command
+// addProxyOptions adds proxy-related options to the SSH command. ssh rejects -J together
+// with ProxyCommand, so only the ProxyJump is passed when both are set: lazyssh writes
+// ProxyJump first in the Host block, where it also wins over ProxyCommand.
 func addProxyOptions(parts *[]string, s domain.Server) {
 	if s.ProxyJump != "" {
 		*parts = append(*parts, "-J", quoteIfNeeded(s.ProxyJump))
 	}
-	addQuotedOption(parts, "ProxyCommand", s.ProxyCommand)
+	if !proxyCommandShadowed(s) {
+		addQuotedOption(parts, "ProxyCommand", s.ProxyCommand)
+	}
+}
+
+// proxyCommandShadowed reports whether both ProxyJump and ProxyCommand are set, in which
+// case copied commands use ProxyJump and drop ProxyCommand.
+func proxyCommandShadowed(s domain.Server) bool {
+	return s.ProxyJump != "" && s.ProxyCommand != ""
 }
 
 // addConnectionTimingOptions adds connection timing options to the SSH command
@@ -225,7 +306,14 @@ func addConnectionTimingOptions(parts *[]string, s domain.Server) {
 	if s.BindInterface != "" {
 		*parts = append(*parts, "-B", s.BindInterface)
 	}
-	addOption(parts, "AddressFamily", s.AddressFamily)
+	switch s.AddressFamily {
+	case "inet":
+		*parts = append(*parts, "-4")
+	case "inet6":
+		*parts = append(*parts, "-6")
+	default:
+		addOption(parts, "AddressFamily", s.AddressFamily)
+	}
 	addOption(parts, "IPQoS", s.IPQoS)
 	// Hostname canonicalization options
 	addOption(parts, "CanonicalizeHostname", s.CanonicalizeHostname)
@@ -467,6 +555,18 @@ func quoteIfNeeded(val string) string {
 	return val
 }
 
+// expandHomePath expands a leading "~/" (or a bare "~") to the user's home directory.
+func expandHomePath(path string) string {
+	if path != "~" && !strings.HasPrefix(path, "~/") {
+		return path
+	}
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return path
+	}
+	return filepath.Join(homeDir, path[1:])
+}
+
 // GetAvailableSSHKeys returns a list of available SSH private key files in the user's .ssh directory.
 // It safely handles file permission issues and only returns readable key files.
 func GetAvailableSSHKeys() []string {
@@ -632,3 +732,43 @@ func GetAvailableKnownHostsFiles() []string {
 
 	return files
 }
+
+// parseUserHostPort splits the user@host:port shorthand. user and port are empty/zero when
+// absent; a bracketed IPv6 address ([::1]:2222) may carry a port, a bare one never does.
+func parseUserHostPort(s string) (user, host string, port int) {
+	host = strings.TrimSpace(s)
+	if i := strings.LastIndex(host, "@"); i >= 0 {
+		user, host = host[:i], host[i+1:]
+	}
+
+	portText := ""
+	switch {
+	case strings.HasPrefix(host, "["):
+		end := strings.Index(host, "]")
+		if end < 0 {
+			return user, host, 0
+		}
+		if rest := host[end+1:]; strings.HasPrefix(rest, ":") {
+			portText = rest[1:]
+		}
+		host = host[1:end]
+	case strings.Count(host, ":") == 1:
+		i := strings.Index(host, ":")
+		host, portText = host[:i], host[i+1:]
+	}
+	if portText != "" {
+		if p, err := strconv.Atoi(portText); err == nil && p > 0 && p <= 65535 {
+			port = p
+		}
+	}
+	return user, host, port
+}
+
+// formatListCount renders the list title's count: "(142)", or "· Showing 7 of 142 · web"
+// while a search narrows the list.
+func formatListCount(shown, total int, query string) string {
+	if query == "" {
+		return fmt.Sprintf("(%d)", total)
+	}
+	return fmt.Sprintf("· Showing %d of %d · %s", shown, total, tview.Escape(query))
+}
@@ -17,8 +17,11 @@ package ui
 import (
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/Adembc/lazyssh/internal/config"
 	"github.com/Adembc/lazyssh/internal/core/domain"
+	"github.com/mattn/go-runewidth"
 )
 
 func TestBuildSSHCommand_PortForwarding(t *testing.T) {
@@ -106,7 +109,7 @@ func TestBuildSSHCommand_PortForwarding(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result := BuildSSHCommand(tt.server)
+			result := BuildSSHCommand(tt.server, "")
 
 			// Check that all expected parts are in the result
 			for _, part := range tt.expected {
@@ -135,7 +138,7 @@ func TestBuildSSHCommand_CompleteCommand(t *testing.T) {
 		IdentityFiles:  []string{"~/.ssh/id_rsa"},
 	}
 
-	result := BuildSSHCommand(server)
+	result := BuildSSHCommand(server, "")
 
 	// Check command structure
 	if !strings.HasPrefix(result, "ssh ") {
@@ -171,3 +174,380 @@ func TestBuildSSHCommand_CompleteCommand(t *testing.T) {
 		t.Errorf("Command should contain 'admin@example.com', got: %q", result)
 	}
 }
+
+func TestBuildSSHCommand_DefaultIdentityFile(t *testing.T) {
+	withoutKey := BuildSSHCommand(domain.Server{Alias: "a", Host: "example.com"}, "~/.ssh/id_default")
+	if !strings.Contains(withoutKey, "-i ~/.ssh/id_default") {
+		t.Errorf("Command without a key should use the default identity file, got: %q", withoutKey)
+	}
+
+	withKey := BuildSSHCommand(domain.Server{Alias: "b", Host: "example.com", IdentityFiles: []string{"~/.ssh/id_rsa"}}, "~/.ssh/id_default")
+	if strings.Contains(withKey, "id_default") {
+		t.Errorf("Command with its own key should not add the default, got: %q", withKey)
+	}
+}
+
+func TestBuildSSHCommand_DomainUser(t *testing.T) {
+	got := BuildSSHCommand(domain.Server{Alias: "ad", Host: "dc.example.com", User: `EXAMPLE\jdoe`}, "")
+	if want := `ssh 'EXAMPLE\jdoe@dc.example.com'`; got != want {
+		t.Errorf("BuildSSHCommand() = %q, want %q", got, want)
+	}
+}
+
+func TestBuildSSHCommand_StrictHostKeyChecking(t *testing.T) {
+	got := BuildSSHCommand(domain.Server{Alias: "lab", Host: "10.0.0.9", StrictHostKeyChecking: "accept-new"}, "")
+	if want := "ssh -o StrictHostKeyChecking=accept-new 10.0.0.9"; got != want {
+		t.Errorf("BuildSSHCommand() = %q, want %q", got, want)
+	}
+}
+
+func TestBuildSSHCommand_ProxyCommand(t *testing.T) {
+	got := BuildSSHCommand(domain.Server{Alias: "app", Host: "10.0.0.5", ProxyCommand: "ssh -W %h:%p bastion"}, "")
+	if want := `ssh -o ProxyCommand="ssh -W %h:%p bastion" 10.0.0.5`; got != want {
+		t.Errorf("BuildSSHCommand() = %q, want %q", got, want)
+	}
+
+	// ssh refuses -J together with ProxyCommand, so ProxyJump wins.
+	got = BuildSSHCommand(domain.Server{Alias: "app", Host: "10.0.0.5", ProxyJump: "bastion", ProxyCommand: "nc %h %p"}, "")
+	if want := "ssh -J bastion 10.0.0.5"; got != want {
+		t.Errorf("BuildSSHCommand() with both = %q, want %q", got, want)
+	}
+}
+
+func TestAliasOnlyHost(t *testing.T) {
+	server := domain.Server{Alias: "web.internal", Aliases: []string{"web.internal"}, User: "deploy", Port: 2222}
+	if got, want := BuildSSHCommand(server, ""), "ssh -p 2222 deploy@web.internal"; got != want {
+		t.Errorf("BuildSSHCommand() = %q, want %q", got, want)
+	}
+	if got, want := BuildSSHCommand(domain.Server{Alias: "web"}, ""), "ssh web"; got != want {
+		t.Errorf("BuildSSHCommand() = %q, want %q", got, want)
+	}
+	if got, want := BuildSSHCommand(domain.Server{Alias: "my box", User: "me"}, ""), `ssh "me@my box"`; got != want {
+		t.Errorf("BuildSSHCommand() with a spaced alias = %q, want %q", got, want)
+	}
+
+	sd := NewServerDetails(displaySettings{})
+	sd.UpdateServer(server)
+	if text := sd.GetText(true); !strings.Contains(text, "Host: (uses alias)") {
+		t.Errorf("details of an alias-only host =\n%s", text)
+	}
+}
+
+func TestBuildSSHCommand_AddressFamily(t *testing.T) {
+	tests := []struct {
+		server domain.Server
+		want   string
+	}{
+		{domain.Server{Host: "h", AddressFamily: "inet"}, "ssh -4 h"},
+		{domain.Server{Host: "h", AddressFamily: "inet6", BindAddress: "::1"}, "ssh -b ::1 -6 h"},
+		{domain.Server{Host: "h", AddressFamily: "any"}, "ssh -o AddressFamily=any h"},
+	}
+	for _, tt := range tests {
+		if got := BuildSSHCommand(tt.server, ""); got != tt.want {
+			t.Errorf("BuildSSHCommand(%+v) = %q, want %q", tt.server, got, tt.want)
+		}
+	}
+}
+
+func TestInsecureSetting(t *testing.T) {
+	tests := []struct {
+		name, value string
+		want        bool
+	}{
+		{"StrictHostKeyChecking", "no", true},
+		{"StrictHostKeyChecking", "Off", true},
+		{"StrictHostKeyChecking", "accept-new", false},
+		{"StrictHostKeyChecking", "ask", false},
+		{"CheckHostIP", "no", false},
+	}
+	for _, tt := range tests {
+		if got := insecureSetting(tt.name, tt.value); got != tt.want {
+			t.Errorf("insecureSetting(%q, %q) = %v, want %v", tt.name, tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestHumanizeDurationAt(t *testing.T) {
+	now := time.Date(2025, time.March, 15, 14, 30, 0, 0, time.UTC)
+	tests := []struct {
+		name     string
+		t        time.Time
+		expected string
+	}{
+		{name: "zero", t: time.Time{}, expected: "never"},
+		{name: "seconds", t: now.Add(-30 * time.Second), expected: "just now"},
+		{name: "minutes", t: now.Add(-5 * time.Minute), expected: "5m ago"},
+		{name: "same day hours", t: now.Add(-3 * time.Hour), expected: "3h ago"},
+		{name: "late yesterday", t: time.Date(2025, time.March, 14, 23, 0, 0, 0, time.UTC), expected: "yesterday"},
+		{name: "early yesterday", t: time.Date(2025, time.March, 14, 1, 0, 0, 0, time.UTC), expected: "yesterday"},
+		{name: "days", t: time.Date(2025, time.March, 10, 12, 0, 0, 0, time.UTC), expected: "5d ago"},
+		{name: "59 days", t: now.AddDate(0, 0, -59), expected: "59d ago"},
+		{name: "calendar months", t: time.Date(2024, time.December, 15, 0, 0, 0, 0, time.UTC), expected: "3mo ago"},
+		{name: "month not yet complete", t: time.Date(2024, time.December, 20, 0, 0, 0, 0, time.UTC), expected: "2mo ago"},
+		{name: "just under a year", t: time.Date(2024, time.March, 20, 0, 0, 0, 0, time.UTC), expected: "11mo ago"},
+		{name: "one year", t: time.Date(2024, time.March, 15, 0, 0, 0, 0, time.UTC), expected: "1y ago"},
+		{name: "several years", t: time.Date(2021, time.January, 1, 0, 0, 0, 0, time.UTC), expected: "4y ago"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := humanizeDurationAt(tt.t, now); got != tt.expected {
+				t.Errorf("humanizeDurationAt(%v) = %q, want %q", tt.t, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestTagColor(t *testing.T) {
+	var d displaySettings
+	if d.tagColor("prod") != d.tagColor("prod") {
+		t.Error("tagColor should be stable for the same tag")
+	}
+	if d.tagColor("Prod") != d.tagColor("prod") {
+		t.Error("tagColor should ignore case")
+	}
+
+	d.tagColors = parseTagColors(map[string]string{"Prod": "#FF0000", "dev": "green", "bad": "[red]"})
+	if got := d.tagColor("prod"); got != "#FF0000" {
+		t.Errorf("override for prod = %q, want #FF0000", got)
+	}
+	if got := d.tagColor("dev"); got != "green" {
+		t.Errorf("override for dev = %q, want green", got)
+	}
+	if got := d.tagColor("bad"); got == "[red]" {
+		t.Error("invalid override should be ignored")
+	}
+}
+
+func TestConnectTarget(t *testing.T) {
+	tests := []struct {
+		server domain.Server
+		want   string
+	}{
+		{domain.Server{Alias: "web"}, "web"},
+		{domain.Server{Alias: "web", Host: "10.0.0.5"}, "web (10.0.0.5)"},
+		{domain.Server{Alias: "web", Host: "10.0.0.5", User: "ubuntu", Port: 22}, "web (ubuntu@10.0.0.5)"},
+		{domain.Server{Alias: "web", Host: "10.0.0.5", User: "ubuntu", Port: 2222}, "web (ubuntu@10.0.0.5:2222)"},
+	}
+	for _, tt := range tests {
+		if got := connectTarget(tt.server); got != tt.want {
+			t.Errorf("connectTarget(%+v) = %q, want %q", tt.server, got, tt.want)
+		}
+	}
+}
+
+func TestFormatListCount(t *testing.T) {
+	tests := []struct {
+		shown, total int
+		query        string
+		want         string
+	}{
+		{142, 142, "", "(142)"},
+		{7, 142, "web", "· Showing 7 of 142 · web"},
+		{0, 142, "host:prod [x]", "· Showing 0 of 142 · host:prod [x[]"},
+	}
+	for _, tt := range tests {
+		if got := formatListCount(tt.shown, tt.total, tt.query); got != tt.want {
+			t.Errorf("formatListCount(%d, %d, %q) = %q, want %q", tt.shown, tt.total, tt.query, got, tt.want)
+		}
+	}
+}
+
+func TestRenderLatency(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		name  string
+		res   domain.PingResult
+		ok    bool
+		vpn   bool
+		color string
+	}{
+		{name: "unknown", ok: false, color: "#888888"},
+		{name: "fast", res: domain.PingResult{Up: true, Latency: 12 * time.Millisecond, CheckedAt: now}, ok: true, color: "#A0FFA0"},
+		{name: "medium", res: domain.PingResult{Up: true, Latency: 120 * time.Millisecond, CheckedAt: now}, ok: true, color: "#FFD75F"},
+		{name: "slow", res: domain.PingResult{Up: true, Latency: 450 * time.Millisecond, CheckedAt: now}, ok: true, color: "#FF6B6B"},
+		{name: "down", res: domain.PingResult{CheckedAt: now}, ok: true, color: "#FF6B6B"},
+		{name: "vpn unreachable", res: domain.PingResult{CheckedAt: now}, ok: true, vpn: true, color: "#FFD75F"},
+		{name: "vpn up", res: domain.PingResult{Up: true, Latency: 12 * time.Millisecond, CheckedAt: now}, ok: true, vpn: true, color: "#A0FFA0"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := renderLatency(tt.res, tt.ok, tt.vpn); !strings.HasPrefix(got, "["+tt.color+"]") {
+				t.Errorf("renderLatency() = %q, want color %s", got, tt.color)
+			}
+		})
+	}
+}
+
+func TestRenderSparkline(t *testing.T) {
+	up := func(ms int) domain.PingResult {
+		return domain.PingResult{Up: true, Latency: time.Duration(ms) * time.Millisecond}
+	}
+	down := domain.PingResult{}
+	tests := []struct {
+		name    string
+		history []domain.PingResult
+		want    string
+	}{
+		{"scaled to range", []domain.PingResult{up(10), up(80), up(45), up(10)}, "▁█▄▁ 10ms–80ms"},
+		{"flat", []domain.PingResult{up(20), up(20)}, "▁▁ 20ms–20ms"},
+		{"failures", []domain.PingResult{up(10), down, up(30)}, "▁×█ 10ms–30ms"},
+		{"all down", []domain.PingResult{down, down}, "××"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := stripTags(displaySettings{}.renderSparkline(tt.history)); got != tt.want {
+				t.Errorf("renderSparkline() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSetPingKeepsRecentHistory(t *testing.T) {
+	sd := NewServerDetails(displaySettings{})
+	start := time.Now()
+	for i := 1; i <= pingHistorySize+5; i++ {
+		sd.SetPing("web", domain.PingResult{Up: true, Latency: time.Duration(i), CheckedAt: start.Add(time.Duration(i) * time.Second)})
+	}
+	// A cached result comes back with the same CheckedAt and is not recorded again.
+	sd.SetPing("web", sd.pings["web"])
+	history := sd.pingHistory["web"]
+	if len(history) != pingHistorySize {
+		t.Fatalf("history length = %d, want %d", len(history), pingHistorySize)
+	}
+	if history[0].Latency != 6 || history[len(history)-1].Latency != pingHistorySize+5 {
+		t.Errorf("history = %v..%v, want the most recent results", history[0].Latency, history[len(history)-1].Latency)
+	}
+}
+
+func TestParseUserHostPort(t *testing.T) {
+	tests := []struct {
+		in         string
+		user, host string
+		port       int
+	}{
+		{"10.0.0.5", "", "10.0.0.5", 0},
+		{"ubuntu@10.0.0.5", "ubuntu", "10.0.0.5", 0},
+		{"ubuntu@10.0.0.5:2222", "ubuntu", "10.0.0.5", 2222},
+		{" host.example.com:22 ", "", "host.example.com", 22},
+		{"deploy@[2001:db8::1]:2200", "deploy", "2001:db8::1", 2200},
+		{"2001:db8::1", "", "2001:db8::1", 0},
+		{"web:notaport", "", "web", 0},
+	}
+	for _, tt := range tests {
+		user, host, port := parseUserHostPort(tt.in)
+		if user != tt.user || host != tt.host || port != tt.port {
+			t.Errorf("parseUserHostPort(%q) = (%q, %q, %d), want (%q, %q, %d)", tt.in, user, host, port, tt.user, tt.host, tt.port)
+		}
+	}
+}
+
+func TestRenderTagBadges(t *testing.T) {
+	tags := []string{"prod", "db", "eu", "backup"}
+	tests := []struct {
+		name      string
+		max       int
+		wantChips int
+		wantExtra string
+	}{
+		{name: "default cap", max: 2, wantChips: 2, wantExtra: "+2"},
+		{name: "hidden", max: 0, wantChips: 0},
+		{name: "all", max: -1, wantChips: 4},
+		{name: "cap above count", max: 10, wantChips: 4},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := displaySettings{}.renderTagBadges(tags, tt.max)
+			chips := 0
+			for _, tag := range tags {
+				if strings.Contains(got, tag) {
+					chips++
+				}
+			}
+			if chips != tt.wantChips {
+				t.Errorf("renderTagBadges(%d) shows %d chips, want %d: %q", tt.max, chips, tt.wantChips, got)
+			}
+			if hasExtra := strings.Contains(got, "+"); hasExtra != (tt.wantExtra != "") || (hasExtra && !strings.Contains(got, tt.wantExtra)) {
+				t.Errorf("renderTagBadges(%d) = %q, want extra badge %q", tt.max, got, tt.wantExtra)
+			}
+		})
+	}
+}
+
+func TestFindServer(t *testing.T) {
+	servers := []domain.Server{{Alias: "web", Host: "10.0.0.1"}, {Alias: "db", Host: "10.0.0.2"}}
+	if got, ok := findServer(servers, "db"); !ok || got.Host != "10.0.0.2" {
+		t.Errorf("findServer(db) = %+v, %v; want host 10.0.0.2", got, ok)
+	}
+	if _, ok := findServer(servers, "gone"); ok {
+		t.Error("findServer(gone) found a server")
+	}
+}
+
+func TestFormatServerLineAlignsWideCharacters(t *testing.T) {
+	servers := []domain.Server{
+		{Alias: "web", Tags: []string{"prod"}},
+		{Alias: "生产服务器", Tags: []string{"生产", "数据库"}},
+		{Alias: "db", Tags: []string{"日本語タグ"}, PinnedAt: time.Now()},
+	}
+	endpoints := []domain.Endpoint{{Host: "10.0.0.1"}, {Host: "例え.jp"}, {Host: "db.internal"}}
+
+	d := displaySettings{listMaxTags: config.DefaultListMaxTags}
+	want := -1
+	for i, s := range servers {
+		line, _ := d.formatServerLine(s, endpoints[i], false, false)
+		text := stripTags(line)
+		idx := strings.Index(text, "Last SSH")
+		if idx < 0 {
+			t.Fatalf("no Last SSH column in %q", text)
+		}
+		col := runewidth.StringWidth(text[:idx])
+		if want < 0 {
+			want = col
+		} else if col != want {
+			t.Errorf("%s: Last SSH starts at cell %d, want %d (%q)", s.Alias, col, want, text)
+		}
+	}
+}
+
+func TestExpandProxyTokens(t *testing.T) {
+	server := domain.Server{Alias: "app", Host: "10.0.0.5", User: "deploy", Port: 2222}
+	tests := []struct {
+		name    string
+		command string
+		server  domain.Server
+		want    string
+	}{
+		{"netcat style", "ssh -W %h:%p bastion", server, "ssh -W 10.0.0.5:2222 bastion"},
+		{"user and alias", "connect %r@%n", server, "connect deploy@app"},
+		{"literal percent", "printf 100%% %x", server, "printf 100% %x"},
+		{"defaults", "nc %h %p as %r", domain.Server{Alias: "box"}, "nc box 22 as %r"},
+		{"trailing percent", "echo %", server, "echo %"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := expandProxyTokens(tt.command, tt.server); got != tt.want {
+				t.Errorf("expandProxyTokens(%q) = %q, want %q", tt.command, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRenderIdentityFilesAgent(t *testing.T) {
+	sd := NewServerDetails(displaySettings{})
+	files := []string{"~/.ssh/id_ed25519", "~/.ssh/deploy"}
+	if got := stripTags(sd.renderIdentityFiles(files)); got != "~/.ssh/id_ed25519, ~/.ssh/deploy" {
+		t.Errorf("unchecked keys = %q", got)
+	}
+
+	sd.SetAgentKey("~/.ssh/id_ed25519", true)
+	sd.SetAgentKey("~/.ssh/deploy", false)
+	if got := stripTags(sd.renderIdentityFiles(files)); got != "~/.ssh/id_ed25519 ✓ in agent, ~/.ssh/deploy not in agent" {
+		t.Errorf("checked keys = %q", got)
+	}
+
+	sd.SetAgentDown()
+	if got := stripTags(sd.renderIdentityFiles(files)); got != "~/.ssh/id_ed25519, ~/.ssh/deploy (no ssh-agent)" {
+		t.Errorf("agent down = %q", got)
+	}
+}
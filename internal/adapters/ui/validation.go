@@ -136,8 +136,8 @@ func GetFieldValidators() map[string]fieldValidator {
 	// Basic fields
 	validators["Alias"] = fieldValidator{
 		Required: true,
-		Pattern:  regexp.MustCompile(`^[a-zA-Z0-9._-]+$`),
-		Message:  "Alias is required and can only contain letters, numbers, dots, hyphens, and underscores",
+		Pattern:  regexp.MustCompile(`^[a-zA-Z0-9._-]+( [a-zA-Z0-9._-]+)*$`),
+		Message:  "Alias is required and can only contain letters, numbers, dots, hyphens, underscores, and single spaces",
 	}
 	validators["Host"] = fieldValidator{
 		Required: true,
@@ -150,8 +150,12 @@ func GetFieldValidators() map[string]fieldValidator {
 		Message:  "Port must be between 1 and 65535",
 	}
 	validators["User"] = fieldValidator{
-		Pattern: regexp.MustCompile(`^[a-zA-Z][a-zA-Z0-9._-]*$`),
-		Message: "User must start with a letter and contain only letters, numbers, dots, hyphens, and underscores",
+		Pattern: regexp.MustCompile(`^([a-zA-Z0-9._-]+\\)?[a-zA-Z][a-zA-Z0-9._-]*$`),
+		Message: "User must start with a letter and contain only letters, numbers, dots, hyphens, and underscores, optionally after a DOMAIN\\ prefix",
+	}
+	validators["PingTarget"] = fieldValidator{
+		Validate: validatePingTarget,
+		Message:  "Ping target must be host:port, e.g. 10.0.0.5:443",
 	}
 	validators["Keys"] = fieldValidator{
 		Validate: validateKeyPaths,
@@ -242,6 +246,23 @@ func validatePort(value string) error {
 	return nil
 }
 
+// validatePingTarget validates a host:port ping address; IPv6 hosts are written [addr]:port.
+func validatePingTarget(value string) error {
+	host, port, err := net.SplitHostPort(value)
+	if err != nil {
+		return fmt.Errorf("expected host:port")
+	}
+	if net.ParseIP(host) == nil {
+		if err := validateHost(host); err != nil {
+			return err
+		}
+	}
+	if port == "" {
+		return fmt.Errorf("port is required")
+	}
+	return validatePort(port)
+}
+
 // validateConnectTimeout validates connection timeout
 func validateConnectTimeout(value string) error {
 	if value == "" || value == "none" {
@@ -49,6 +49,26 @@ func TestValidateHost(t *testing.T) {
 	}
 }
 
+func TestValidatePingTarget(t *testing.T) {
+	tests := []struct {
+		target  string
+		wantErr bool
+	}{
+		{"10.0.0.5:443", false},
+		{"health.example.com:8080", false},
+		{"[2001:db8::1]:80", false},
+		{"10.0.0.5", true},
+		{"10.0.0.5:", true},
+		{"10.0.0.5:70000", true},
+		{"bad host:80", true},
+	}
+	for _, tt := range tests {
+		if err := validatePingTarget(tt.target); (err != nil) != tt.wantErr {
+			t.Errorf("validatePingTarget(%q) error = %v, wantErr %v", tt.target, err, tt.wantErr)
+		}
+	}
+}
+
 func TestValidatePortForward(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -195,6 +215,8 @@ func TestFieldValidatorPatterns(t *testing.T) {
 		{"Alias", "server_01", false},
 		{"Alias", "server.01", false},
 		{"Alias", "server@01", true},
+		{"Alias", "staging box", false}, // written quoted to the config
+		{"Alias", " staging", true},
 		{"Alias", "", true}, // Required field
 
 		// Port field
@@ -209,6 +231,11 @@ func TestFieldValidatorPatterns(t *testing.T) {
 		{"User", "user_name", false},
 		{"User", "user-name", false},
 		{"User", "1user", true}, // Can't start with number
+		{"User", `EXAMPLE\jdoe`, false},
+		{"User", `corp.example\j.doe`, false},
+		{"User", `EXAMPLE\`, true},
+		{"User", `\jdoe`, true},
+		{"User", `a\b\c`, true},
 
 		// ConnectTimeout field
 		{"ConnectTimeout", "none", false},
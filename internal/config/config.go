@@ -0,0 +1,401 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const (
+	DefaultPingCacheTTLSeconds = 10
+	DefaultStaleAfterDays      = 90
+	DefaultPingTimeoutMS       = 3000
+	DefaultListMaxTags         = 2
+	// DefaultRemoteInventoryTTLMinutes is how long a fetched remote inventory is reused.
+	DefaultRemoteInventoryTTLMinutes = 60
+	// DefaultConnectRetryBackoffMS is the wait before the first connection retry; it doubles per retry.
+	DefaultConnectRetryBackoffMS = 1000
+	// DefaultListWidthPercent is the list's share of the window next to the details pane (3:2).
+	DefaultListWidthPercent = 60
+	MinListWidthPercent     = 20
+	MaxListWidthPercent     = 80
+	// MinPingTimeoutMS is the smallest ping timeout honoured; lower values are raised to it.
+	MinPingTimeoutMS = 100
+
+	// LayoutSideBySide puts the details pane right of the list; LayoutStacked puts it below.
+	LayoutSideBySide = "side"
+	LayoutStacked    = "stacked"
+
+	MetadataBackendJSON   = "json"
+	MetadataBackendSQLite = "sqlite"
+
+	// EnterActionConnect, EnterActionEdit and EnterActionDetails are what Enter does on a server.
+	EnterActionConnect = "connect"
+	EnterActionEdit    = "edit"
+	EnterActionDetails = "details"
+)
+
+// Config holds user preferences read from ~/.lazyssh/config.json.
+// Every field is optional; missing fields fall back to the defaults from Default().
+type Config struct {
+	// PingCacheTTLSeconds is how long a ping result is reused before the host is pinged again.
+	// Zero disables caching.
+	PingCacheTTLSeconds int `json:"ping_cache_ttl_seconds"`
+
+	// PingTimeoutMS is how long a ping waits for the SSH port to accept a connection.
+	PingTimeoutMS int `json:"ping_timeout_ms"`
+
+	// PingOnSelect pings the selected server in the background when no recent result is cached.
+	PingOnSelect bool `json:"ping_on_select"`
+
+	// ListRefreshSeconds reloads the server list this often so changes made by other lazyssh
+	// instances (connection counts, last seen) show up. Zero disables it.
+	ListRefreshSeconds int `json:"list_refresh_seconds"`
+
+	// ConnectRetries is how many times an SSH connection is retried after a network failure
+	// (refused, timed out, unreachable) within its first seconds. Zero disables retries.
+	ConnectRetries int `json:"connect_retries"`
+
+	// ConnectRetryBackoffMS is the wait before the first retry; each further retry doubles it.
+	ConnectRetryBackoffMS int `json:"connect_retry_backoff_ms"`
+
+	// RelativeIdentityPaths writes IdentityFile paths under the home directory as ~/... in the SSH config.
+	RelativeIdentityPaths bool `json:"relative_identity_paths"`
+
+	// SortConfigOnWrite keeps concrete Host entries sorted by alias whenever lazyssh saves the
+	// SSH config. Wildcard entries stay in place and are not sorted across.
+	SortConfigOnWrite bool `json:"sort_config_on_write"`
+
+	// AnnotateManagedHosts adds an "# Added by lazyssh" comment to the Host lines lazyssh creates.
+	AnnotateManagedHosts bool `json:"annotate_managed_hosts"`
+
+	// DefaultIdentityFile is used for servers without an IdentityFile: it is added as -i to
+	// the sessions lazyssh starts and to copied commands, and prefilled for new servers.
+	// Empty relies on ssh's own defaults.
+	DefaultIdentityFile string `json:"default_identity_file"`
+
+	// SessionLogging copies the output of every SSH session to a log file in SessionLogDir.
+	// Servers can override it individually.
+	SessionLogging bool `json:"session_logging"`
+
+	// SessionLogDir is where session logs are written; empty means ~/.lazyssh/logs.
+	SessionLogDir string `json:"session_log_dir"`
+
+	// MetadataBackend selects where tags, pins and usage stats are stored: "json" or "sqlite".
+	MetadataBackend string `json:"metadata_backend"`
+
+	// MetadataInConfig keeps tags and pins as "# lazyssh-tags:" and "# lazyssh-pinned:" comments
+	// in each Host entry instead of the metadata backend, so the SSH config carries them along.
+	MetadataInConfig bool `json:"metadata_in_config"`
+
+	// StaleAfterDays marks servers not connected to within this many days as stale. Zero disables it.
+	StaleAfterDays int `json:"stale_after_days"`
+
+	// ListMaxTags is how many tag chips a list row shows before a "+N" badge. Zero hides
+	// tags in the list; negative values show every tag.
+	ListMaxTags int `json:"list_max_tags"`
+
+	// ListWidthPercent is the server list's share of the window (its width, or its height in
+	// the stacked layout); the details pane gets the rest. Adjusted with < and > in the TUI,
+	// and clamped to 20-80.
+	ListWidthPercent int `json:"list_width_percent"`
+
+	// Layout is "side" (details beside the list) or "stacked" (details below it). Toggled with v.
+	Layout string `json:"layout"`
+
+	// EnterAction is what Enter does on the selected server: "connect", "edit" or "details",
+	// which focuses the details pane so a second Enter connects.
+	EnterAction string `json:"enter_action"`
+
+	// ClipboardOSC52 makes copy actions set the clipboard through the terminal with an OSC 52
+	// escape sequence instead of xclip/pbcopy, so copying works when lazyssh runs on a
+	// remote host over SSH (tmux needs allow-passthrough).
+	ClipboardOSC52 bool `json:"clipboard_osc52"`
+
+	// ASCIIMode draws the UI with ASCII instead of emoji and box-drawing characters, for
+	// limited terminals and serial consoles. Unset detects it from LANG/LC_* and TERM.
+	ASCIIMode *bool `json:"ascii_mode"`
+
+	// RemoteInventoryURL is an https:// URL of a shared YAML inventory (the lazyssh export
+	// format) whose servers are listed read-only next to the SSH config's. Empty disables it.
+	RemoteInventoryURL string `json:"remote_inventory_url"`
+
+	// RemoteInventoryHeaders are sent with the inventory request, e.g. {"Authorization":
+	// "Bearer ${TEAM_TOKEN}"}; $VAR and ${VAR} are expanded from the environment.
+	RemoteInventoryHeaders map[string]string `json:"remote_inventory_headers"`
+
+	// RemoteInventoryTTLMinutes is how long a fetched inventory is used before it is fetched
+	// again. Unset or non-positive values use the default.
+	RemoteInventoryTTLMinutes int `json:"remote_inventory_ttl_minutes"`
+
+	// TagColors assigns fixed chip colors (#RRGGBB or a color name) to tags, e.g. {"prod": "#FF0000"}.
+	// Tags without an entry get a stable color derived from their name.
+	TagColors map[string]string `json:"tag_colors"`
+
+	// path is the file the config was loaded from, where preferences changed in the TUI are saved.
+	path string
+}
+
+// Default returns the configuration used when no config file is present.
+func Default() Config {
+	return Config{
+		PingCacheTTLSeconds:   DefaultPingCacheTTLSeconds,
+		PingTimeoutMS:         DefaultPingTimeoutMS,
+		RelativeIdentityPaths: true,
+		AnnotateManagedHosts:  true,
+		MetadataBackend:       MetadataBackendJSON,
+		StaleAfterDays:        DefaultStaleAfterDays,
+		ListMaxTags:           DefaultListMaxTags,
+		ListWidthPercent:      DefaultListWidthPercent,
+		Layout:                LayoutSideBySide,
+		EnterAction:           EnterActionConnect,
+	}
+}
+
+// Load reads the config file at path and merges it over the defaults: keys absent
+// from the file keep their default value.
+// A missing file is not an error and yields Default().
+func Load(path string) (Config, error) {
+	cfg := Default()
+	cfg.path = path
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return cfg, nil
+		}
+		return cfg, fmt.Errorf("read config '%s': %w", path, err)
+	}
+
+	if len(data) == 0 {
+		return cfg, nil
+	}
+
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		cfg = Default()
+		cfg.path = path
+		return cfg, fmt.Errorf("parse config JSON '%s': %w", path, err)
+	}
+
+	return cfg, nil
+}
+
+// Path returns the file the config was loaded from; empty for Default().
+func (c Config) Path() string {
+	return c.path
+}
+
+// SetValue stores key in the config file at path, leaving the other keys untouched so
+// defaults are not written out. Only the value of key is rewritten, or a new key appended,
+// so the user's key order and formatting survive. The file and its directory are created
+// when missing, and the file is replaced atomically.
+func SetValue(path, key string, value any) error {
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("read config '%s': %w", path, err)
+	}
+
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("encode %s: %w", key, err)
+	}
+	out, err := setJSONKey(data, key, raw)
+	if err != nil {
+		return fmt.Errorf("parse config JSON '%s': %w", path, err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		return fmt.Errorf("create config dir: %w", err)
+	}
+	if err := writeFileAtomic(path, out); err != nil {
+		return fmt.Errorf("write config '%s': %w", path, err)
+	}
+	return nil
+}
+
+// setJSONKey returns the JSON object in data with key set to raw. An existing value is
+// replaced in place; a new key is appended after the last one. Empty data yields a new object.
+func setJSONKey(data []byte, key string, raw json.RawMessage) ([]byte, error) {
+	if len(bytes.TrimSpace(data)) == 0 {
+		return fmt.Appendf(nil, "{\n  %q: %s\n}\n", key, raw), nil
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	if tok, err := dec.Token(); err != nil || tok != json.Delim('{') {
+		return nil, fmt.Errorf("config is not a JSON object")
+	}
+	valueStart, valueEnd, lastEnd := -1, -1, -1
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, err
+		}
+		afterKey := int(dec.InputOffset())
+		var value json.RawMessage
+		if err := dec.Decode(&value); err != nil {
+			return nil, err
+		}
+		lastEnd = int(dec.InputOffset())
+		if tok == key {
+			// The value follows the colon and any whitespace.
+			colon := afterKey + bytes.IndexByte(data[afterKey:lastEnd], ':')
+			valueStart = lastEnd - len(bytes.TrimLeft(data[colon+1:lastEnd], " \t\r\n"))
+			valueEnd = lastEnd
+		}
+	}
+	if _, err := dec.Token(); err != nil {
+		return nil, err
+	}
+	closing := int(dec.InputOffset()) - 1
+
+	var out []byte
+	switch {
+	case valueStart >= 0:
+		out = append(out, data[:valueStart]...)
+		out = append(out, raw...)
+		out = append(out, data[valueEnd:]...)
+	case lastEnd >= 0:
+		out = append(out, data[:lastEnd]...)
+		out = fmt.Appendf(out, ",\n  %q: %s", key, raw)
+		out = append(out, data[lastEnd:]...)
+	default:
+		out = append(out, data[:closing]...)
+		out = fmt.Appendf(out, "\n  %q: %s\n", key, raw)
+		out = append(out, data[closing:]...)
+	}
+	return out, nil
+}
+
+// writeFileAtomic writes data to a temporary file next to path and renames it into place,
+// so a crash mid-write never leaves a truncated config behind.
+func writeFileAtomic(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("create temporary file: %w", err)
+	}
+	tmpPath := tmp.Name()
+	defer func() { _ = os.Remove(tmpPath) }() // only present if something failed before the rename
+
+	if err := tmp.Chmod(0o600); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("chmod temporary file: %w", err)
+	}
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("write temporary file: %w", err)
+	}
+	if err := tmp.Sync(); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("sync temporary file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("close temporary file: %w", err)
+	}
+	return os.Rename(tmpPath, path)
+}
+
+// ListWidth returns the list's width share in percent, clamped to the allowed range;
+// zero or unset uses the default.
+func (c Config) ListWidth() int {
+	return ClampListWidth(c.ListWidthPercent)
+}
+
+// ClampListWidth bounds a list width percentage to MinListWidthPercent-MaxListWidthPercent;
+// non-positive values yield DefaultListWidthPercent.
+func ClampListWidth(percent int) int {
+	switch {
+	case percent <= 0:
+		return DefaultListWidthPercent
+	case percent < MinListWidthPercent:
+		return MinListWidthPercent
+	case percent > MaxListWidthPercent:
+		return MaxListWidthPercent
+	}
+	return percent
+}
+
+// Stacked reports whether the details pane goes below the list instead of beside it.
+func (c Config) Stacked() bool {
+	return c.Layout == LayoutStacked
+}
+
+// Enter returns the configured Enter action; unknown values connect.
+func (c Config) Enter() string {
+	switch c.EnterAction {
+	case EnterActionEdit, EnterActionDetails:
+		return c.EnterAction
+	}
+	return EnterActionConnect
+}
+
+// PingCacheTTL returns the ping cache TTL as a duration. Negative values are treated as zero.
+func (c Config) PingCacheTTL() time.Duration {
+	if c.PingCacheTTLSeconds <= 0 {
+		return 0
+	}
+	return time.Duration(c.PingCacheTTLSeconds) * time.Second
+}
+
+// RemoteInventoryTTL returns how long a fetched remote inventory is reused; unset or
+// non-positive values use the default.
+func (c Config) RemoteInventoryTTL() time.Duration {
+	if c.RemoteInventoryTTLMinutes <= 0 {
+		return DefaultRemoteInventoryTTLMinutes * time.Minute
+	}
+	return time.Duration(c.RemoteInventoryTTLMinutes) * time.Minute
+}
+
+// PingTimeout returns the ping dial timeout. Unset or non-positive values use the default,
+// and values below MinPingTimeoutMS are raised to it.
+func (c Config) PingTimeout() time.Duration {
+	ms := c.PingTimeoutMS
+	switch {
+	case ms <= 0:
+		ms = DefaultPingTimeoutMS
+	case ms < MinPingTimeoutMS:
+		ms = MinPingTimeoutMS
+	}
+	return time.Duration(ms) * time.Millisecond
+}
+
+// ListRefreshInterval returns the periodic list refresh interval; zero means disabled.
+func (c Config) ListRefreshInterval() time.Duration {
+	if c.ListRefreshSeconds <= 0 {
+		return 0
+	}
+	return time.Duration(c.ListRefreshSeconds) * time.Second
+}
+
+// ConnectRetryBackoff returns the wait before the first connection retry; unset or
+// non-positive values use the default.
+func (c Config) ConnectRetryBackoff() time.Duration {
+	if c.ConnectRetryBackoffMS <= 0 {
+		return DefaultConnectRetryBackoffMS * time.Millisecond
+	}
+	return time.Duration(c.ConnectRetryBackoffMS) * time.Millisecond
+}
+
+// StaleAfter returns the staleness window as a duration; zero means disabled.
+func (c Config) StaleAfter() time.Duration {
+	if c.StaleAfterDays <= 0 {
+		return 0
+	}
+	return time.Duration(c.StaleAfterDays) * 24 * time.Hour
+}
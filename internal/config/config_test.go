@@ -0,0 +1,243 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestLoad(t *testing.T) {
+	tests := []struct {
+		name    string
+		content *string
+		wantTTL time.Duration
+		wantErr bool
+	}{
+		{
+			name:    "missing file uses defaults",
+			content: nil,
+			wantTTL: DefaultPingCacheTTLSeconds * time.Second,
+		},
+		{
+			name:    "empty file uses defaults",
+			content: strPtr(""),
+			wantTTL: DefaultPingCacheTTLSeconds * time.Second,
+		},
+		{
+			name:    "ttl override",
+			content: strPtr(`{"ping_cache_ttl_seconds": 30}`),
+			wantTTL: 30 * time.Second,
+		},
+		{
+			name:    "zero disables cache",
+			content: strPtr(`{"ping_cache_ttl_seconds": 0}`),
+			wantTTL: 0,
+		},
+		{
+			name:    "invalid JSON",
+			content: strPtr(`{`),
+			wantTTL: DefaultPingCacheTTLSeconds * time.Second,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "config.json")
+			if tt.content != nil {
+				if err := os.WriteFile(path, []byte(*tt.content), 0o600); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			cfg, err := Load(path)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Load() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got := cfg.PingCacheTTL(); got != tt.wantTTL {
+				t.Errorf("PingCacheTTL() = %v, want %v", got, tt.wantTTL)
+			}
+		})
+	}
+}
+
+func TestPingTimeout(t *testing.T) {
+	tests := []struct {
+		name string
+		ms   int
+		want time.Duration
+	}{
+		{name: "unset uses default", ms: 0, want: DefaultPingTimeoutMS * time.Millisecond},
+		{name: "negative uses default", ms: -5, want: DefaultPingTimeoutMS * time.Millisecond},
+		{name: "below minimum is raised", ms: 20, want: MinPingTimeoutMS * time.Millisecond},
+		{name: "minimum", ms: MinPingTimeoutMS, want: MinPingTimeoutMS * time.Millisecond},
+		{name: "custom", ms: 750, want: 750 * time.Millisecond},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := (Config{PingTimeoutMS: tt.ms}).PingTimeout(); got != tt.want {
+				t.Errorf("PingTimeout() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestListRefreshInterval(t *testing.T) {
+	tests := []struct {
+		seconds int
+		want    time.Duration
+	}{
+		{seconds: 0, want: 0},
+		{seconds: -1, want: 0},
+		{seconds: 30, want: 30 * time.Second},
+	}
+
+	for _, tt := range tests {
+		if got := (Config{ListRefreshSeconds: tt.seconds}).ListRefreshInterval(); got != tt.want {
+			t.Errorf("ListRefreshInterval(%d) = %v, want %v", tt.seconds, got, tt.want)
+		}
+	}
+}
+
+func strPtr(s string) *string {
+	return &s
+}
+
+func TestSetValue(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "lazyssh", "config.json")
+	if err := SetValue(path, "list_width_percent", 70); err != nil {
+		t.Fatalf("SetValue() on a missing file error = %v", err)
+	}
+	if err := os.WriteFile(path, []byte(`{"ping_timeout_ms": 500, "list_width_percent": 70}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := SetValue(path, "list_width_percent", 45); err != nil {
+		t.Fatalf("SetValue() error = %v", err)
+	}
+
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if cfg.ListWidthPercent != 45 || cfg.PingTimeoutMS != 500 {
+		t.Errorf("after SetValue got list_width_percent=%d ping_timeout_ms=%d, want 45 and 500", cfg.ListWidthPercent, cfg.PingTimeoutMS)
+	}
+	if cfg.Path() != path {
+		t.Errorf("Path() = %q, want %q", cfg.Path(), path)
+	}
+
+	if err := os.WriteFile(path, []byte(`{`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := SetValue(path, "list_width_percent", 50); err == nil {
+		t.Error("SetValue() on invalid JSON should fail rather than overwrite the file")
+	}
+}
+
+func TestSetJSONKeyKeepsFormatting(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{
+			name:  "replace in place",
+			input: "{\n    \"ping_timeout_ms\": 500,\n    \"list_width_percent\":   70, \"layout\": \"stacked\"\n}\n",
+			want:  "{\n    \"ping_timeout_ms\": 500,\n    \"list_width_percent\":   45, \"layout\": \"stacked\"\n}\n",
+		},
+		{
+			name:  "append after last key",
+			input: "{\"zeta\": true, \"alpha\": 1}",
+			want:  "{\"zeta\": true, \"alpha\": 1,\n  \"list_width_percent\": 45}",
+		},
+		{
+			name:  "empty object",
+			input: "{}\n",
+			want:  "{\n  \"list_width_percent\": 45\n}\n",
+		},
+		{
+			name:  "empty file",
+			input: "",
+			want:  "{\n  \"list_width_percent\": 45\n}\n",
+		},
+		{
+			name:  "nested value replaced whole",
+			input: `{"list_width_percent": {"old": [1, 2]}, "x": 1}`,
+			want:  `{"list_width_percent": 45, "x": 1}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := setJSONKey([]byte(tt.input), "list_width_percent", []byte("45"))
+			if err != nil {
+				t.Fatalf("setJSONKey() error = %v", err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("setJSONKey() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	for _, bad := range []string{"[1, 2]", `{"a": `} {
+		if _, err := setJSONKey([]byte(bad), "a", []byte("1")); err == nil {
+			t.Errorf("setJSONKey(%q) succeeded, want an error", bad)
+		}
+	}
+}
+
+func TestClampListWidth(t *testing.T) {
+	tests := []struct{ in, want int }{
+		{0, DefaultListWidthPercent},
+		{-5, DefaultListWidthPercent},
+		{10, MinListWidthPercent},
+		{45, 45},
+		{95, MaxListWidthPercent},
+	}
+	for _, tt := range tests {
+		if got := ClampListWidth(tt.in); got != tt.want {
+			t.Errorf("ClampListWidth(%d) = %d, want %d", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestStacked(t *testing.T) {
+	if Default().Stacked() {
+		t.Error("Default().Stacked() = true, want side by side")
+	}
+	if !(Config{Layout: LayoutStacked}).Stacked() {
+		t.Error(`Stacked() = false for layout "stacked"`)
+	}
+}
+
+func TestEnter(t *testing.T) {
+	tests := []struct {
+		action string
+		want   string
+	}{
+		{"", EnterActionConnect},
+		{"edit", EnterActionEdit},
+		{"details", EnterActionDetails},
+		{"explode", EnterActionConnect},
+	}
+	for _, tt := range tests {
+		if got := (Config{EnterAction: tt.action}).Enter(); got != tt.want {
+			t.Errorf("Enter() for %q = %q, want %q", tt.action, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,34 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package domain
+
+import "time"
+
+// Change kinds recorded in the change log.
+const (
+	ChangeAdd    = "add"
+	ChangeUpdate = "update"
+	ChangeRename = "rename"
+	ChangeDelete = "delete"
+)
+
+// ConfigChange is one entry of the change log: a server added, edited, renamed or deleted
+// through lazyssh, with a short description of what changed.
+type ConfigChange struct {
+	Time   time.Time
+	Action string
+	Alias  string
+	Detail string
+}
@@ -0,0 +1,43 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package domain
+
+// CryptoAlgorithm is one algorithm ssh would offer, flagged when it is deprecated as weak.
+type CryptoAlgorithm struct {
+	Name       string
+	Deprecated bool
+}
+
+// CryptoSettings are the algorithm lists ssh would offer a host once every config rule
+// is applied, in preference order.
+type CryptoSettings struct {
+	Ciphers           []CryptoAlgorithm
+	MACs              []CryptoAlgorithm
+	KexAlgorithms     []CryptoAlgorithm
+	HostKeyAlgorithms []CryptoAlgorithm
+}
+
+// DeprecatedCount returns how many offered algorithms are deprecated.
+func (c CryptoSettings) DeprecatedCount() int {
+	n := 0
+	for _, list := range [][]CryptoAlgorithm{c.Ciphers, c.MACs, c.KexAlgorithms, c.HostKeyAlgorithms} {
+		for _, a := range list {
+			if a.Deprecated {
+				n++
+			}
+		}
+	}
+	return n
+}
@@ -0,0 +1,45 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package domain
+
+import (
+	"net"
+	"strconv"
+)
+
+// Endpoint is the host and port an SSH connection goes to.
+type Endpoint struct {
+	Host string
+	Port int
+}
+
+// String renders the endpoint as host, or host:port when the port is not the default 22.
+// IPv6 hosts are bracketed when a port is shown.
+func (e Endpoint) String() string {
+	if e.Port == 0 || e.Port == 22 {
+		return e.Host
+	}
+	return net.JoinHostPort(e.Host, strconv.Itoa(e.Port))
+}
+
+// Endpoint returns the endpoint as written in the SSH config: HostName (or the alias when it
+// is unset) and Port. Matching Host patterns and Match blocks are not applied.
+func (s Server) Endpoint() Endpoint {
+	host := s.Host
+	if host == "" {
+		host = s.Alias
+	}
+	return Endpoint{Host: host, Port: s.Port}
+}
@@ -0,0 +1,34 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package domain
+
+// DiffReport describes how applying an inventory would change the configured servers.
+type DiffReport struct {
+	Added   []string
+	Changed []ServerChange
+	// Removed lists configured servers the inventory does not mention.
+	Removed []string
+}
+
+// ServerChange lists the differences for one server as "key: old -> new" lines.
+type ServerChange struct {
+	Alias   string
+	Changes []string
+}
+
+// Empty reports whether the inventory matches the configured servers.
+func (d DiffReport) Empty() bool {
+	return len(d.Added) == 0 && len(d.Changed) == 0 && len(d.Removed) == 0
+}
@@ -0,0 +1,75 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package domain
+
+import "time"
+
+// PathStage is where a connection path check stopped.
+type PathStage string
+
+const (
+	PathOK                PathStage = "ok"
+	PathProxyUnreachable  PathStage = "proxy-unreachable"
+	PathProxyAuth         PathStage = "proxy-auth"
+	PathTargetUnreachable PathStage = "target-unreachable"
+	PathTargetAuth        PathStage = "target-auth"
+	PathHostKey           PathStage = "host-key"
+	PathUnknown           PathStage = "unknown"
+)
+
+// PathCheck is the outcome of a non-interactive end-to-end connection through a server's
+// ProxyJump/ProxyCommand chain.
+type PathCheck struct {
+	Stage PathStage
+	// Hop is the host (or proxy command) the check failed at; empty when it succeeded or
+	// the failing hop could not be told from ssh's output.
+	Hop string
+	// Proxy describes the chain in front of the target: the ProxyJump value or ProxyCommand.
+	Proxy string
+	// Message is ssh's error line that decided the stage.
+	Message  string
+	Duration time.Duration
+}
+
+// OK reports whether the whole path, including authentication on the target, worked.
+func (c PathCheck) OK() bool {
+	return c.Stage == PathOK
+}
+
+// Summary describes the result in one sentence.
+func (c PathCheck) Summary() string {
+	hop := c.Hop
+	if hop == "" {
+		hop = "unknown hop"
+	}
+	switch c.Stage {
+	case PathOK:
+		return "Connected and authenticated end to end"
+	case PathProxyUnreachable:
+		return "Proxy " + hop + " is unreachable"
+	case PathProxyAuth:
+		return "Authentication failed on proxy " + hop
+	case PathTargetUnreachable:
+		if c.Proxy != "" {
+			return "Proxy path works, but target " + hop + " is unreachable from it"
+		}
+		return "Target " + hop + " is unreachable"
+	case PathTargetAuth:
+		return "Proxy path works, but authentication failed on target " + hop
+	case PathHostKey:
+		return "Host key verification failed for " + hop
+	}
+	return "Connection failed"
+}
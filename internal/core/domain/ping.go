@@ -0,0 +1,27 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package domain
+
+import "time"
+
+// PingResult is the outcome of a reachability check against a server's SSH port.
+type PingResult struct {
+	Up        bool
+	Latency   time.Duration
+	Err       error
+	CheckedAt time.Time
+	// Via is the ProxyJump host the check went through; empty for a direct TCP dial.
+	Via string
+}
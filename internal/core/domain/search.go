@@ -0,0 +1,108 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package domain
+
+import "strings"
+
+// FilterServers returns the servers matching a search query. Plain words match the host,
+// user, tags or aliases; host:, user: and alias: terms match only that field.
+func FilterServers(servers []Server, query string) []Server {
+	text, scoped := parseScopedQuery(strings.ToLower(query))
+	filtered := make([]Server, 0)
+
+	for _, server := range servers {
+		if matchesScoped(server, scoped) && matchesQuery(server, text) {
+			filtered = append(filtered, server)
+		}
+	}
+
+	return filtered
+}
+
+// searchScopes are the field prefixes a query term can use to match a single field.
+var searchScopes = map[string]bool{"host": true, "user": true, "alias": true}
+
+// scopedTerm is a "field:value" query term.
+type scopedTerm struct {
+	field string
+	value string
+}
+
+// parseScopedQuery splits host:, user: and alias: terms out of query. The remaining words
+// are rejoined as the unscoped text, which matches any field as before.
+func parseScopedQuery(query string) (string, []scopedTerm) {
+	var scoped []scopedTerm
+	var rest []string
+	for _, word := range strings.Fields(query) {
+		field, value, ok := strings.Cut(word, ":")
+		if ok && value != "" && searchScopes[field] {
+			scoped = append(scoped, scopedTerm{field: field, value: value})
+			continue
+		}
+		rest = append(rest, word)
+	}
+	if len(scoped) == 0 {
+		return query, nil
+	}
+	return strings.Join(rest, " "), scoped
+}
+
+// matchesScoped reports whether the server satisfies every scoped term.
+func matchesScoped(server Server, terms []scopedTerm) bool {
+	for _, term := range terms {
+		var fields []string
+		switch term.field {
+		case "host":
+			fields = []string{server.Host}
+		case "user":
+			fields = []string{server.User}
+		case "alias":
+			fields = server.Aliases
+		}
+		matched := false
+		for _, f := range fields {
+			if strings.Contains(strings.ToLower(f), term.value) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+	return true
+}
+
+// matchesQuery checks if any field of the server matches the query string.
+func matchesQuery(server Server, query string) bool {
+	fields := []string{
+		strings.ToLower(server.Host),
+		strings.ToLower(server.User),
+	}
+	for _, tag := range server.Tags {
+		fields = append(fields, strings.ToLower(tag))
+	}
+	for _, alias := range server.Aliases {
+		fields = append(fields, strings.ToLower(alias))
+	}
+
+	for _, field := range fields {
+		if strings.Contains(field, query) {
+			return true
+		}
+	}
+
+	return false
+}
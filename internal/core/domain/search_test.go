@@ -0,0 +1,55 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package domain
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestFilterServersScoped(t *testing.T) {
+	servers := []Server{
+		{Alias: "web1", Aliases: []string{"web1"}, Host: "10.0.0.5", User: "root"},
+		{Alias: "db10.0", Aliases: []string{"db10.0"}, Host: "192.168.1.2", User: "admin"},
+		{Alias: "api", Aliases: []string{"api"}, Host: "10.0.1.9", User: "deploy", Tags: []string{"prod"}},
+	}
+
+	tests := []struct {
+		name  string
+		query string
+		want  []string
+	}{
+		{"unscoped matches any field", "10.0", []string{"web1", "db10.0", "api"}},
+		{"host scope ignores alias", "host:10.0", []string{"web1", "api"}},
+		{"user scope", "user:root", []string{"web1"}},
+		{"alias scope", "alias:db", []string{"db10.0"}},
+		{"scopes combine", "host:10.0 user:deploy", []string{"api"}},
+		{"scope with text", "host:10.0 prod", []string{"api"}},
+		{"case insensitive", "USER:ROOT", []string{"web1"}},
+		{"empty value is plain text", "host:", nil},
+		{"unknown prefix is plain text", "tag:prod", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, s := range FilterServers(servers, tt.query) {
+				got = append(got, s.Alias)
+			}
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("FilterServers(%q) = %v, want %v", tt.query, got, tt.want)
+			}
+		})
+	}
+}
@@ -14,19 +14,61 @@
 
 package domain
 
-import "time"
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// Kinds of Server field that are not SSH config directives, set with the lazyssh struct tag.
+// Code that walks Server by reflection derives its skip lists from these instead of keeping
+// its own; untagged fields are directives.
+const (
+	// FieldName is the alias the Host entry is known by.
+	FieldName = "name"
+	// FieldState is bookkeeping lazyssh derives or records itself, never edited as a setting.
+	FieldState = "state"
+	// FieldMeta is a lazyssh setting kept as metadata rather than in the SSH config.
+	FieldMeta = "meta"
+)
+
+// ErrReadOnly is returned when asked to change a server listed from the remote inventory.
+var ErrReadOnly = errors.New("read-only (from remote inventory)")
 
 type Server struct {
-	Alias         string
-	Aliases       []string
+	Alias         string   `lazyssh:"name"`
+	Aliases       []string `lazyssh:"state"`
 	Host          string
 	User          string
 	Port          int
 	IdentityFiles []string
-	Tags          []string
-	LastSeen      time.Time
-	PinnedAt      time.Time
-	SSHCount      int
+	Tags          []string  `lazyssh:"meta"`
+	LastSeen      time.Time `lazyssh:"state"`
+	PinnedAt      time.Time `lazyssh:"state"`
+	SSHCount      int       `lazyssh:"state"`
+	LastError     string    `lazyssh:"state"`
+	LastErrorAt   time.Time `lazyssh:"state"`
+	// PinOrder places a pinned server manually (1 first); 0 leaves it ordered by PinnedAt.
+	PinOrder int `lazyssh:"state"`
+	// ConfigOrder is the position of the Host entry in the SSH config; OpenSSH applies
+	// the first matching value, so earlier entries take precedence.
+	ConfigOrder int `lazyssh:"state"`
+	// SessionLogging overrides the session_logging config for this server; nil follows it.
+	SessionLogging *bool `lazyssh:"meta"`
+	// AutoTunnels are lazyssh-managed local forwards (ssh -L specs) opened with every
+	// interactive session; AutoTunnelsDisabled keeps them stored without using them.
+	AutoTunnels         []string `lazyssh:"meta"`
+	AutoTunnelsDisabled bool     `lazyssh:"meta"`
+	// RequiresVPN marks a server only reachable over a VPN, so failed pings are expected
+	// when disconnected and are not reported as down.
+	RequiresVPN bool `lazyssh:"meta"`
+	// PingTarget is the host:port pings dial instead of the SSH HostName and Port, for hosts
+	// whose SSH port is firewalled but another port shows liveness. Empty uses the SSH address.
+	PingTarget string `lazyssh:"meta"`
+	// ReadOnly marks a server listed from a remote inventory rather than the SSH config: it
+	// can be connected to but not edited.
+	ReadOnly bool `lazyssh:"state"`
 
 	// Additional SSH config fields
 	// Connection and proxy settings
@@ -115,3 +157,71 @@ type Server struct {
 	// Debugging settings
 	LogLevel string
 }
+
+// serverFieldKinds maps each tagged Server field to its kind.
+var serverFieldKinds = func() map[string]string {
+	kinds := make(map[string]string)
+	t := reflect.TypeOf(Server{})
+	for i := 0; i < t.NumField(); i++ {
+		if kind := t.Field(i).Tag.Get("lazyssh"); kind != "" {
+			kinds[t.Field(i).Name] = kind
+		}
+	}
+	return kinds
+}()
+
+// ServerFieldKind returns the kind (FieldName, FieldState or FieldMeta) of the named Server
+// field, or "" when it is an SSH config directive.
+func ServerFieldKind(name string) string {
+	return serverFieldKinds[name]
+}
+
+// URLTagPrefix marks a tag that holds the server's web UI address, e.g. "url:https://grafana.prod".
+const URLTagPrefix = "url:"
+
+// URL returns the address from the server's first url: tag, or an empty string.
+func (s Server) URL() string {
+	for _, tag := range s.Tags {
+		if len(tag) > len(URLTagPrefix) && strings.EqualFold(tag[:len(URLTagPrefix)], URLTagPrefix) {
+			return strings.TrimSpace(tag[len(URLTagPrefix):])
+		}
+	}
+	return ""
+}
+
+// IsStale reports whether the server has not been connected to within the given window.
+// Servers that were never connected to are stale; a non-positive window disables the check.
+func (s Server) IsStale(now time.Time, window time.Duration) bool {
+	if window <= 0 {
+		return false
+	}
+	return s.LastSeen.IsZero() || now.Sub(s.LastSeen) > window
+}
+
+// PinnedBefore reports whether pinned server s sorts above pinned server o: servers with a
+// manual PinOrder come first in ascending order, the rest follow newest pin first.
+func (s Server) PinnedBefore(o Server) bool {
+	if (s.PinOrder > 0) != (o.PinOrder > 0) {
+		return s.PinOrder > 0
+	}
+	if s.PinOrder != o.PinOrder {
+		return s.PinOrder < o.PinOrder
+	}
+	if !s.PinnedAt.Equal(o.PinnedAt) {
+		return s.PinnedAt.After(o.PinnedAt)
+	}
+	return strings.ToLower(s.Alias) < strings.ToLower(o.Alias)
+}
+
+// Control master operations, passed to `ssh -O`.
+const (
+	ControlCheck = "check"
+	ControlExit  = "exit"
+)
+
+// ServerFiles lists the on-disk files backing a server entry.
+type ServerFiles struct {
+	ConfigPath   string
+	ConfigLine   int // 1-based line of the Host entry; 0 if it could not be located
+	MetadataPath string
+}
@@ -0,0 +1,90 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package domain
+
+import (
+	"sort"
+	"testing"
+	"time"
+)
+
+func TestPinnedBefore(t *testing.T) {
+	now := time.Now()
+	servers := []Server{
+		{Alias: "old", PinnedAt: now.Add(-2 * time.Hour)},
+		{Alias: "second", PinnedAt: now.Add(-3 * time.Hour), PinOrder: 2},
+		{Alias: "new", PinnedAt: now},
+		{Alias: "first", PinnedAt: now.Add(-time.Hour), PinOrder: 1},
+	}
+	sort.SliceStable(servers, func(i, j int) bool { return servers[i].PinnedBefore(servers[j]) })
+
+	want := []string{"first", "second", "new", "old"}
+	for i, alias := range want {
+		if servers[i].Alias != alias {
+			t.Fatalf("position %d = %s, want %s (order %v)", i, servers[i].Alias, alias, servers)
+		}
+	}
+}
+
+func TestEndpointString(t *testing.T) {
+	tests := []struct {
+		name string
+		e    Endpoint
+		want string
+	}{
+		{name: "default port", e: Endpoint{Host: "web.example.com", Port: 22}, want: "web.example.com"},
+		{name: "unset port", e: Endpoint{Host: "web"}, want: "web"},
+		{name: "custom port", e: Endpoint{Host: "10.0.0.5", Port: 2222}, want: "10.0.0.5:2222"},
+		{name: "ipv6", e: Endpoint{Host: "2001:db8::1", Port: 2222}, want: "[2001:db8::1]:2222"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.e.String(); got != tt.want {
+				t.Errorf("String() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	if got := (Server{Alias: "web"}).Endpoint(); got != (Endpoint{Host: "web"}) {
+		t.Errorf("Endpoint() without HostName = %+v, want the alias", got)
+	}
+}
+
+func TestServerFieldKind(t *testing.T) {
+	tests := []struct {
+		field string
+		want  string
+	}{
+		{field: "Alias", want: FieldName},
+		{field: "SSHCount", want: FieldState},
+		{field: "ConfigOrder", want: FieldState},
+		{field: "Tags", want: FieldMeta},
+		{field: "PingTarget", want: FieldMeta},
+		{field: "Host", want: ""},
+		{field: "ProxyJump", want: ""},
+		{field: "NoSuchField", want: ""},
+	}
+	for _, tt := range tests {
+		if got := ServerFieldKind(tt.field); got != tt.want {
+			t.Errorf("ServerFieldKind(%q) = %q, want %q", tt.field, got, tt.want)
+		}
+	}
+
+	for name, kind := range serverFieldKinds {
+		if kind != FieldName && kind != FieldState && kind != FieldMeta {
+			t.Errorf("field %s has unknown lazyssh tag %q", name, kind)
+		}
+	}
+}
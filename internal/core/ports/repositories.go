@@ -22,5 +22,27 @@ type ServerRepository interface {
 	AddServer(server domain.Server) error
 	DeleteServer(server domain.Server) error
 	SetPinned(alias string, pinned bool) error
+	SetPinOrder(orders map[string]int) error
+	SetSessionLogging(alias string, enabled *bool) error
+	SetAutoTunnels(alias string, specs []string, disabled bool) error
 	RecordSSH(alias string) error
+	RecordSSHError(alias string, message string) error
+	MoveServer(alias string, offset int) (string, error)
+	RenameTag(oldTag, newTag string) (int, error)
+	DeleteTag(tag string) (int, error)
+	DrainWarnings() []string
+	ServerFiles(alias string) (domain.ServerFiles, error)
+	ConfigWritable() (path string, writable bool)
+	HostBlock(server domain.Server) string
+	RecentChanges(alias string, limit int) ([]domain.ConfigChange, error)
+}
+
+// ServerSource supplies servers from outside the SSH config, such as a shared remote
+// inventory. Its servers are listed read-only next to the configured ones.
+type ServerSource interface {
+	// Servers returns the servers last loaded, without waiting on the network.
+	Servers() ([]domain.Server, error)
+	// Watch keeps the servers up to date in the background, calling onChange after each
+	// reload, until stop is closed.
+	Watch(stop <-chan struct{}, onChange func())
 }
@@ -15,17 +15,50 @@
 package ports
 
 import (
-	"time"
+	"io"
+	"os"
 
 	"github.com/Adembc/lazyssh/internal/core/domain"
 )
 
 type ServerService interface {
 	ListServers(query string) ([]domain.Server, error)
+	WatchRemoteInventory(stop <-chan struct{}, onChange func())
 	UpdateServer(server domain.Server, newServer domain.Server) error
 	AddServer(server domain.Server) error
 	DeleteServer(server domain.Server) error
 	SetPinned(alias string, pinned bool) error
+	MovePinned(alias string, offset int) (string, error)
 	SSH(alias string) error
-	Ping(server domain.Server) (bool, time.Duration, error)
+	SSHVerbose(alias string) error
+	Ping(server domain.Server) domain.PingResult
+	PingAll(servers []domain.Server) map[string]domain.PingResult
+	CachedPing(alias string) (domain.PingResult, bool)
+	ResolveEndpoints(aliases []string) map[string]domain.Endpoint
+	MoveServer(alias string, offset int) (string, error)
+	RenameTag(oldTag, newTag string) (int, error)
+	DeleteTag(tag string) (int, error)
+	ExportInventory(w io.Writer, format string) error
+	ImportInventory(r io.Reader, format string) error
+	DiffInventory(path string) (domain.DiffReport, error)
+	ApplyInventory(path string, prune bool) (domain.DiffReport, error)
+	DrainWarnings() []string
+	ServerFiles(alias string) (domain.ServerFiles, error)
+	ConfigWritable() (path string, writable bool)
+	ConfigBlock(server domain.Server) string
+	ServerJSON(server domain.Server) (string, error)
+	RecentChanges(alias string, limit int) ([]domain.ConfigChange, error)
+	OpenURL(url string) error
+	SetSessionLogging(alias string, enabled *bool) error
+	SetAutoTunnels(alias string, specs []string, disabled bool) error
+	SessionLoggingDefault() bool
+	ControlSocket(alias string) (path string, active bool)
+	ControlMaster(alias, op string) (string, error)
+	CheckKeyPermissions(path string) (ok bool, mode os.FileMode)
+	FixKeyPermissions(path string) error
+	AgentHasKey(identityFile string) (bool, error)
+	AddKeyToAgent(identityFile string) error
+	Audit() ([]domain.AuditFinding, error)
+	EffectiveCrypto(alias string) (domain.CryptoSettings, error)
+	CheckPath(alias string) domain.PathCheck
 }
@@ -0,0 +1,126 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package services
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/Adembc/lazyssh/internal/core/domain"
+)
+
+// agentNoIdentities is ssh-add's exit status when the agent is running but holds no keys;
+// other failures (2) mean no agent could be reached.
+const agentNoIdentities = 1
+
+// AgentHasKey reports whether the key at identityFile is loaded in ssh-agent, by comparing
+// its fingerprint with those listed by "ssh-add -l".
+func (s *serverService) AgentHasKey(identityFile string) (bool, error) {
+	fingerprint, err := keyFingerprint(expandKeyPath(identityFile))
+	if err != nil {
+		return false, err
+	}
+	loaded, err := agentFingerprints()
+	if err != nil {
+		s.logger.Debugw("ssh-agent not available", "error", err)
+		return false, err
+	}
+	for _, fp := range loaded {
+		if fp == fingerprint {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// AddKeyToAgent runs "ssh-add <identityFile>" on the terminal, so ssh-add can ask for the
+// key's passphrase itself. The caller must have released the terminal.
+func (s *serverService) AddKeyToAgent(identityFile string) error {
+	cmd := exec.Command(sshTool("ssh-add"), expandKeyPath(identityFile))
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		s.logger.Errorw("ssh-add failed", "path", identityFile, "error", err)
+		return fmt.Errorf("ssh-add %s: %w", identityFile, err)
+	}
+	s.logger.Infow("added key to agent", "path", identityFile)
+	return nil
+}
+
+// agentFingerprints lists the fingerprints of the keys loaded in ssh-agent.
+func agentFingerprints() ([]string, error) {
+	out, err := exec.Command(sshTool("ssh-add"), "-l").Output()
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && exitErr.ExitCode() == agentNoIdentities {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", domain.ErrAgentUnavailable, err)
+	}
+	var fps []string
+	scanner := bufio.NewScanner(strings.NewReader(string(out)))
+	for scanner.Scan() {
+		if fp := parseFingerprint(scanner.Text()); fp != "" {
+			fps = append(fps, fp)
+		}
+	}
+	return fps, nil
+}
+
+// keyFingerprint returns the fingerprint of the key at path. The .pub file next to a private
+// key is preferred, since ssh-keygen cannot read a passphrase-protected private key.
+func keyFingerprint(path string) (string, error) {
+	target := path
+	if !strings.HasSuffix(path, ".pub") {
+		if _, err := os.Stat(path + ".pub"); err == nil {
+			target = path + ".pub"
+		}
+	}
+	out, err := exec.Command(sshTool("ssh-keygen"), "-l", "-f", target).Output()
+	if err != nil {
+		return "", fmt.Errorf("fingerprint %s: %w", path, err)
+	}
+	fp := parseFingerprint(string(out))
+	if fp == "" {
+		return "", fmt.Errorf("fingerprint %s: unexpected ssh-keygen output", path)
+	}
+	return fp, nil
+}
+
+// parseFingerprint extracts the fingerprint from an "ssh-add -l" or "ssh-keygen -l" line:
+// "256 SHA256:abc… comment (ED25519)".
+func parseFingerprint(line string) string {
+	fields := strings.Fields(line)
+	if len(fields) < 2 || !strings.Contains(fields[1], ":") {
+		return ""
+	}
+	return fields[1]
+}
+
+// sshTool returns the OpenSSH companion program (ssh-add, ssh-keygen) next to the ssh client
+// in use, or just its name to be found on PATH.
+func sshTool(name string) string {
+	bin := sshBinary()
+	if !filepath.IsAbs(bin) {
+		return name
+	}
+	return filepath.Join(filepath.Dir(bin), name+filepath.Ext(bin))
+}
@@ -0,0 +1,34 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package services
+
+import "testing"
+
+func TestParseFingerprint(t *testing.T) {
+	tests := []struct {
+		line string
+		want string
+	}{
+		{"256 SHA256:Ue8NqX2pR0vtmlQ7Lq0T3Jd9GqcbQ/w1o5nFz0kDq1E me@laptop (ED25519)", "SHA256:Ue8NqX2pR0vtmlQ7Lq0T3Jd9GqcbQ/w1o5nFz0kDq1E"},
+		{"2048 MD5:12:f8:7e:78:61:b4:bf:e2:de:24:15:96:4e:d4:72:53 deploy (RSA)", "MD5:12:f8:7e:78:61:b4:bf:e2:de:24:15:96:4e:d4:72:53"},
+		{"The agent has no identities.", ""},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := parseFingerprint(tt.line); got != tt.want {
+			t.Errorf("parseFingerprint(%q) = %q, want %q", tt.line, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,152 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package services
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/Adembc/lazyssh/internal/core/domain"
+)
+
+// Audit check names, stable for scripts consuming "audit --json".
+const (
+	auditHostKeyChecking = "strict-host-key-checking"
+	auditForwardAgent    = "forward-agent"
+	auditKeyPermissions  = "key-permissions"
+	auditPlaintextSecret = "plaintext-secret"
+	auditWeakCiphers     = "weak-ciphers"
+)
+
+// weakCiphers are ciphers OpenSSH has deprecated or removed as insecure.
+var weakCiphers = map[string]bool{
+	"3des-cbc":                    true,
+	"aes128-cbc":                  true,
+	"aes192-cbc":                  true,
+	"aes256-cbc":                  true,
+	"blowfish-cbc":                true,
+	"cast128-cbc":                 true,
+	"arcfour":                     true,
+	"arcfour128":                  true,
+	"arcfour256":                  true,
+	"rijndael-cbc@lysator.liu.se": true,
+}
+
+// secretEnvName matches SetEnv variable names that usually carry credentials.
+var secretEnvName = regexp.MustCompile(`(?i)pass(word|wd)?|secret|token`)
+
+// Audit scans every server in the SSH config for risky settings. OpenSSH has no password
+// directive, so plaintext passwords are looked for where they end up in practice:
+// sshpass -p in ProxyCommand, RemoteCommand or LocalCommand, and credential-like SetEnv values.
+func (s *serverService) Audit() ([]domain.AuditFinding, error) {
+	servers, err := s.serverRepository.ListServers("")
+	if err != nil {
+		s.logger.Errorw("failed to list servers for audit", "error", err)
+		return nil, fmt.Errorf("list servers: %w", err)
+	}
+
+	var findings []domain.AuditFinding
+	for _, srv := range servers {
+		findings = append(findings, s.auditServer(srv)...)
+	}
+	sort.SliceStable(findings, func(i, j int) bool {
+		if findings[i].Alias != findings[j].Alias {
+			return findings[i].Alias < findings[j].Alias
+		}
+		return findings[i].Check < findings[j].Check
+	})
+	return findings, nil
+}
+
+// auditServer returns the findings for a single server.
+func (s *serverService) auditServer(srv domain.Server) []domain.AuditFinding {
+	var out []domain.AuditFinding
+	add := func(check string, sev domain.AuditSeverity, format string, args ...any) {
+		out = append(out, domain.AuditFinding{Alias: srv.Alias, Check: check, Severity: sev, Message: fmt.Sprintf(format, args...)})
+	}
+
+	switch strings.ToLower(srv.StrictHostKeyChecking) {
+	case "no", "off":
+		add(auditHostKeyChecking, domain.AuditHigh, "StrictHostKeyChecking %s accepts any host key, allowing man-in-the-middle attacks", srv.StrictHostKeyChecking)
+	}
+	if strings.EqualFold(srv.ForwardAgent, "yes") {
+		add(auditForwardAgent, domain.AuditMedium, "ForwardAgent yes lets root on the server use your agent keys")
+	}
+	for _, key := range srv.IdentityFiles {
+		if ok, mode := s.CheckKeyPermissions(key); !ok {
+			add(auditKeyPermissions, domain.AuditHigh, "identity file %s is readable by other users (mode %04o)", key, mode)
+		}
+	}
+	for _, cmd := range []struct{ name, value string }{
+		{"ProxyCommand", srv.ProxyCommand},
+		{"RemoteCommand", srv.RemoteCommand},
+		{"LocalCommand", srv.LocalCommand},
+	} {
+		if sshpassInlinePassword(cmd.value) {
+			add(auditPlaintextSecret, domain.AuditHigh, "%s passes a password to sshpass in plain text", cmd.name)
+		}
+	}
+	for _, env := range srv.SetEnv {
+		name, value, ok := strings.Cut(env, "=")
+		if ok && value != "" && secretEnvName.MatchString(name) {
+			add(auditPlaintextSecret, domain.AuditHigh, "SetEnv %s stores a credential in plain text", name)
+		}
+	}
+	if weak := weakCipherList(srv.Ciphers); len(weak) > 0 {
+		add(auditWeakCiphers, domain.AuditMedium, "Ciphers enables weak algorithms: %s", strings.Join(weak, ", "))
+	}
+	return out
+}
+
+// sshpassInlinePassword reports whether command runs sshpass with the password on its
+// command line (-p). Only sshpass's own options are checked, not the wrapped command's.
+func sshpassInlinePassword(command string) bool {
+	fields := strings.Fields(command)
+	for i, f := range fields {
+		if filepath.Base(f) != "sshpass" {
+			continue
+		}
+		for _, opt := range fields[i+1:] {
+			if !strings.HasPrefix(opt, "-") {
+				break
+			}
+			if strings.HasPrefix(opt, "-p") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// weakCipherList returns the weak ciphers a Ciphers value enables. A leading "+" or "^"
+// adds to the defaults and still enables the listed ciphers; a leading "-" removes them.
+func weakCipherList(ciphers string) []string {
+	ciphers = strings.TrimSpace(ciphers)
+	if ciphers == "" || strings.HasPrefix(ciphers, "-") {
+		return nil
+	}
+	ciphers = strings.TrimLeft(ciphers, "+^")
+	var weak []string
+	for _, c := range strings.Split(ciphers, ",") {
+		c = strings.ToLower(strings.TrimSpace(c))
+		if weakCiphers[c] {
+			weak = append(weak, c)
+		}
+	}
+	return weak
+}
@@ -0,0 +1,61 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package services
+
+import (
+	"reflect"
+	"testing"
+
+	"go.uber.org/zap"
+
+	"github.com/Adembc/lazyssh/internal/core/domain"
+)
+
+func TestAuditServer(t *testing.T) {
+	tests := []struct {
+		name   string
+		server domain.Server
+		want   []string
+	}{
+		{"clean", domain.Server{Alias: "web", StrictHostKeyChecking: "accept-new", ForwardAgent: "no"}, nil},
+		{"host key checking off", domain.Server{Alias: "web", StrictHostKeyChecking: "no"}, []string{auditHostKeyChecking}},
+		{"agent forwarding", domain.Server{Alias: "web", ForwardAgent: "Yes"}, []string{auditForwardAgent}},
+		{"sshpass inline", domain.Server{Alias: "web", ProxyCommand: "sshpass -p hunter2 ssh -W %h:%p bastion"}, []string{auditPlaintextSecret}},
+		{"sshpass from file", domain.Server{Alias: "web", ProxyCommand: "sshpass -f ~/.pw ssh -p 2222 -W %h:%p bastion"}, nil},
+		{"secret env", domain.Server{Alias: "web", SetEnv: []string{"DB_PASSWORD=hunter2", "LANG=C"}}, []string{auditPlaintextSecret}},
+		{"weak ciphers", domain.Server{Alias: "web", Ciphers: "aes256-ctr,3des-cbc"}, []string{auditWeakCiphers}},
+		{"removed weak ciphers", domain.Server{Alias: "web", Ciphers: "-3des-cbc"}, nil},
+	}
+	s := &serverService{logger: zap.NewNop().Sugar()}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, f := range s.auditServer(tt.server) {
+				got = append(got, f.Check)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("auditServer() checks = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWeakCipherList(t *testing.T) {
+	got := weakCipherList("+AES128-CBC, chacha20-poly1305@openssh.com,arcfour")
+	want := []string{"aes128-cbc", "arcfour"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("weakCipherList() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,99 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package services
+
+import (
+	"bufio"
+	"fmt"
+	"os/exec"
+	"strings"
+
+	"github.com/Adembc/lazyssh/internal/core/domain"
+)
+
+// weakMACs are MACs built on MD5 or truncated SHA-1.
+var weakMACs = map[string]bool{
+	"hmac-md5":                       true,
+	"hmac-md5-96":                    true,
+	"hmac-md5-etm@openssh.com":       true,
+	"hmac-md5-96-etm@openssh.com":    true,
+	"hmac-sha1-96":                   true,
+	"hmac-sha1-96-etm@openssh.com":   true,
+	"hmac-ripemd160":                 true,
+	"hmac-ripemd160@openssh.com":     true,
+	"hmac-ripemd160-etm@openssh.com": true,
+}
+
+// weakKex are key exchanges using SHA-1 or 1024-bit groups.
+var weakKex = map[string]bool{
+	"diffie-hellman-group1-sha1":         true,
+	"diffie-hellman-group14-sha1":        true,
+	"diffie-hellman-group-exchange-sha1": true,
+}
+
+// weakHostKeys are DSA and SHA-1 RSA signature algorithms.
+var weakHostKeys = map[string]bool{
+	"ssh-dss":                      true,
+	"ssh-dss-cert-v01@openssh.com": true,
+	"ssh-rsa":                      true,
+	"ssh-rsa-cert-v01@openssh.com": true,
+}
+
+// EffectiveCrypto returns the ciphers, MACs, key exchange and host key algorithms ssh would
+// offer alias, as reported by `ssh -G`, with deprecated ones flagged.
+func (s *serverService) EffectiveCrypto(alias string) (domain.CryptoSettings, error) {
+	out, err := exec.Command(sshBinary(), "-G", alias).Output()
+	if err != nil {
+		s.logger.Errorw("ssh -G failed", "alias", alias, "error", err)
+		return domain.CryptoSettings{}, fmt.Errorf("ssh -G %s: %w", alias, err)
+	}
+	return parseCryptoSettings(string(out)), nil
+}
+
+// parseCryptoSettings reads the algorithm lists from `ssh -G` output.
+func parseCryptoSettings(out string) domain.CryptoSettings {
+	var c domain.CryptoSettings
+	scanner := bufio.NewScanner(strings.NewReader(out))
+	for scanner.Scan() {
+		key, value, ok := strings.Cut(strings.TrimSpace(scanner.Text()), " ")
+		if !ok {
+			continue
+		}
+		switch key {
+		case "ciphers":
+			c.Ciphers = flagAlgorithms(value, weakCiphers)
+		case "macs":
+			c.MACs = flagAlgorithms(value, weakMACs)
+		case "kexalgorithms":
+			c.KexAlgorithms = flagAlgorithms(value, weakKex)
+		case "hostkeyalgorithms":
+			c.HostKeyAlgorithms = flagAlgorithms(value, weakHostKeys)
+		}
+	}
+	return c
+}
+
+// flagAlgorithms splits a comma-separated algorithm list and marks the entries found in weak.
+func flagAlgorithms(list string, weak map[string]bool) []domain.CryptoAlgorithm {
+	var algs []domain.CryptoAlgorithm
+	for _, name := range strings.Split(list, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		algs = append(algs, domain.CryptoAlgorithm{Name: name, Deprecated: weak[strings.ToLower(name)]})
+	}
+	return algs
+}
@@ -0,0 +1,41 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package services
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/Adembc/lazyssh/internal/core/domain"
+)
+
+func TestParseCryptoSettings(t *testing.T) {
+	out := "user root\nciphers chacha20-poly1305@openssh.com,aes128-cbc\nmacs hmac-sha2-256,hmac-md5\n" +
+		"kexalgorithms curve25519-sha256,diffie-hellman-group1-sha1\nhostkeyalgorithms ssh-ed25519,ssh-rsa\n"
+	c := parseCryptoSettings(out)
+
+	want := domain.CryptoSettings{
+		Ciphers:           []domain.CryptoAlgorithm{{Name: "chacha20-poly1305@openssh.com"}, {Name: "aes128-cbc", Deprecated: true}},
+		MACs:              []domain.CryptoAlgorithm{{Name: "hmac-sha2-256"}, {Name: "hmac-md5", Deprecated: true}},
+		KexAlgorithms:     []domain.CryptoAlgorithm{{Name: "curve25519-sha256"}, {Name: "diffie-hellman-group1-sha1", Deprecated: true}},
+		HostKeyAlgorithms: []domain.CryptoAlgorithm{{Name: "ssh-ed25519"}, {Name: "ssh-rsa", Deprecated: true}},
+	}
+	if !reflect.DeepEqual(c, want) {
+		t.Errorf("parseCryptoSettings() = %+v, want %+v", c, want)
+	}
+	if n := c.DeprecatedCount(); n != 4 {
+		t.Errorf("DeprecatedCount() = %d, want 4", n)
+	}
+}
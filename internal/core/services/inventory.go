@@ -0,0 +1,567 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Adembc/lazyssh/internal/core/domain"
+	"gopkg.in/yaml.v3"
+)
+
+// inventoryVersion is the version written to exported inventories; imports reject newer ones.
+const inventoryVersion = 1
+
+// Supported inventory formats.
+const (
+	InventoryFormatYAML = "yaml"
+	InventoryFormatJSON = "json"
+)
+
+// inventory is the declarative document written by ExportInventory and applied by ImportInventory.
+type inventory struct {
+	Version int               `yaml:"version" json:"version"`
+	Servers []inventoryServer `yaml:"servers" json:"servers"`
+}
+
+// inventoryServer is one server in an inventory. Directives without a dedicated field are kept
+// under their SSH config names in Options (single value) or ListOptions (repeatable).
+type inventoryServer struct {
+	Alias          string              `yaml:"alias" json:"alias"`
+	Host           string              `yaml:"host" json:"host"`
+	User           string              `yaml:"user,omitempty" json:"user,omitempty"`
+	Port           int                 `yaml:"port,omitempty" json:"port,omitempty"`
+	IdentityFiles  []string            `yaml:"identity_files,omitempty" json:"identity_files,omitempty"`
+	Tags           []string            `yaml:"tags,omitempty" json:"tags,omitempty"`
+	Pinned         bool                `yaml:"pinned,omitempty" json:"pinned,omitempty"`
+	PinOrder       int                 `yaml:"pin_order,omitempty" json:"pin_order,omitempty"`
+	SessionLogging *bool               `yaml:"session_logging,omitempty" json:"session_logging,omitempty"`
+	AutoTunnels    []string            `yaml:"auto_tunnels,omitempty" json:"auto_tunnels,omitempty"`
+	AutoTunnelsOff bool                `yaml:"auto_tunnels_disabled,omitempty" json:"auto_tunnels_disabled,omitempty"`
+	RequiresVPN    bool                `yaml:"requires_vpn,omitempty" json:"requires_vpn,omitempty"`
+	PingTarget     string              `yaml:"ping_target,omitempty" json:"ping_target,omitempty"`
+	Options        map[string]string   `yaml:"options,omitempty" json:"options,omitempty"`
+	ListOptions    map[string][]string `yaml:"list_options,omitempty" json:"list_options,omitempty"`
+}
+
+// inventoryKeyFields are SSH config directives with their own inventory key rather than an
+// entry in Options or ListOptions.
+var inventoryKeyFields = map[string]bool{"Host": true, "User": true, "Port": true, "IdentityFiles": true}
+
+// inventoryDedicated reports whether the named domain.Server field has its own inventory key
+// or is not an SSH directive at all, so it never appears in Options or ListOptions.
+func inventoryDedicated(name string) bool {
+	return inventoryKeyFields[name] || domain.ServerFieldKind(name) != ""
+}
+
+// serverRecord is a server's full record as copied by ServerJSON: its inventory entry plus the
+// usage statistics an inventory leaves out.
+type serverRecord struct {
+	inventoryServer
+	Aliases     []string   `json:"aliases,omitempty"`
+	PinnedAt    *time.Time `json:"pinned_at,omitempty"`
+	SSHCount    int        `json:"ssh_count"`
+	LastSeen    *time.Time `json:"last_seen,omitempty"`
+	LastError   string     `json:"last_error,omitempty"`
+	LastErrorAt *time.Time `json:"last_error_at,omitempty"`
+}
+
+// ServerJSON renders one server's config fields and lazyssh metadata as indented JSON, for
+// pasting into tickets or scripts.
+func (s *serverService) ServerJSON(server domain.Server) (string, error) {
+	record := serverRecord{
+		inventoryServer: toInventoryServer(server),
+		Aliases:         server.Aliases,
+		PinnedAt:        optionalTime(server.PinnedAt),
+		SSHCount:        server.SSHCount,
+		LastSeen:        optionalTime(server.LastSeen),
+		LastError:       server.LastError,
+		LastErrorAt:     optionalTime(server.LastErrorAt),
+	}
+	data, err := json.MarshalIndent(record, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("encode server %s: %w", server.Alias, err)
+	}
+	return string(data), nil
+}
+
+// optionalTime returns nil for the zero time so it is omitted from JSON output.
+func optionalTime(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
+
+// ExportInventory writes every server, in SSH config order, with its tags and pin state as a
+// YAML or JSON document suitable for keeping in version control.
+func (s *serverService) ExportInventory(w io.Writer, format string) error {
+	servers, err := s.serverRepository.ListServers("")
+	if err != nil {
+		s.logger.Errorw("failed to list servers for export", "error", err)
+		return err
+	}
+	sort.SliceStable(servers, func(i, j int) bool { return servers[i].ConfigOrder < servers[j].ConfigOrder })
+
+	doc := inventory{Version: inventoryVersion, Servers: make([]inventoryServer, 0, len(servers))}
+	for _, srv := range servers {
+		doc.Servers = append(doc.Servers, toInventoryServer(srv))
+	}
+
+	switch normalizeInventoryFormat(format) {
+	case InventoryFormatYAML:
+		enc := yaml.NewEncoder(w)
+		enc.SetIndent(2)
+		if err := enc.Encode(doc); err != nil {
+			return fmt.Errorf("encode inventory: %w", err)
+		}
+		return enc.Close()
+	case InventoryFormatJSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(doc); err != nil {
+			return fmt.Errorf("encode inventory: %w", err)
+		}
+		return nil
+	default:
+		return fmt.Errorf("unsupported inventory format %q (use yaml or json)", format)
+	}
+}
+
+// ImportInventory applies an inventory written by ExportInventory: listed servers are added or
+// updated to match it, and servers missing from it are left alone. The whole document is
+// validated before anything is written.
+func (s *serverService) ImportInventory(r io.Reader, format string) error {
+	plan, err := s.planInventory(r, format)
+	if err != nil {
+		return err
+	}
+	return s.applyInventoryPlan(plan)
+}
+
+// DiffInventory reports which servers applying the inventory at path would add or change, and
+// which configured servers it does not list, without writing anything. The format is taken
+// from the file extension.
+func (s *serverService) DiffInventory(path string) (domain.DiffReport, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return domain.DiffReport{}, fmt.Errorf("open inventory: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+
+	plan, err := s.planInventory(f, InventoryFormatForPath(path))
+	if err != nil {
+		return domain.DiffReport{}, err
+	}
+	return plan.report(), nil
+}
+
+// ApplyInventory reconciles the configured servers with the inventory at path: missing servers
+// are added and changed ones updated. With prune, servers the inventory does not list are
+// deleted. It returns what was done; Removed is empty unless prune is set.
+func (s *serverService) ApplyInventory(path string, prune bool) (domain.DiffReport, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return domain.DiffReport{}, fmt.Errorf("open inventory: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+
+	plan, err := s.planInventory(f, InventoryFormatForPath(path))
+	if err != nil {
+		return domain.DiffReport{}, err
+	}
+	if !prune {
+		plan.unlisted = nil
+	}
+	if err := s.applyInventoryPlan(plan); err != nil {
+		return domain.DiffReport{}, err
+	}
+	defer s.forgetResolved()
+	for _, srv := range plan.unlisted {
+		if err := s.serverRepository.DeleteServer(srv); err != nil {
+			s.logger.Errorw("failed to prune server", "error", err, "alias", srv.Alias)
+			return domain.DiffReport{}, fmt.Errorf("server '%s': %w", srv.Alias, err)
+		}
+	}
+	return plan.report(), nil
+}
+
+// InventoryFormatForPath picks the inventory format from a file extension, defaulting to YAML.
+func InventoryFormatForPath(path string) string {
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		return InventoryFormatJSON
+	}
+	return InventoryFormatYAML
+}
+
+// inventoryEntry is a listed server whose configuration differs from the inventory.
+type inventoryEntry struct {
+	item    inventoryServer
+	server  domain.Server
+	current domain.Server
+	exists  bool
+	changes []string
+}
+
+// inventoryPlan is the set of changes needed to make the configured servers match an inventory.
+type inventoryPlan struct {
+	entries []inventoryEntry
+	// unlisted are configured servers the inventory does not mention.
+	unlisted []domain.Server
+}
+
+// report summarises the plan for display.
+func (p inventoryPlan) report() domain.DiffReport {
+	var report domain.DiffReport
+	for _, e := range p.entries {
+		if !e.exists {
+			report.Added = append(report.Added, e.server.Alias)
+			continue
+		}
+		report.Changed = append(report.Changed, domain.ServerChange{Alias: e.server.Alias, Changes: e.changes})
+	}
+	for _, srv := range p.unlisted {
+		report.Removed = append(report.Removed, srv.Alias)
+	}
+	return report
+}
+
+// planInventory decodes and validates an inventory and compares it with the configured servers.
+func (s *serverService) planInventory(r io.Reader, format string) (inventoryPlan, error) {
+	doc, err := decodeInventory(r, format)
+	if err != nil {
+		return inventoryPlan{}, err
+	}
+
+	wanted := make([]domain.Server, 0, len(doc.Servers))
+	seen := make(map[string]bool, len(doc.Servers))
+	for _, item := range doc.Servers {
+		srv, err := fromInventoryServer(item)
+		if err != nil {
+			return inventoryPlan{}, fmt.Errorf("server '%s': %w", item.Alias, err)
+		}
+		if err := validateServer(srv); err != nil {
+			return inventoryPlan{}, fmt.Errorf("server '%s': %w", item.Alias, err)
+		}
+		if seen[srv.Alias] {
+			return inventoryPlan{}, fmt.Errorf("server '%s' is listed more than once", srv.Alias)
+		}
+		seen[srv.Alias] = true
+		wanted = append(wanted, srv)
+	}
+
+	servers, err := s.serverRepository.ListServers("")
+	if err != nil {
+		s.logger.Errorw("failed to list servers for inventory", "error", err)
+		return inventoryPlan{}, err
+	}
+	sort.SliceStable(servers, func(i, j int) bool { return servers[i].ConfigOrder < servers[j].ConfigOrder })
+	existing := make(map[string]domain.Server, len(servers))
+	var plan inventoryPlan
+	for _, srv := range servers {
+		existing[srv.Alias] = srv
+		if !seen[srv.Alias] {
+			plan.unlisted = append(plan.unlisted, srv)
+		}
+	}
+
+	for i, srv := range wanted {
+		item := doc.Servers[i]
+		current, ok := existing[srv.Alias]
+		want := toInventoryServer(srv)
+		want.Pinned = item.Pinned
+		entry := inventoryEntry{item: item, server: srv, current: current, exists: ok}
+		if ok {
+			entry.changes = diffInventoryServers(toInventoryServer(current), want)
+			if len(entry.changes) == 0 {
+				continue
+			}
+		}
+		plan.entries = append(plan.entries, entry)
+	}
+	return plan, nil
+}
+
+// decodeInventory reads an inventory document in the given format.
+func decodeInventory(r io.Reader, format string) (inventory, error) {
+	var doc inventory
+	switch normalizeInventoryFormat(format) {
+	case InventoryFormatYAML:
+		dec := yaml.NewDecoder(r)
+		dec.KnownFields(true)
+		if err := dec.Decode(&doc); err != nil && err != io.EOF {
+			return inventory{}, fmt.Errorf("decode inventory: %w", err)
+		}
+	case InventoryFormatJSON:
+		dec := json.NewDecoder(r)
+		dec.DisallowUnknownFields()
+		if err := dec.Decode(&doc); err != nil {
+			return inventory{}, fmt.Errorf("decode inventory: %w", err)
+		}
+	default:
+		return inventory{}, fmt.Errorf("unsupported inventory format %q (use yaml or json)", format)
+	}
+	if doc.Version > inventoryVersion {
+		return inventory{}, fmt.Errorf("inventory version %d is newer than supported version %d", doc.Version, inventoryVersion)
+	}
+	return doc, nil
+}
+
+// applyInventoryPlan adds and updates the servers in plan, then applies their pin and
+// session logging metadata.
+func (s *serverService) applyInventoryPlan(plan inventoryPlan) error {
+	defer s.forgetResolved()
+	pinOrders := make(map[string]int)
+	for _, e := range plan.entries {
+		var err error
+		if e.exists {
+			// The inventory carries pins as a flag, applied below; keep the current pin so a
+			// repository storing pins with the server (metadata_in_config) does not drop it.
+			e.server.PinnedAt = e.current.PinnedAt
+			err = s.serverRepository.UpdateServer(e.current, e.server)
+		} else {
+			err = s.serverRepository.AddServer(e.server)
+		}
+		if err != nil {
+			s.logger.Errorw("failed to apply inventory server", "error", err, "alias", e.server.Alias)
+			return fmt.Errorf("server '%s': %w", e.server.Alias, err)
+		}
+
+		alias := e.server.Alias
+		if e.item.Pinned != !e.current.PinnedAt.IsZero() {
+			if err := s.serverRepository.SetPinned(alias, e.item.Pinned); err != nil {
+				return fmt.Errorf("server '%s': %w", alias, err)
+			}
+		}
+		if e.item.Pinned && e.item.PinOrder != e.current.PinOrder {
+			pinOrders[alias] = e.item.PinOrder
+		}
+		if !reflect.DeepEqual(e.item.SessionLogging, e.current.SessionLogging) {
+			if err := s.serverRepository.SetSessionLogging(alias, e.item.SessionLogging); err != nil {
+				return fmt.Errorf("server '%s': %w", alias, err)
+			}
+		}
+		if !reflect.DeepEqual(e.item.AutoTunnels, e.current.AutoTunnels) || e.item.AutoTunnelsOff != e.current.AutoTunnelsDisabled {
+			if err := s.serverRepository.SetAutoTunnels(alias, e.item.AutoTunnels, e.item.AutoTunnelsOff); err != nil {
+				return fmt.Errorf("server '%s': %w", alias, err)
+			}
+		}
+	}
+	if len(pinOrders) > 0 {
+		if err := s.serverRepository.SetPinOrder(pinOrders); err != nil {
+			return fmt.Errorf("set pin order: %w", err)
+		}
+	}
+	return nil
+}
+
+// diffInventoryServers describes each difference between the current and wanted inventory
+// entries as "key: old -> new".
+func diffInventoryServers(current, want inventoryServer) []string {
+	var changes []string
+	add := func(key string, from, to any) {
+		if !reflect.DeepEqual(from, to) {
+			changes = append(changes, fmt.Sprintf("%s: %s -> %s", key, inventoryValue(from), inventoryValue(to)))
+		}
+	}
+	add("host", current.Host, want.Host)
+	add("user", current.User, want.User)
+	add("port", current.Port, want.Port)
+	add("identity_files", current.IdentityFiles, want.IdentityFiles)
+	add("tags", current.Tags, want.Tags)
+	add("pinned", current.Pinned, want.Pinned)
+	if want.Pinned {
+		add("pin_order", current.PinOrder, want.PinOrder)
+	}
+	add("session_logging", current.SessionLogging, want.SessionLogging)
+	add("auto_tunnels", current.AutoTunnels, want.AutoTunnels)
+	add("auto_tunnels_disabled", current.AutoTunnelsOff, want.AutoTunnelsOff)
+	add("requires_vpn", current.RequiresVPN, want.RequiresVPN)
+	add("ping_target", current.PingTarget, want.PingTarget)
+	for _, key := range unionKeys(current.Options, want.Options) {
+		add(key, current.Options[key], want.Options[key])
+	}
+	for _, key := range unionKeys(current.ListOptions, want.ListOptions) {
+		add(key, current.ListOptions[key], want.ListOptions[key])
+	}
+	return changes
+}
+
+// inventoryValue formats a value for diffInventoryServers, showing zero values as (unset).
+func inventoryValue(v any) string {
+	switch val := v.(type) {
+	case *bool:
+		if val == nil {
+			return "(unset)"
+		}
+		return fmt.Sprint(*val)
+	case []string:
+		if len(val) == 0 {
+			return "(unset)"
+		}
+		return strings.Join(val, ", ")
+	case string:
+		if val == "" {
+			return "(unset)"
+		}
+	case int:
+		if val == 0 {
+			return "(unset)"
+		}
+	}
+	return fmt.Sprint(v)
+}
+
+// unionKeys returns the sorted keys present in either map.
+func unionKeys[V any](a, b map[string]V) []string {
+	keys := make([]string, 0, len(a)+len(b))
+	for k := range a {
+		keys = append(keys, k)
+	}
+	for k := range b {
+		if _, ok := a[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// normalizeInventoryFormat lower-cases format and maps "yml" and "" to YAML.
+func normalizeInventoryFormat(format string) string {
+	switch f := strings.ToLower(strings.TrimSpace(format)); f {
+	case "", "yml":
+		return InventoryFormatYAML
+	default:
+		return f
+	}
+}
+
+// toInventoryServer converts srv to its inventory form. Empty values are dropped and the
+// default port 22 is omitted so that unchanged servers compare equal after a round trip.
+func toInventoryServer(srv domain.Server) inventoryServer {
+	item := inventoryServer{
+		Alias:          srv.Alias,
+		Host:           srv.Host,
+		User:           srv.User,
+		Pinned:         !srv.PinnedAt.IsZero(),
+		PinOrder:       srv.PinOrder,
+		SessionLogging: srv.SessionLogging,
+		AutoTunnelsOff: srv.AutoTunnelsDisabled,
+		RequiresVPN:    srv.RequiresVPN,
+		PingTarget:     srv.PingTarget,
+	}
+	if srv.Port != 22 {
+		item.Port = srv.Port
+	}
+	if len(srv.IdentityFiles) > 0 {
+		item.IdentityFiles = srv.IdentityFiles
+	}
+	if len(srv.Tags) > 0 {
+		item.Tags = srv.Tags
+	}
+	if len(srv.AutoTunnels) > 0 {
+		item.AutoTunnels = srv.AutoTunnels
+	}
+
+	v := reflect.ValueOf(srv)
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		name := t.Field(i).Name
+		if inventoryDedicated(name) {
+			continue
+		}
+		switch field := v.Field(i).Interface().(type) {
+		case string:
+			if field == "" {
+				continue
+			}
+			if item.Options == nil {
+				item.Options = make(map[string]string)
+			}
+			item.Options[name] = field
+		case []string:
+			if len(field) == 0 {
+				continue
+			}
+			if item.ListOptions == nil {
+				item.ListOptions = make(map[string][]string)
+			}
+			item.ListOptions[name] = field
+		}
+	}
+	return item
+}
+
+// fromInventoryServer builds the server described by item, rejecting unknown directives
+// and a ping target that is not host:port.
+func fromInventoryServer(item inventoryServer) (domain.Server, error) {
+	if item.PingTarget != "" {
+		if err := validatePingTarget(item.PingTarget); err != nil {
+			return domain.Server{}, err
+		}
+	}
+	srv := domain.Server{
+		Alias:               item.Alias,
+		Host:                item.Host,
+		User:                item.User,
+		Port:                item.Port,
+		IdentityFiles:       item.IdentityFiles,
+		Tags:                item.Tags,
+		PinOrder:            item.PinOrder,
+		SessionLogging:      item.SessionLogging,
+		AutoTunnels:         item.AutoTunnels,
+		AutoTunnelsDisabled: item.AutoTunnelsOff,
+		RequiresVPN:         item.RequiresVPN,
+		PingTarget:          item.PingTarget,
+	}
+
+	v := reflect.ValueOf(&srv).Elem()
+	for name, value := range item.Options {
+		field, err := inventoryOptionField(v, name, reflect.String)
+		if err != nil {
+			return domain.Server{}, err
+		}
+		field.SetString(value)
+	}
+	for name, values := range item.ListOptions {
+		field, err := inventoryOptionField(v, name, reflect.Slice)
+		if err != nil {
+			return domain.Server{}, err
+		}
+		field.Set(reflect.ValueOf(values))
+	}
+	return srv, nil
+}
+
+// inventoryOptionField returns the domain.Server field named by an inventory option.
+func inventoryOptionField(v reflect.Value, name string, kind reflect.Kind) (reflect.Value, error) {
+	field := v.FieldByName(name)
+	if inventoryDedicated(name) || !field.IsValid() || field.Kind() != kind {
+		return reflect.Value{}, fmt.Errorf("unknown option %q", name)
+	}
+	if kind == reflect.Slice && field.Type().Elem().Kind() != reflect.String {
+		return reflect.Value{}, fmt.Errorf("unknown option %q", name)
+	}
+	return field, nil
+}
@@ -0,0 +1,252 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package services
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Adembc/lazyssh/internal/adapters/data/ssh_config_file"
+	"github.com/Adembc/lazyssh/internal/config"
+	"github.com/Adembc/lazyssh/internal/core/domain"
+	"go.uber.org/zap"
+	"gopkg.in/yaml.v3"
+)
+
+func TestInventoryServerRoundTrip(t *testing.T) {
+	srv := domain.Server{
+		Alias:         "db",
+		Host:          "10.0.0.7",
+		User:          "postgres",
+		Port:          2222,
+		IdentityFiles: []string{"~/.ssh/id_db"},
+		Tags:          []string{"prod"},
+		ProxyJump:     "bastion",
+		LocalForward:  []string{"5432:localhost:5432"},
+		SSHCount:      4,
+	}
+
+	item := toInventoryServer(srv)
+	if item.Options["ProxyJump"] != "bastion" {
+		t.Errorf("Options = %v, want ProxyJump=bastion", item.Options)
+	}
+	if _, ok := item.Options["LastError"]; ok {
+		t.Error("usage statistics should not be exported")
+	}
+
+	data, err := yaml.Marshal(item)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded inventoryServer
+	if err := yaml.Unmarshal(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	got, err := fromInventoryServer(decoded)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	srv.SSHCount = 0
+	if !reflect.DeepEqual(got, srv) {
+		t.Errorf("round trip = %+v, want %+v", got, srv)
+	}
+}
+
+func TestInventoryDefaultPortOmitted(t *testing.T) {
+	if item := toInventoryServer(domain.Server{Alias: "web", Host: "web.lan", Port: 22}); item.Port != 0 {
+		t.Errorf("Port = %d, want 0 for the default port", item.Port)
+	}
+}
+
+func TestServerJSON(t *testing.T) {
+	pinned := time.Date(2025, 3, 1, 9, 30, 0, 0, time.UTC)
+	tests := []struct {
+		name    string
+		srv     domain.Server
+		want    map[string]any
+		missing []string
+	}{
+		{
+			name: "full record",
+			srv: domain.Server{
+				Alias: "db", Host: "10.0.0.7", User: "postgres", Port: 2222,
+				Tags: []string{"prod"}, PinnedAt: pinned, SSHCount: 4, ProxyJump: "bastion",
+			},
+			want: map[string]any{
+				"alias": "db", "host": "10.0.0.7", "user": "postgres", "port": float64(2222),
+				"pinned": true, "pinned_at": "2025-03-01T09:30:00Z", "ssh_count": float64(4),
+				"tags":    []any{"prod"},
+				"options": map[string]any{"ProxyJump": "bastion"},
+			},
+		},
+		{
+			name:    "unused server omits timestamps",
+			srv:     domain.Server{Alias: "web", Host: "web.lan", Port: 22},
+			want:    map[string]any{"alias": "web", "ssh_count": float64(0)},
+			missing: []string{"port", "pinned_at", "last_seen", "last_error_at"},
+		},
+	}
+	s := &serverService{}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, err := s.ServerJSON(tt.srv)
+			if err != nil {
+				t.Fatalf("ServerJSON: %v", err)
+			}
+			if !strings.Contains(out, "\n  \"alias\"") {
+				t.Errorf("output is not indented:\n%s", out)
+			}
+			var got map[string]any
+			if err := json.Unmarshal([]byte(out), &got); err != nil {
+				t.Fatalf("output is not JSON: %v", err)
+			}
+			for key, want := range tt.want {
+				if !reflect.DeepEqual(got[key], want) {
+					t.Errorf("%s = %#v, want %#v", key, got[key], want)
+				}
+			}
+			for _, key := range tt.missing {
+				if _, ok := got[key]; ok {
+					t.Errorf("%s present, want omitted", key)
+				}
+			}
+		})
+	}
+}
+
+func TestFromInventoryServerRejectsUnknownOptions(t *testing.T) {
+	tests := []inventoryServer{
+		{Alias: "a", Host: "h", Options: map[string]string{"NoSuchOption": "yes"}},
+		{Alias: "a", Host: "h", Options: map[string]string{"LocalForward": "80:localhost:80"}},
+		{Alias: "a", Host: "h", ListOptions: map[string][]string{"ProxyJump": {"bastion"}}},
+		{Alias: "a", Host: "h", Options: map[string]string{"LastError": "boom"}},
+	}
+	for _, item := range tests {
+		if _, err := fromInventoryServer(item); err == nil || !strings.Contains(err.Error(), "unknown option") {
+			t.Errorf("fromInventoryServer(%+v) error = %v, want unknown option", item, err)
+		}
+	}
+}
+
+func TestFromInventoryServerValidatesPingTarget(t *testing.T) {
+	tests := []struct {
+		target  string
+		wantErr bool
+	}{
+		{"", false},
+		{"db.internal:5432", false},
+		{"10.0.0.7:443", false},
+		{"[::1]:22", false},
+		{"db.internal", true},
+		{"db.internal:0", true},
+		{"db.internal:http", true},
+		{":22", true},
+		{"bad host:22", true},
+	}
+	for _, tt := range tests {
+		_, err := fromInventoryServer(inventoryServer{Alias: "a", Host: "h", PingTarget: tt.target})
+		if (err != nil) != tt.wantErr {
+			t.Errorf("fromInventoryServer(ping_target %q) error = %v, wantErr %v", tt.target, err, tt.wantErr)
+		}
+	}
+
+	doc := "version: 1\nservers:\n  - alias: shared\n    host: shared.example.com\n    ping_target: shared.example.com\n"
+	if _, err := parseRemoteInventory([]byte(doc)); err == nil || !strings.Contains(err.Error(), "ping target") {
+		t.Errorf("parseRemoteInventory error = %v, want a ping target error", err)
+	}
+}
+
+func TestNormalizeInventoryFormat(t *testing.T) {
+	for in, want := range map[string]string{"": "yaml", "YML": "yaml", "yaml": "yaml", "JSON": "json", "toml": "toml"} {
+		if got := normalizeInventoryFormat(in); got != want {
+			t.Errorf("normalizeInventoryFormat(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestDiffInventoryServers(t *testing.T) {
+	current := inventoryServer{
+		Alias:   "db",
+		Host:    "10.0.0.7",
+		Tags:    []string{"prod"},
+		Options: map[string]string{"ProxyJump": "bastion", "User": "ignored"},
+	}
+	want := inventoryServer{
+		Alias:       "db",
+		Host:        "10.0.0.8",
+		Port:        2222,
+		Tags:        []string{"prod"},
+		Options:     map[string]string{"User": "ignored"},
+		ListOptions: map[string][]string{"LocalForward": {"5432:localhost:5432"}},
+	}
+
+	got := diffInventoryServers(current, want)
+	expected := []string{
+		"host: 10.0.0.7 -> 10.0.0.8",
+		"port: (unset) -> 2222",
+		"ProxyJump: bastion -> (unset)",
+		"LocalForward: (unset) -> 5432:localhost:5432",
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("diffInventoryServers() = %q, want %q", got, expected)
+	}
+
+	if changes := diffInventoryServers(current, current); len(changes) != 0 {
+		t.Errorf("identical entries should not differ, got %q", changes)
+	}
+}
+
+func TestApplyInventoryKeepsPinsInConfig(t *testing.T) {
+	dir := t.TempDir()
+	configPath := filepath.Join(dir, "config")
+	pinned := "Host db\n    # lazyssh-pinned: 2025-01-02T03:04:05Z\n    HostName 10.0.0.7\n"
+	if err := os.WriteFile(configPath, []byte(pinned), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	inventoryPath := filepath.Join(dir, "inventory.yaml")
+	doc := "version: 1\nservers:\n  - alias: db\n    host: 10.0.0.8\n    pinned: true\n"
+	if err := os.WriteFile(inventoryPath, []byte(doc), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	log := zap.NewNop().Sugar()
+	cfg := config.Config{MetadataInConfig: true}
+	repo := ssh_config_file.NewRepository(log, configPath, filepath.Join(dir, "metadata.json"), cfg)
+	s := &serverService{logger: log, serverRepository: repo}
+
+	report, err := s.ApplyInventory(inventoryPath, false)
+	if err != nil {
+		t.Fatalf("ApplyInventory: %v", err)
+	}
+	if len(report.Changed) != 1 {
+		t.Fatalf("report = %+v, want db changed", report)
+	}
+	servers, err := repo.ListServers("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(servers) != 1 || servers[0].Host != "10.0.0.8" {
+		t.Fatalf("servers = %+v, want db updated", servers)
+	}
+	if servers[0].PinnedAt.IsZero() {
+		t.Errorf("db was unpinned by apply")
+	}
+}
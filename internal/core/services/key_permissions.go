@@ -0,0 +1,66 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package services
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// looseKeyPermBits are the group and other permission bits that make ssh refuse a private key.
+const looseKeyPermBits os.FileMode = 0o077
+
+// privateKeyPerm is the mode FixKeyPermissions applies.
+const privateKeyPerm os.FileMode = 0o600
+
+// CheckKeyPermissions reports whether the private key at path is private enough for ssh,
+// along with its permission bits. Missing files are reported ok (ssh will complain about
+// them itself), as are keys on Windows where access is governed by ACLs, not mode bits.
+func (s *serverService) CheckKeyPermissions(path string) (bool, os.FileMode) {
+	info, err := os.Stat(expandKeyPath(path))
+	if err != nil {
+		return true, 0
+	}
+	mode := info.Mode().Perm()
+	if runtime.GOOS == "windows" {
+		return true, mode
+	}
+	return mode&looseKeyPermBits == 0, mode
+}
+
+// FixKeyPermissions restricts the private key at path to its owner (chmod 600).
+func (s *serverService) FixKeyPermissions(path string) error {
+	if err := os.Chmod(expandKeyPath(path), privateKeyPerm); err != nil {
+		s.logger.Errorw("failed to fix key permissions", "error", err, "path", path)
+		return fmt.Errorf("chmod %s: %w", path, err)
+	}
+	s.logger.Infow("restricted key permissions", "path", path, "mode", privateKeyPerm)
+	return nil
+}
+
+// expandKeyPath resolves a leading ~ the way ssh does for IdentityFile.
+func expandKeyPath(path string) string {
+	if path != "~" && !strings.HasPrefix(path, "~/") {
+		return path
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return path
+	}
+	return filepath.Join(home, path[1:])
+}
@@ -0,0 +1,56 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package services
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"go.uber.org/zap"
+)
+
+func TestCheckAndFixKeyPermissions(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("key permissions are not checked on Windows")
+	}
+
+	s := &serverService{logger: zap.NewNop().Sugar()}
+	key := filepath.Join(t.TempDir(), "id_ed25519")
+	if err := os.WriteFile(key, []byte("key"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chmod(key, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	ok, mode := s.CheckKeyPermissions(key)
+	if ok || mode != 0o644 {
+		t.Errorf("CheckKeyPermissions() = %v, %04o, want false, 0644", ok, mode)
+	}
+
+	if err := s.FixKeyPermissions(key); err != nil {
+		t.Fatalf("FixKeyPermissions() error = %v", err)
+	}
+	ok, mode = s.CheckKeyPermissions(key)
+	if !ok || mode != 0o600 {
+		t.Errorf("after fix CheckKeyPermissions() = %v, %04o, want true, 0600", ok, mode)
+	}
+
+	if ok, _ := s.CheckKeyPermissions(filepath.Join(t.TempDir(), "missing")); !ok {
+		t.Errorf("CheckKeyPermissions(missing) = false, want true")
+	}
+}
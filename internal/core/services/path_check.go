@@ -0,0 +1,239 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/Adembc/lazyssh/internal/core/domain"
+)
+
+const (
+	// pathCheckConnectTimeout is ssh's ConnectTimeout, in seconds, for each hop of a path check.
+	pathCheckConnectTimeout = 5
+	// pathCheckTimeout bounds a whole path check before the ssh process is killed.
+	pathCheckTimeout = 30 * time.Second
+	// sshErrorExit is the exit status ssh uses for its own errors; any other non-zero status
+	// comes from the remote command, so the connection itself worked.
+	sshErrorExit = 255
+)
+
+var (
+	connectFailedRe = regexp.MustCompile(`connect to host (\S+) port \d+: `)
+	resolveFailedRe = regexp.MustCompile(`Could not resolve hostname ([^:\s]+)`)
+	deniedRe        = regexp.MustCompile(`^(?:\S+@)?(\S+): Permission denied`)
+	hostKeyRe       = regexp.MustCompile(`(?i)host key (?:is known )?for (\S+?)\.? (?:and|has)`)
+)
+
+// pathHop is one ProxyJump hop: its spec as written and the host names ssh may print for it.
+type pathHop struct {
+	spec  string
+	names []string
+}
+
+// CheckPath connects to alias through its whole ProxyJump/ProxyCommand chain without
+// prompting, runs a no-op command, and reports at which hop and stage it failed.
+func (s *serverService) CheckPath(alias string) domain.PathCheck {
+	dest, _ := s.resolve(alias)
+	hops := pathHops(dest.proxyJump, s.resolve)
+
+	ctx, cancel := context.WithTimeout(context.Background(), pathCheckTimeout)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, sshBinary(), pathCheckArgs(alias)...)
+	var stderr strings.Builder
+	cmd.Stderr = &stderr
+
+	start := time.Now()
+	err := cmd.Run()
+	var check domain.PathCheck
+	var exitErr *exec.ExitError
+	switch {
+	case err == nil, errors.As(err, &exitErr) && exitErr.ExitCode() != sshErrorExit:
+		check = domain.PathCheck{Stage: domain.PathOK}
+	case ctx.Err() != nil:
+		check = domain.PathCheck{Stage: domain.PathUnknown, Message: fmt.Sprintf("no answer within %s", pathCheckTimeout)}
+	default:
+		check = classifyPathFailure(stderr.String(), alias, dest, hops)
+	}
+	check.Proxy = describeProxy(dest)
+	check.Duration = time.Since(start)
+	s.logger.Infow("path check", "alias", alias, "stage", check.Stage, "hop", check.Hop, "message", check.Message)
+	return check
+}
+
+// pathCheckArgs returns the ssh arguments for CheckPath. Multiplexing is disabled so an
+// existing master connection cannot hide a broken path, and RemoteCommand and RequestTTY
+// are overridden so entries meant for interactive sessions do not break the check.
+func pathCheckArgs(alias string) []string {
+	return []string{
+		"-o", "BatchMode=yes",
+		"-o", fmt.Sprintf("ConnectTimeout=%d", pathCheckConnectTimeout),
+		"-o", "ControlMaster=no",
+		"-o", "ControlPath=none",
+		"-o", "RemoteCommand=none",
+		"-o", "RequestTTY=no",
+		alias, "true",
+	}
+}
+
+// describeProxy names the chain in front of the target; empty for a direct connection.
+func describeProxy(dest sshDestination) string {
+	if dest.proxyJump != "" && !strings.EqualFold(dest.proxyJump, "none") {
+		return dest.proxyJump
+	}
+	return dest.proxyCommand
+}
+
+// pathHops splits a ProxyJump value into its hops. Each hop is known by the name written in
+// the spec and, when it is itself a config alias, the HostName it resolves to.
+func pathHops(proxyJump string, resolve func(string) (sshDestination, bool)) []pathHop {
+	if proxyJump == "" || strings.EqualFold(proxyJump, "none") {
+		return nil
+	}
+	var hops []pathHop
+	for _, spec := range strings.Split(proxyJump, ",") {
+		spec = strings.TrimSpace(spec)
+		if spec == "" {
+			continue
+		}
+		host := jumpHost(spec)
+		hop := pathHop{spec: spec, names: []string{host}}
+		if d, ok := resolve(host); ok && !strings.EqualFold(d.host, host) {
+			hop.names = append(hop.names, d.host)
+		}
+		hops = append(hops, hop)
+	}
+	return hops
+}
+
+// jumpHost extracts the host from a ProxyJump hop: [ssh://][user@]host[:port].
+func jumpHost(spec string) string {
+	host := strings.TrimPrefix(spec, "ssh://")
+	if i := strings.LastIndex(host, "@"); i >= 0 {
+		host = host[i+1:]
+	}
+	if strings.HasPrefix(host, "[") {
+		if i := strings.Index(host, "]"); i > 0 {
+			return host[1:i]
+		}
+	}
+	if i := strings.LastIndex(host, ":"); i >= 0 && strings.Count(host, ":") == 1 {
+		host = host[:i]
+	}
+	return host
+}
+
+// classifyPathFailure reads ssh's stderr from a failed path check and works out which hop
+// failed and how. The first line that names a stage decides; the generic "connection closed"
+// lines ssh prints when a proxy dies only count when nothing more specific was said.
+func classifyPathFailure(stderr, alias string, dest sshDestination, hops []pathHop) domain.PathCheck {
+	proxy := describeProxy(dest)
+	target := dest.host
+	if target == "" {
+		target = alias
+	}
+	// hopFor maps a host named by ssh to a proxy hop; ok is false for the target itself.
+	hopFor := func(host string) (string, bool) {
+		for _, h := range hops {
+			for _, n := range h.names {
+				if strings.EqualFold(n, host) {
+					return h.spec, true
+				}
+			}
+		}
+		if strings.EqualFold(host, target) || strings.EqualFold(host, alias) || proxy == "" {
+			return "", false
+		}
+		// Named by a ProxyCommand (e.g. "ssh -W %h:%p bastion"): not the target, so the proxy.
+		return host, true
+	}
+	firstHop := proxy
+	if len(hops) > 0 {
+		firstHop = hops[0].spec
+	}
+	lastHop := proxy
+	if len(hops) > 0 {
+		lastHop = hops[len(hops)-1].spec
+	}
+
+	proxyClosed := ""
+	for _, line := range strings.Split(strings.ReplaceAll(stderr, "\r\n", "\n"), "\n") {
+		line = strings.TrimSpace(line)
+		lower := strings.ToLower(line)
+		switch {
+		case line == "":
+			continue
+		case connectFailedRe.MatchString(line):
+			host := connectFailedRe.FindStringSubmatch(line)[1]
+			if hop, ok := hopFor(host); ok {
+				return domain.PathCheck{Stage: domain.PathProxyUnreachable, Hop: hop, Message: line}
+			}
+			return domain.PathCheck{Stage: domain.PathTargetUnreachable, Hop: target, Message: line}
+		case resolveFailedRe.MatchString(line):
+			host := resolveFailedRe.FindStringSubmatch(line)[1]
+			if hop, ok := hopFor(host); ok {
+				return domain.PathCheck{Stage: domain.PathProxyUnreachable, Hop: hop, Message: line}
+			}
+			return domain.PathCheck{Stage: domain.PathTargetUnreachable, Hop: target, Message: line}
+		case strings.Contains(lower, "open failed") || strings.Contains(lower, "stdio forwarding failed"):
+			// The last proxy is up but could not open the forwarded connection to the target.
+			return domain.PathCheck{Stage: domain.PathTargetUnreachable, Hop: target, Message: line}
+		case strings.Contains(lower, "permission denied"):
+			if m := deniedRe.FindStringSubmatch(line); m != nil {
+				if hop, ok := hopFor(m[1]); ok {
+					return domain.PathCheck{Stage: domain.PathProxyAuth, Hop: hop, Message: line}
+				}
+				return domain.PathCheck{Stage: domain.PathTargetAuth, Hop: target, Message: line}
+			}
+			// Older ssh versions do not name the host; a proxy failing takes the session down
+			// with a "connection closed" line, which the target's own failure does not print.
+			if proxy != "" && proxyClosedLine(stderr) {
+				return domain.PathCheck{Stage: domain.PathProxyAuth, Hop: firstHop, Message: line}
+			}
+			return domain.PathCheck{Stage: domain.PathTargetAuth, Hop: target, Message: line}
+		case hostKeyRe.MatchString(line):
+			host := hostKeyRe.FindStringSubmatch(line)[1]
+			if hop, ok := hopFor(host); ok {
+				return domain.PathCheck{Stage: domain.PathHostKey, Hop: hop, Message: line}
+			}
+			return domain.PathCheck{Stage: domain.PathHostKey, Hop: target, Message: line}
+		case proxyClosed == "" && proxyClosedLine(line):
+			proxyClosed = line
+		}
+	}
+	if proxyClosed != "" && proxy != "" {
+		return domain.PathCheck{Stage: domain.PathProxyUnreachable, Hop: lastHop, Message: proxyClosed}
+	}
+	msg := lastLine(stderr)
+	if strings.Contains(strings.ToLower(msg), "host key verification failed") {
+		return domain.PathCheck{Stage: domain.PathHostKey, Message: msg}
+	}
+	return domain.PathCheck{Stage: domain.PathUnknown, Message: msg}
+}
+
+// proxyClosedLine reports whether s contains one of the lines ssh prints when the proxy
+// side of the connection went away before the SSH handshake finished.
+func proxyClosedLine(s string) bool {
+	lower := strings.ToLower(s)
+	return strings.Contains(lower, "closed by unknown port 65535") ||
+		strings.Contains(lower, "kex_exchange_identification") ||
+		strings.Contains(lower, "banner exchange")
+}
@@ -0,0 +1,150 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package services
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/Adembc/lazyssh/internal/core/domain"
+)
+
+func TestClassifyPathFailure(t *testing.T) {
+	jump := sshDestination{host: "10.0.0.5", proxyJump: "bastion"}
+	hops := []pathHop{{spec: "bastion", names: []string{"bastion", "bastion.example.com"}}}
+	cmd := sshDestination{host: "10.0.0.5", proxyCommand: "ssh -W %h:%p gw"}
+	direct := sshDestination{host: "10.0.0.5"}
+
+	tests := []struct {
+		name      string
+		stderr    string
+		dest      sshDestination
+		hops      []pathHop
+		wantStage domain.PathStage
+		wantHop   string
+	}{
+		{
+			name:      "jump host refused",
+			stderr:    "ssh: connect to host bastion.example.com port 22: Connection refused\r\nConnection closed by UNKNOWN port 65535\r\n",
+			dest:      jump,
+			hops:      hops,
+			wantStage: domain.PathProxyUnreachable,
+			wantHop:   "bastion",
+		},
+		{
+			name:      "jump host unknown",
+			stderr:    "ssh: Could not resolve hostname bastion: Name or service not known\nConnection closed by UNKNOWN port 65535\n",
+			dest:      jump,
+			hops:      hops,
+			wantStage: domain.PathProxyUnreachable,
+			wantHop:   "bastion",
+		},
+		{
+			name:      "target unreachable from jump",
+			stderr:    "channel 0: open failed: connect failed: No route to host\nstdio forwarding failed\nConnection closed by UNKNOWN port 65535\n",
+			dest:      jump,
+			hops:      hops,
+			wantStage: domain.PathTargetUnreachable,
+			wantHop:   "10.0.0.5",
+		},
+		{
+			name:      "auth failed on jump",
+			stderr:    "ops@bastion.example.com: Permission denied (publickey).\nConnection closed by UNKNOWN port 65535\n",
+			dest:      jump,
+			hops:      hops,
+			wantStage: domain.PathProxyAuth,
+			wantHop:   "bastion",
+		},
+		{
+			name:      "auth failed on target",
+			stderr:    "deploy@10.0.0.5: Permission denied (publickey,password).\n",
+			dest:      jump,
+			hops:      hops,
+			wantStage: domain.PathTargetAuth,
+			wantHop:   "10.0.0.5",
+		},
+		{
+			name:      "old ssh denied before proxy closed",
+			stderr:    "Permission denied (publickey).\nkex_exchange_identification: Connection closed by remote host\n",
+			dest:      jump,
+			hops:      hops,
+			wantStage: domain.PathProxyAuth,
+			wantHop:   "bastion",
+		},
+		{
+			name:      "unknown jump host key",
+			stderr:    "No ED25519 host key is known for bastion and you have requested strict checking.\nHost key verification failed.\n",
+			dest:      jump,
+			hops:      hops,
+			wantStage: domain.PathHostKey,
+			wantHop:   "bastion",
+		},
+		{
+			name:      "proxy command host down",
+			stderr:    "ssh: connect to host gw port 22: Connection timed out\nkex_exchange_identification: Connection closed by remote host\n",
+			dest:      cmd,
+			wantStage: domain.PathProxyUnreachable,
+			wantHop:   "gw",
+		},
+		{
+			name:      "proxy command died silently",
+			stderr:    "kex_exchange_identification: Connection closed by remote host\n",
+			dest:      cmd,
+			wantStage: domain.PathProxyUnreachable,
+			wantHop:   "ssh -W %h:%p gw",
+		},
+		{
+			name:      "direct connection refused",
+			stderr:    "ssh: connect to host 10.0.0.5 port 22: Connection refused\n",
+			dest:      direct,
+			wantStage: domain.PathTargetUnreachable,
+			wantHop:   "10.0.0.5",
+		},
+		{
+			name:      "unrecognised failure",
+			stderr:    "something odd happened\n",
+			dest:      direct,
+			wantStage: domain.PathUnknown,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := classifyPathFailure(tt.stderr, "app", tt.dest, tt.hops)
+			if got.Stage != tt.wantStage || got.Hop != tt.wantHop {
+				t.Errorf("classifyPathFailure() = %s at %q (%s), want %s at %q", got.Stage, got.Hop, got.Message, tt.wantStage, tt.wantHop)
+			}
+		})
+	}
+}
+
+func TestPathHops(t *testing.T) {
+	resolve := func(host string) (sshDestination, bool) {
+		if host == "bastion" {
+			return sshDestination{host: "bastion.example.com"}, true
+		}
+		return sshDestination{host: host}, true
+	}
+	got := pathHops("ops@bastion:2222,ssh://[fd00::1]:22", resolve)
+	want := []pathHop{
+		{spec: "ops@bastion:2222", names: []string{"bastion", "bastion.example.com"}},
+		{spec: "ssh://[fd00::1]:22", names: []string{"fd00::1"}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("pathHops() = %+v, want %+v", got, want)
+	}
+	if hops := pathHops("none", resolve); hops != nil {
+		t.Errorf("pathHops(none) = %+v, want nil", hops)
+	}
+}
@@ -0,0 +1,286 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package services
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Adembc/lazyssh/internal/config"
+	"github.com/Adembc/lazyssh/internal/core/domain"
+	"github.com/Adembc/lazyssh/internal/core/ports"
+	"go.uber.org/zap"
+)
+
+// remoteInventoryTimeout bounds one fetch of the remote inventory.
+const remoteInventoryTimeout = 10 * time.Second
+
+// remoteInventoryMaxSize caps the size of a fetched inventory document.
+const remoteInventoryMaxSize = 8 << 20
+
+// remoteInventoryRetry is the longest lazyssh waits before fetching again after a failure.
+const remoteInventoryRetry = time.Minute
+
+// remoteInventory is a ServerSource reading a shared YAML inventory (the format written by
+// ExportInventory) from an HTTPS URL. Watch fetches it in the background and caches it on
+// disk, reusing the cache for the TTL; Servers only returns the last result, so listing never
+// waits on the network. When a fetch fails the cached copy is used, however old.
+type remoteInventory struct {
+	logger    *zap.SugaredLogger
+	url       string
+	headers   map[string]string
+	ttl       time.Duration
+	cachePath string
+	client    *http.Client
+
+	mu      sync.Mutex
+	loaded  bool
+	servers []domain.Server
+	err     error
+}
+
+// NewRemoteInventory returns the ServerSource for cfg's remote_inventory_url, caching it at
+// cachePath, or nil when no URL is configured or it is not an https:// URL.
+func NewRemoteInventory(logger *zap.SugaredLogger, cfg config.Config, cachePath string) ports.ServerSource {
+	if cfg.RemoteInventoryURL == "" {
+		return nil
+	}
+	u, err := url.Parse(cfg.RemoteInventoryURL)
+	if err != nil || u.Scheme != "https" || u.Host == "" {
+		logger.Warnw("ignoring remote_inventory_url, it must be an https:// URL", "url", cfg.RemoteInventoryURL)
+		return nil
+	}
+	headers := make(map[string]string, len(cfg.RemoteInventoryHeaders))
+	for name, value := range cfg.RemoteInventoryHeaders {
+		headers[name] = os.ExpandEnv(value)
+	}
+	return &remoteInventory{
+		logger:    logger,
+		url:       cfg.RemoteInventoryURL,
+		headers:   headers,
+		ttl:       cfg.RemoteInventoryTTL(),
+		cachePath: cachePath,
+		client:    &http.Client{Timeout: remoteInventoryTimeout},
+	}
+}
+
+// Servers returns the servers last fetched by Watch, marked read-only, or the cached copy
+// before the first fetch. It never touches the network. An error is returned only when a
+// fetch failed and nothing is cached.
+func (r *remoteInventory) Servers() ([]domain.Server, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.loadCacheLocked()
+	return r.servers, r.err
+}
+
+// Watch fetches the inventory now and again whenever the fetched copy is older than the
+// TTL, sooner after a failure, calling onChange after each attempt. It returns when stop
+// is closed.
+func (r *remoteInventory) Watch(stop <-chan struct{}, onChange func()) {
+	for {
+		wait := r.refresh()
+		onChange()
+		timer := time.NewTimer(wait)
+		select {
+		case <-stop:
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+	}
+}
+
+// refresh brings the servers up to date, from the disk cache while it is fresh and from the
+// URL otherwise, and returns how long to wait before refreshing again.
+func (r *remoteInventory) refresh() time.Duration {
+	if info, err := os.Stat(r.cachePath); err == nil {
+		if age := time.Since(info.ModTime()); age < r.ttl {
+			if servers, err := r.readCache(); err == nil {
+				r.set(servers)
+				return r.ttl - age
+			}
+		}
+	}
+
+	data, err := r.fetch()
+	var servers []domain.Server
+	if err == nil {
+		servers, err = parseRemoteInventory(data)
+	}
+	if err == nil {
+		if werr := writeCacheFile(r.cachePath, data); werr != nil {
+			r.logger.Warnw("failed to cache remote inventory", "path", r.cachePath, "error", werr)
+		}
+		r.set(servers)
+		return r.ttl
+	}
+
+	r.logger.Warnw("failed to fetch remote inventory, using the cached copy", "url", r.url, "error", err)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.loadCacheLocked()
+	if r.servers == nil {
+		r.err = fmt.Errorf("remote inventory unavailable and not cached: %w", err)
+	}
+	return min(r.ttl, remoteInventoryRetry)
+}
+
+// set replaces the servers after a successful fetch or cache read.
+func (r *remoteInventory) set(servers []domain.Server) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.loaded, r.servers, r.err = true, servers, nil
+}
+
+// loadCacheLocked reads the cached copy, however old, the first time servers are needed.
+// r.mu must be held.
+func (r *remoteInventory) loadCacheLocked() {
+	if r.loaded {
+		return
+	}
+	r.loaded = true
+	if servers, err := r.readCache(); err == nil {
+		r.servers = servers
+	}
+}
+
+// fetch downloads the inventory document with the configured headers.
+func (r *remoteInventory) fetch() ([]byte, error) {
+	req, err := http.NewRequest(http.MethodGet, r.url, nil)
+	if err != nil {
+		return nil, err
+	}
+	for name, value := range r.headers {
+		req.Header.Set(name, value)
+	}
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("GET %s: %s", r.url, resp.Status)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, remoteInventoryMaxSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("read %s: %w", r.url, err)
+	}
+	if len(data) > remoteInventoryMaxSize {
+		return nil, fmt.Errorf("inventory at %s exceeds %d bytes", r.url, remoteInventoryMaxSize)
+	}
+	return data, nil
+}
+
+// readCache parses the cached inventory document.
+func (r *remoteInventory) readCache() ([]domain.Server, error) {
+	data, err := os.ReadFile(r.cachePath)
+	if err != nil {
+		return nil, err
+	}
+	return parseRemoteInventory(data)
+}
+
+// remoteRefusedFields are settings a remote inventory may not set. They run local commands
+// or open forwards when connecting, which would let whoever controls the inventory URL, or
+// the network path to it, act on this machine.
+var remoteRefusedFields = []string{
+	"ProxyCommand", "LocalCommand", "PermitLocalCommand", "RemoteCommand",
+	"LocalForward", "RemoteForward", "DynamicForward", "AutoTunnels",
+}
+
+// checkRemoteServer refuses a remote server that sets a field in remoteRefusedFields, or
+// whose alias or ProxyJump would be read by ssh as an option.
+func checkRemoteServer(srv domain.Server) error {
+	v := reflect.ValueOf(srv)
+	for _, name := range remoteRefusedFields {
+		if !v.FieldByName(name).IsZero() {
+			return fmt.Errorf("%s is not allowed in a remote inventory", name)
+		}
+	}
+	if strings.HasPrefix(srv.Alias, "-") || strings.HasPrefix(srv.ProxyJump, "-") {
+		return fmt.Errorf("alias and ProxyJump must not start with '-'")
+	}
+	for _, arg := range optionArgs(srv) {
+		if strings.ContainsAny(arg, "\r\n") {
+			return fmt.Errorf("values must not contain line breaks")
+		}
+	}
+	return nil
+}
+
+// parseRemoteInventory decodes a YAML inventory into read-only servers. Later entries with an
+// alias already seen are dropped; an entry refused by checkRemoteServer rejects the document.
+func parseRemoteInventory(data []byte) ([]domain.Server, error) {
+	doc, err := decodeInventory(bytes.NewReader(data), InventoryFormatYAML)
+	if err != nil {
+		return nil, err
+	}
+	servers := make([]domain.Server, 0, len(doc.Servers))
+	seen := make(map[string]bool, len(doc.Servers))
+	for _, item := range doc.Servers {
+		if item.Alias == "" || seen[item.Alias] {
+			continue
+		}
+		seen[item.Alias] = true
+		srv, err := fromInventoryServer(item)
+		if err == nil {
+			err = checkRemoteServer(srv)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("server %s: %w", item.Alias, err)
+		}
+		srv.Aliases = []string{srv.Alias}
+		if srv.Port == 0 {
+			srv.Port = 22
+		}
+		srv.PinOrder = 0
+		srv.ReadOnly = true
+		servers = append(servers, srv)
+	}
+	return servers, nil
+}
+
+// writeCacheFile replaces path with data through a temporary file, so a reader never sees a
+// partly written cache.
+func writeCacheFile(path string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	tmpPath := tmp.Name()
+	defer func() { _ = os.Remove(tmpPath) }() // only present if something failed before the rename
+
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmpPath, path)
+}
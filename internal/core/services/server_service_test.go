@@ -0,0 +1,359 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package services
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os/exec"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Adembc/lazyssh/internal/core/domain"
+	"go.uber.org/zap"
+)
+
+func TestExtractStaleFilter(t *testing.T) {
+	tests := []struct {
+		name      string
+		query     string
+		wantQuery string
+		wantStale *bool
+	}{
+		{name: "no filter", query: "prod web", wantQuery: "prod web"},
+		{name: "stale true", query: "stale:true", wantQuery: "", wantStale: boolPtr(true)},
+		{name: "stale false with text", query: "prod stale:false", wantQuery: "prod", wantStale: boolPtr(false)},
+		{name: "case insensitive", query: "Stale:TRUE db", wantQuery: "db", wantStale: boolPtr(true)},
+		{name: "unknown value is plain text", query: "stale:maybe", wantQuery: "stale:maybe"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotQuery, gotStale := extractStaleFilter(tt.query)
+			if gotQuery != tt.wantQuery {
+				t.Errorf("query = %q, want %q", gotQuery, tt.wantQuery)
+			}
+			switch {
+			case (gotStale == nil) != (tt.wantStale == nil):
+				t.Errorf("stale = %v, want %v", gotStale, tt.wantStale)
+			case gotStale != nil && *gotStale != *tt.wantStale:
+				t.Errorf("stale = %v, want %v", *gotStale, *tt.wantStale)
+			}
+		})
+	}
+}
+
+func boolPtr(b bool) *bool {
+	return &b
+}
+
+func TestValidateServerAlias(t *testing.T) {
+	tests := []struct {
+		alias   string
+		wantErr bool
+	}{
+		{alias: "web-01", wantErr: false},
+		{alias: "staging box", wantErr: false},
+		{alias: "staging  box", wantErr: true},
+		{alias: " staging", wantErr: true},
+		{alias: "web@01", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.alias, func(t *testing.T) {
+			err := validateServer(domain.Server{Alias: tt.alias, Host: "10.0.0.1"})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("validateServer(alias %q) error = %v, wantErr %v", tt.alias, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestParseSSHDestination(t *testing.T) {
+	tests := []struct {
+		name string
+		out  string
+		want sshDestination
+	}{
+		{
+			name: "direct",
+			out:  "user admin\nhostname 10.0.0.5\nport 2222\n",
+			want: sshDestination{host: "10.0.0.5", port: 2222},
+		},
+		{
+			name: "proxy jump",
+			out:  "hostname internal.lan\nport 22\nproxyjump bastion\n",
+			want: sshDestination{host: "internal.lan", port: 22, proxyJump: "bastion"},
+		},
+		{
+			name: "control path",
+			out:  "hostname h\ncontrolpath /home/u/.ssh/cm-u@h:22\n",
+			want: sshDestination{host: "h", port: 22, controlPath: "/home/u/.ssh/cm-u@h:22"},
+		},
+		{
+			name: "control path none",
+			out:  "hostname h\ncontrolpath none\n",
+			want: sshDestination{host: "h", port: 22},
+		},
+		{
+			name: "address family and bind address",
+			out:  "hostname h\naddressfamily inet\nbindaddress 10.0.0.9\n",
+			want: sshDestination{host: "h", port: 22, addressFamily: "inet", bindAddress: "10.0.0.9"},
+		},
+		{
+			name: "defaults",
+			out:  "user admin\n",
+			want: sshDestination{host: "web", port: 22},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseSSHDestination("web", tt.out); got != tt.want {
+				t.Errorf("parseSSHDestination() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestJumpProbeArgs(t *testing.T) {
+	got := strings.Join(jumpProbeArgs([]string{"db"}, "bastion", 3), " ")
+	want := "-o BatchMode=yes -o ConnectTimeout=3 -o RemoteCommand=none -o RequestTTY=no -J bastion db true"
+	if got != want {
+		t.Errorf("jumpProbeArgs() = %q, want %q", got, want)
+	}
+}
+
+func TestJumpTarget(t *testing.T) {
+	local := domain.Server{Alias: "db", Host: "10.0.0.7", ProxyJump: "bastion"}
+	if got := jumpTarget(local, literalDestination(local)); !reflect.DeepEqual(got, []string{"db"}) {
+		t.Errorf("jumpTarget(local) = %q, want the alias", got)
+	}
+	remote := domain.Server{Alias: "shared", Host: "10.0.0.8", User: "deploy", Port: 2222, ProxyJump: "bastion", ReadOnly: true}
+	want := []string{"-p", "2222", "deploy@10.0.0.8"}
+	if got := jumpTarget(remote, literalDestination(remote)); !reflect.DeepEqual(got, want) {
+		t.Errorf("jumpTarget(remote) = %q, want %q", got, want)
+	}
+}
+
+func TestVerboseArgs(t *testing.T) {
+	got := strings.Join(verboseArgs([]string{"ssh", "-L", "5432:localhost:5432", "db"}), " ")
+	if want := "ssh -vvv -L 5432:localhost:5432 db"; got != want {
+		t.Errorf("verboseArgs() = %q, want %q", got, want)
+	}
+}
+
+func TestControlMasterRejectsUnknownOperation(t *testing.T) {
+	s := &serverService{logger: zap.NewNop().Sugar()}
+	if _, err := s.ControlMaster("web", "forward"); err == nil {
+		t.Error("expected an error for an unsupported operation")
+	}
+}
+
+func TestAutoTunnelArgs(t *testing.T) {
+	tests := []struct {
+		name   string
+		server domain.Server
+		want   string
+	}{
+		{name: "none", server: domain.Server{Alias: "web"}, want: ""},
+		{
+			name:   "enabled",
+			server: domain.Server{Alias: "db", AutoTunnels: []string{"5432:localhost:5432", "8080:web:80"}},
+			want:   "-L 5432:localhost:5432 -L 8080:web:80",
+		},
+		{
+			name:   "disabled",
+			server: domain.Server{Alias: "db", AutoTunnels: []string{"5432:localhost:5432"}, AutoTunnelsDisabled: true},
+			want:   "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := strings.Join(autoTunnelArgs(tt.server), " "); got != tt.want {
+				t.Errorf("autoTunnelArgs() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSessionArgsDefaultIdentityFile(t *testing.T) {
+	repo := listRepo{servers: []domain.Server{
+		{Alias: "web"},
+		{Alias: "db", IdentityFiles: []string{"~/.ssh/db"}},
+	}}
+	s := &serverService{logger: zap.NewNop().Sugar(), serverRepository: repo, defaultIdentityFile: "~/.ssh/team"}
+	tests := []struct {
+		alias string
+		want  string
+	}{
+		{"web", "-i ~/.ssh/team web"},
+		{"db", "db"}, // the config's IdentityFile applies
+		{"unknown", "unknown"},
+	}
+	for _, tt := range tests {
+		if got := strings.Join(s.sessionArgs(tt.alias)[1:], " "); got != tt.want {
+			t.Errorf("sessionArgs(%q) = %q, want %q", tt.alias, got, tt.want)
+		}
+	}
+}
+
+func TestProbeUsesPingTarget(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = ln.Close() }()
+
+	s := &serverService{logger: zap.NewNop().Sugar(), pingTimeout: time.Second}
+	// The SSH address and the jump host are unreachable; only the ping target listens.
+	res := s.probe(domain.Server{Alias: "web", Host: "192.0.2.1", ProxyJump: "bastion", PingTarget: ln.Addr().String()})
+	if !res.Up || res.Via != "" {
+		t.Errorf("probe() = %+v, want up via a direct dial to the ping target", res)
+	}
+}
+
+func TestDialHonorsAddressFamily(t *testing.T) {
+	ln, err := net.Listen("tcp4", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = ln.Close() }()
+
+	s := &serverService{logger: zap.NewNop().Sugar(), pingTimeout: time.Second}
+	tests := []struct {
+		family, bind string
+		wantUp       bool
+	}{
+		{"", "", true},
+		{"any", "", true},
+		{"inet", "127.0.0.1", true},
+		// ssh -6 would not reach an IPv4-only address either.
+		{"inet6", "", false},
+		{"inet", "not a host name", false},
+	}
+	for _, tt := range tests {
+		if res := s.dial(ln.Addr().String(), tt.family, tt.bind); res.Up != tt.wantUp {
+			t.Errorf("dial(family %q, bind %q) = %+v, want up %v", tt.family, tt.bind, res, tt.wantUp)
+		}
+	}
+}
+
+func TestLiteralDestination(t *testing.T) {
+	tests := []struct {
+		name   string
+		server domain.Server
+		want   sshDestination
+	}{
+		{"host name", domain.Server{Alias: "web", Host: "10.0.0.5", Port: 2222}, sshDestination{host: "10.0.0.5", port: 2222}},
+		{"alias only", domain.Server{Alias: "web.internal"}, sshDestination{host: "web.internal", port: 22}},
+		{"blank host name", domain.Server{Alias: "web", Host: "  ", ProxyJump: "bastion"}, sshDestination{host: "web", port: 22, proxyJump: "bastion"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := literalDestination(tt.server); got != tt.want {
+				t.Errorf("literalDestination() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestProbeAliasOnlyHost(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = ln.Close() }()
+	port := ln.Addr().(*net.TCPAddr).Port
+
+	// Without ssh on the PATH the entry's own values are used; with no HostName, the alias is dialed.
+	t.Setenv("PATH", "")
+	s := &serverService{logger: zap.NewNop().Sugar(), pingTimeout: time.Second, resolveCache: make(map[string]resolvedDestination)}
+	if res := s.probe(domain.Server{Alias: "127.0.0.1", Port: port}); !res.Up {
+		t.Errorf("probe() of an alias-only host = %+v, want up", res)
+	}
+}
+
+func TestIsRetryableSSHError(t *testing.T) {
+	tests := []struct {
+		msg  string
+		want bool
+	}{
+		{"ssh: connect to host 10.0.0.5 port 22: Connection refused", true},
+		{"ssh: connect to host web port 22: Operation timed out", true},
+		{"kex_exchange_identification: read: Connection reset by peer", true},
+		{"user@10.0.0.5: Permission denied (publickey).", false},
+		{"Host key verification failed.", false},
+		{"ssh: Could not resolve hostname web: Name or service not known", false},
+	}
+	for _, tt := range tests {
+		if got := isRetryableSSHError(tt.msg); got != tt.want {
+			t.Errorf("isRetryableSSHError(%q) = %v, want %v", tt.msg, got, tt.want)
+		}
+	}
+}
+
+func TestRetryDelay(t *testing.T) {
+	tests := []struct {
+		attempt int
+		want    time.Duration
+	}{
+		{1, time.Second},
+		{2, 2 * time.Second},
+		{3, 4 * time.Second},
+		{10, maxRetryDelay},
+	}
+	for _, tt := range tests {
+		if got := retryDelay(time.Second, tt.attempt); got != tt.want {
+			t.Errorf("retryDelay(1s, %d) = %v, want %v", tt.attempt, got, tt.want)
+		}
+	}
+}
+
+func TestRunAttachedDoesNotWaitForStderrHolders(t *testing.T) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh not available")
+	}
+	tests := []struct {
+		name     string
+		exitCode int
+	}{
+		{name: "clean logout", exitCode: 0},
+		{name: "connection error", exitCode: 255},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// The background sleep inherits stderr, like a ProxyCommand kept alive by ControlPersist.
+			script := fmt.Sprintf("sleep 4 & echo 'connection refused' >&2; exit %d", tt.exitCode)
+			start := time.Now()
+			tail, err := runAttached(exec.Command("sh", "-c", script))
+			if elapsed := time.Since(start); elapsed > 3*time.Second {
+				t.Fatalf("runAttached took %v, want it to return soon after the shell exits", elapsed)
+			}
+			var exitErr *exec.ExitError
+			switch {
+			case tt.exitCode == 0 && err != nil:
+				t.Errorf("err = %v, want nil", err)
+			case tt.exitCode != 0 && (!errors.As(err, &exitErr) || exitErr.ExitCode() != tt.exitCode):
+				t.Errorf("err = %v, want exit status %d", err, tt.exitCode)
+			}
+			if !strings.Contains(tail, "connection refused") {
+				t.Errorf("stderr tail = %q, want the shell's message", tail)
+			}
+		})
+	}
+}
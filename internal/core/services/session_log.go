@@ -0,0 +1,110 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package services
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// unsafeLogNameChars are replaced in aliases used as log file names.
+var unsafeLogNameChars = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// sessionLoggingEnabled reports whether sessions to alias should be logged: the server's
+// own override when set, otherwise the session_logging config.
+func (s *serverService) sessionLoggingEnabled(alias string) bool {
+	servers, err := s.allServers()
+	if err != nil {
+		return s.sessionLogging
+	}
+	for _, srv := range servers {
+		if srv.Alias == alias && srv.SessionLogging != nil {
+			return *srv.SessionLogging
+		}
+	}
+	return s.sessionLogging
+}
+
+// sessionCommand builds the command for an SSH session. When logging is enabled for
+// alias it wraps argv in script(1) and also returns the log path; otherwise, or when
+// script(1) is unavailable, it runs argv directly and the path is empty.
+func (s *serverService) sessionCommand(alias string, argv []string) (*exec.Cmd, string) {
+	if !s.sessionLoggingEnabled(alias) {
+		return exec.Command(argv[0], argv[1:]...), ""
+	}
+
+	logPath, err := s.createSessionLog(alias)
+	if err != nil {
+		s.logger.Warnw("failed to create session log, connecting without it", "alias", alias, "error", err)
+		return exec.Command(argv[0], argv[1:]...), ""
+	}
+	cmd := sessionLogCommand(logPath, argv)
+	if cmd == nil {
+		s.logger.Warnw("script(1) not available, connecting without a session log", "alias", alias)
+		_ = os.Remove(logPath)
+		return exec.Command(argv[0], argv[1:]...), ""
+	}
+	s.logger.Infow("logging ssh session", "alias", alias, "path", logPath)
+	return cmd, logPath
+}
+
+// createSessionLog creates an empty, owner-only log file named <alias>-<timestamp>.log.
+func (s *serverService) createSessionLog(alias string) (string, error) {
+	if err := os.MkdirAll(s.sessionLogDir, 0o700); err != nil {
+		return "", fmt.Errorf("mkdir '%s': %w", s.sessionLogDir, err)
+	}
+	name := fmt.Sprintf("%s-%s.log", unsafeLogNameChars.ReplaceAllString(alias, "_"), time.Now().Format("20060102-150405"))
+	path := filepath.Join(s.sessionLogDir, name)
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o600)
+	if err != nil {
+		return "", err
+	}
+	return path, f.Close()
+}
+
+// lastSessionLogLine returns the last output line recorded in a session log, skipping
+// the header and footer script(1) adds.
+func lastSessionLogLine(path string) string {
+	f, err := os.Open(path)
+	if err != nil {
+		return ""
+	}
+	defer func() { _ = f.Close() }()
+
+	if info, err := f.Stat(); err == nil && info.Size() > stderrTailSize {
+		if _, err := f.Seek(-stderrTailSize, io.SeekEnd); err != nil {
+			return ""
+		}
+	}
+	data, err := io.ReadAll(f)
+	if err != nil {
+		return ""
+	}
+
+	var kept []string
+	for _, line := range strings.Split(string(data), "\n") {
+		if strings.HasPrefix(line, "Script started") || strings.HasPrefix(line, "Script done") {
+			continue
+		}
+		kept = append(kept, line)
+	}
+	return lastLine(strings.Join(kept, "\n"))
+}
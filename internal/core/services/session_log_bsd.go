@@ -0,0 +1,30 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build darwin || freebsd || netbsd || openbsd || dragonfly
+
+package services
+
+import "os/exec"
+
+// sessionLogCommand runs argv under BSD script(1) so the session keeps a real TTY while
+// its output is copied to logPath.
+func sessionLogCommand(logPath string, argv []string) *exec.Cmd {
+	script, err := exec.LookPath("script")
+	if err != nil {
+		return nil
+	}
+	args := append([]string{"-q", logPath}, argv...)
+	return exec.Command(script, args...)
+}
@@ -0,0 +1,36 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux
+
+package services
+
+import (
+	"os/exec"
+	"strings"
+)
+
+// sessionLogCommand runs argv under util-linux script(1) so the session keeps a real TTY
+// while its output is copied to logPath. -e returns the child's exit status.
+func sessionLogCommand(logPath string, argv []string) *exec.Cmd {
+	script, err := exec.LookPath("script")
+	if err != nil {
+		return nil
+	}
+	quoted := make([]string, len(argv))
+	for i, arg := range argv {
+		quoted[i] = "'" + strings.ReplaceAll(arg, "'", `'\''`) + "'"
+	}
+	return exec.Command(script, "-q", "-f", "-e", "-c", strings.Join(quoted, " "), logPath)
+}
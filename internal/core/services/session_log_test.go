@@ -0,0 +1,59 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package services
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+func TestSessionLogCommand(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("exercises util-linux script(1)")
+	}
+	if _, err := exec.LookPath("script"); err != nil {
+		t.Skip("script(1) not installed")
+	}
+
+	logPath := filepath.Join(t.TempDir(), "web-20250101-000000.log")
+	cmd := sessionLogCommand(logPath, []string{"sh", "-c", "echo 'Permission denied (publickey).' >&2; exit 255"})
+	if cmd == nil {
+		t.Fatal("sessionLogCommand() = nil, want command")
+	}
+
+	err := cmd.Run()
+	var exitErr *exec.ExitError
+	if !errors.As(err, &exitErr) || exitErr.ExitCode() != 255 {
+		t.Fatalf("Run() error = %v, want exit status 255", err)
+	}
+	if got := lastSessionLogLine(logPath); got != "Permission denied (publickey)." {
+		t.Errorf("lastSessionLogLine() = %q, want the ssh error", got)
+	}
+}
+
+func TestLastSessionLogLine(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "session.log")
+	content := "Script started on 2025-01-01 00:00:00+00:00 [COMMAND=\"ssh web\"]\r\nwelcome\r\nlogout\r\n\nScript done on 2025-01-01 00:01:00+00:00 [COMMAND_EXIT_CODE=\"0\"]\n"
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if got := lastSessionLogLine(path); got != "logout" {
+		t.Errorf("lastSessionLogLine() = %q, want %q", got, "logout")
+	}
+}
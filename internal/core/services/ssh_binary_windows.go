@@ -0,0 +1,41 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build windows
+
+package services
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+)
+
+// sshBinary returns the ssh client to execute. Windows ships OpenSSH under
+// %SystemRoot%\System32\OpenSSH, which is not always on PATH (e.g. when lazyssh
+// is started from a shell with a trimmed environment), so fall back to it.
+func sshBinary() string {
+	if p, err := exec.LookPath("ssh"); err == nil {
+		return p
+	}
+	root := os.Getenv("SystemRoot")
+	if root == "" {
+		root = `C:\Windows`
+	}
+	candidate := filepath.Join(root, "System32", "OpenSSH", "ssh.exe")
+	if _, err := os.Stat(candidate); err == nil {
+		return candidate
+	}
+	return "ssh"
+}